@@ -9,28 +9,53 @@
 //	infracore plan <description>
 //	infracore state
 //	infracore discover --provider <p> --action <a>
-//	infracore policy list | infracore policy check <skill>
-//	infracore compliance audit <framework>
+//	infracore policy list | infracore policy check <skill> | infracore policy validate <dir>
+//	infracore policy test <fixture_dir> [--policies=<dir>] [--now=<time>]
+//	infracore policy exceptions list [--expiring-in=14d]
+//	infracore policy report [--since=7d]
+//	infracore policy bundle keygen | sign <dir> --key=<file> | verify <path>
+//	infracore compliance audit <framework|--all> [--evidence=<file|dir>] [--live [--scan-images=<img,...>]] [--env=<env>] [--format=<fmt>] [--min-score=<pct>] [--fail-on-critical] [--since=90d]
+//	infracore compliance remediate <framework|--all> [--evidence=<file|dir>] [--live] [--env=<env>] [--user=<u>]
+//	infracore compliance trend <framework> [--env=<env>]
+//	infracore compliance waivers [--expiring-in=14d]
+//	infracore compliance frameworks
+//	infracore compliance matrix
 //	infracore drift detect
 //	infracore runbook list | infracore runbook run <name>
 //	infracore health check
 //	infracore config show
+//	infracore calendar list | infracore calendar check --env=<env>
+//	infracore breakglass activate --incident=<id> --reason=<text> [--env=<env>] [--duration=1h]
 package main
 
 import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"os"
+	osuser "os/user"
+	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/parth14193/ownbot/pkg/breakglass"
+	"github.com/parth14193/ownbot/pkg/calendar"
 	"github.com/parth14193/ownbot/pkg/compliance"
 	"github.com/parth14193/ownbot/pkg/config"
 	"github.com/parth14193/ownbot/pkg/core"
 	"github.com/parth14193/ownbot/pkg/drift"
+	"github.com/parth14193/ownbot/pkg/environment"
+	"github.com/parth14193/ownbot/pkg/executor"
 	"github.com/parth14193/ownbot/pkg/health"
+	"github.com/parth14193/ownbot/pkg/notify"
 	"github.com/parth14193/ownbot/pkg/output"
 	"github.com/parth14193/ownbot/pkg/planner"
 	"github.com/parth14193/ownbot/pkg/policy"
+	"github.com/parth14193/ownbot/pkg/policy/rego"
 	"github.com/parth14193/ownbot/pkg/rbac"
 	"github.com/parth14193/ownbot/pkg/runbook"
 	"github.com/parth14193/ownbot/pkg/safety"
@@ -53,27 +78,178 @@ func main() {
 		os.Exit(1)
 	}
 
+	cfg, err := config.LoadOrDefault(config.DefaultConfigPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+	classifier, err := cfg.Classifier()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Invalid environment tiers: %v\n", err)
+		os.Exit(1)
+	}
+
+	calendars := calendar.NewRegistry()
+	calendars.SetClassifier(classifier)
+	if err := calendars.LoadConfig(cfg.Calendars); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to load calendars: %v\n", err)
+		os.Exit(1)
+	}
+
 	renderer := output.NewRenderer()
 	safetyLayer := safety.NewLayer()
+	safetyLayer.SetClassifier(classifier)
+	safetyLayer.SetCalendars(calendars)
+	if len(cfg.ResourceGraph) > 0 {
+		graph := safety.NewGraph()
+		if err := graph.LoadConfig(cfg.ResourceGraph); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Invalid resource graph: %v\n", err)
+			os.Exit(1)
+		}
+		safetyLayer.SetGraph(graph)
+	}
 	planEngine := planner.NewEngine(registry)
 	stateManager := state.NewManager("cli-session")
-	cfg := config.DefaultConfig()
-	policyEngine := policy.NewEngine(policy.EnforcementWarn)
-	policyEngine.LoadBuiltins()
+	policyEngine, err := policy.NewEngineFromConfig(cfg.Policies, classifier)
+	if err != nil {
+		policyFailure("❌ Invalid policy configuration:\n%v\n", err)
+		policyEngine = policy.NewEngine(policy.EnforcementDeny)
+	}
+	exceptions := policy.NewExceptionRegistry()
+	exceptions.SetClassifier(classifier)
+	if cfg.Policies != nil {
+		if err := exceptions.LoadConfig(cfg.Policies.Exceptions); err != nil {
+			policyFailure("❌ Invalid policy exceptions: %v\n", err)
+		}
+	}
+	policyEngine.SetExceptions(exceptions)
+	for _, p := range calendar.Policies(calendars) {
+		policyEngine.Register(p)
+	}
+	if cfg.Policies != nil {
+		for _, dir := range cfg.Policies.Directories {
+			dir = expandHome(dir)
+			if _, err := os.Stat(dir); os.IsNotExist(err) {
+				continue
+			}
+			if _, err := policyEngine.LoadDir(dir); err != nil {
+				policyFailure("❌ Invalid policies:\n%v\n", err)
+			}
+			modules, err := rego.LoadDir(dir)
+			if err != nil {
+				policyFailure("❌ Invalid Rego modules:\n%v\n", err)
+				continue
+			}
+			if len(modules.Modules()) > 0 {
+				policyEngine.AddBackend(modules)
+			}
+		}
+		// Bundle tooling must keep working while a configured bundle is broken.
+		bundleCmd := len(os.Args) > 2 && os.Args[1] == "policy" && os.Args[2] == "bundle"
+		if err := loadPolicyBundles(policyEngine, cfg); err != nil && !bundleCmd {
+			policyFailure("❌ Invalid policy bundle:\n%v\n", err)
+		}
+	}
 	rbacEngine := rbac.NewEngine()
+	rbacEngine.SetClassifier(classifier)
+	if cfg.RBAC != nil {
+		rbacEngine.SetEnabled(cfg.RBAC.Enabled)
+		for user, role := range cfg.RBAC.Users {
+			rbacEngine.AddUser(user, rbac.Role(role), nil)
+		}
+	}
+	dispatcher := buildDispatcher(cfg)
+	ledgerKey, err := breakglass.LoadKey(cfg.BreakGlassKeyPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to load break-glass ledger key: %v\n", err)
+		os.Exit(1)
+	}
+	ledger, err := breakglass.OpenLedger(cfg.BreakGlassLedgerPath(), ledgerKey)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to open break-glass ledger: %v\n", err)
+		os.Exit(1)
+	}
+	decisions, err := policy.OpenDecisionLog(cfg.PolicyDecisionLogPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to open policy decision log: %v\n", err)
+		os.Exit(1)
+	}
+	breakGlass := breakglass.NewManager(ledger)
+	breakGlass.SetDispatcher(dispatcher)
+	breakGlass.SetCalendars(calendars)
+	if bc := cfg.BreakGlass; bc != nil && bc.Enabled {
+		breakGlass.SetDesignatedUsers(bc.Users...)
+		if bc.MaxDuration != "" {
+			d, err := time.ParseDuration(bc.MaxDuration)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Invalid break_glass.max_duration: %v\n", err)
+				os.Exit(1)
+			}
+			breakGlass.SetMaxDuration(d)
+		}
+	}
 	runbookEngine := runbook.NewEngine()
 	runbookEngine.LoadBuiltins()
 	healthChecker := health.NewChecker()
 	healthChecker.LoadBuiltins()
+	if err := healthChecker.LoadConfig(cfg.HealthProbes); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to load health probes: %v\n", err)
+		os.Exit(1)
+	}
+	skillGates, err := cfg.SkillGates()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Invalid gates: %v\n", err)
+		os.Exit(1)
+	}
+	for name, gates := range skillGates {
+		skill, err := registry.Get(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Invalid gates: %v\n", err)
+			os.Exit(1)
+		}
+		skill.Gates = append(skill.Gates, gates...)
+	}
 	auditor := compliance.NewAuditor()
 	auditor.LoadAll()
+	auditor.SetBreakGlassLedger(ledger)
+	waivers := compliance.NewWaiverRegistry()
+	waivers.SetClassifier(classifier)
+	auditor.SetWaivers(waivers)
+	if cfg.Compliance != nil {
+		if err := waivers.LoadConfig(cfg.Compliance.Waivers); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Invalid compliance waivers: %v\n", err)
+			os.Exit(1)
+		}
+		scoring, err := compliance.ScoringFromConfig(cfg.Compliance.Scoring)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Invalid compliance scoring: %v\n", err)
+			os.Exit(1)
+		}
+		auditor.SetScoring(scoring)
+		for _, path := range cfg.Compliance.Frameworks {
+			path = expandHome(path)
+			if _, err := os.Stat(path); os.IsNotExist(err) {
+				continue
+			}
+			if _, err := auditor.LoadFramework(path); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Failed to load compliance framework %s:\n%v\n", path, err)
+				os.Exit(1)
+			}
+		}
+	}
+	history, err := compliance.OpenHistory(cfg.ComplianceHistoryPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to open compliance history: %v\n", err)
+		os.Exit(1)
+	}
+	history.SetDispatcher(dispatcher)
 	driftDetector := drift.NewDetector()
 
 	switch os.Args[1] {
 	case "skills":
 		handleSkills(os.Args[2:], registry, renderer)
 	case "run":
-		handleRun(os.Args[2:], registry, renderer, safetyLayer, stateManager, policyEngine)
+		handleRun(os.Args[2:], registry, renderer, safetyLayer, stateManager, policyEngine, decisions, rbacEngine, breakGlass, healthChecker)
 	case "plan":
 		handlePlan(os.Args[2:], renderer, planEngine)
 	case "state":
@@ -81,9 +257,13 @@ func main() {
 	case "discover":
 		handleDiscover(os.Args[2:], registry, renderer)
 	case "policy":
-		handlePolicy(os.Args[2:], policyEngine, registry, renderer)
+		handlePolicy(os.Args[2:], policyEngine, decisions, registry, renderer, cfg, classifier)
 	case "compliance":
-		handleCompliance(os.Args[2:], auditor)
+		if len(os.Args) > 2 && os.Args[2] == "remediate" {
+			handleComplianceRemediate(os.Args[2:], auditor, registry, renderer, planEngine, safetyLayer, policyEngine, rbacEngine, breakGlass, healthChecker)
+			break
+		}
+		handleCompliance(os.Args[2:], auditor, history, registry, safetyLayer)
 	case "drift":
 		handleDrift(os.Args[2:], driftDetector)
 	case "runbook":
@@ -94,6 +274,10 @@ func main() {
 		handleConfig(os.Args[2:], cfg)
 	case "rbac":
 		handleRBAC(os.Args[2:], rbacEngine)
+	case "calendar":
+		handleCalendar(os.Args[2:], calendars)
+	case "breakglass":
+		handleBreakGlass(os.Args[2:], breakGlass)
 	case "version":
 		fmt.Printf("InfraCore Agent Framework v%s\n", version)
 	case "help", "--help", "-h":
@@ -105,6 +289,28 @@ func main() {
 	}
 }
 
+// policyFailure reports an invalid policy setup. It is fatal for commands
+// that evaluate policies; the others (version, config show, ...) only warn so
+// a broken configuration can still be inspected and fixed.
+func policyFailure(format string, err error) {
+	fmt.Fprintf(os.Stderr, format, err)
+	if evaluatesPolicy(os.Args[1:]) {
+		os.Exit(1)
+	}
+}
+
+// evaluatesPolicy reports whether the command line runs a command that
+// evaluates policies.
+func evaluatesPolicy(args []string) bool {
+	switch args[0] {
+	case "run", "policy":
+		return true
+	case "compliance":
+		return len(args) > 1 && args[1] == "remediate"
+	}
+	return false
+}
+
 func printUsage() {
 	fmt.Println(`
 ╔══════════════════════════════════════════════════════════╗
@@ -127,7 +333,19 @@ CORE COMMANDS:
 PLATFORM COMMANDS:
   policy list      List all registered policies
   policy check     Check policies against a skill
-  compliance audit Run compliance audit (CIS, SOC2, HIPAA)
+  policy validate  Validate declarative policy files and Rego modules in a directory
+  policy test      Run policy fixtures (*_test.yaml) and report diffs
+  policy exceptions List policy exceptions (--expiring-in=14d)
+  policy report    Summarise the policy decision log (--since=7d)
+  policy bundle    Generate keys, sign and verify policy bundles
+  compliance audit Run compliance audit (CIS, SOC2, HIPAA, PCI-DSS, or --all) against --evidence or --live skills
+                   (--format=text|json|junit|sarif|html; --min-score, --fail-on-critical exit non-zero;
+                   --since=90d sets the period of break-glass activity listed)
+  compliance remediate Plan skill runs that fix failed checks and evaluate them through policy and safety
+  compliance trend Show score history, newly failing and newly fixed checks
+  compliance waivers List compliance waivers (--expiring-in=14d)
+  compliance frameworks List built-in and custom frameworks
+  compliance matrix Show which framework controls each technical check supports
   drift detect     Detect infrastructure drift
   runbook list     List operational runbooks
   runbook run      Execute or simulate a runbook
@@ -135,6 +353,9 @@ PLATFORM COMMANDS:
   config show      Show current configuration
   config init      Generate sample config file
   rbac show        Show RBAC roles and users
+  calendar list    List change freezes and maintenance windows
+  calendar check   Check whether changes are allowed right now
+  breakglass       Emergency override (activate|status|log|end)
 
 OPTIONS:
   --provider=<p>      Filter by provider
@@ -142,12 +363,15 @@ OPTIONS:
   --param key=value   Set skill parameters
   --env=<env>         Set target environment
   --region=<r>        Set target region
+  --user=<u>          Act as user for RBAC (not authenticated; break-glass always uses the OS account)
 
 EXAMPLES:
   infracore skills list --provider=aws
   infracore run aws.ec2.list --param region=us-west-2
   infracore policy check k8s.deploy --env=production
-  infracore compliance audit CIS
+  infracore compliance audit CIS --evidence=./evidence
+  infracore compliance audit --all --evidence=./evidence --format=sarif > compliance.sarif
+  infracore compliance remediate CIS --evidence=./evidence --env=staging
   infracore drift detect
   infracore runbook run deployment-rollback
   infracore health check`)
@@ -241,7 +465,7 @@ func handleSkillsInfo(args []string, registry *skills.Registry, renderer *output
 
 // ─── Run ──────────────────────────────────────────────────────
 
-func handleRun(args []string, registry *skills.Registry, renderer *output.Renderer, safetyLayer *safety.Layer, stateManager *state.Manager, pe *policy.Engine) {
+func handleRun(args []string, registry *skills.Registry, renderer *output.Renderer, safetyLayer *safety.Layer, stateManager *state.Manager, pe *policy.Engine, decisions *policy.DecisionLog, rbacEngine *rbac.Engine, bg *breakglass.Manager, checker *health.Checker) {
 	if len(args) == 0 {
 		fmt.Println("Usage: infracore run <skill_name> [--param key=value ...]")
 		return
@@ -258,20 +482,14 @@ func handleRun(args []string, registry *skills.Registry, renderer *output.Render
 		env = e
 		stateManager.SetEnvironment(env)
 	}
+	user := extractFlag(args[1:], "--user")
+	if user == "" {
+		user = os.Getenv("USER")
+	}
 
-	// Policy check
-	policyResult := pe.Evaluate(skill, params, env)
-	if !policyResult.Passed {
-		fmt.Print(policyResult.Render())
+	if !evaluateSkill(skill, params, env, user, renderer, safetyLayer, pe, decisions, rbacEngine, bg, checker) {
 		return
 	}
-	if len(policyResult.Warnings) > 0 {
-		fmt.Print(policyResult.Render())
-	}
-
-	// Safety evaluation
-	report := safetyLayer.Evaluate(skill, params, env)
-	fmt.Print(renderer.RenderSafetyReport(report))
 
 	stateManager.LoadSkill(skillName)
 	stateManager.AddToAuditLog(skillName, "evaluate",
@@ -281,6 +499,78 @@ func handleRun(args []string, registry *skills.Registry, renderer *output.Render
 	fmt.Println(renderer.RenderSuccess(fmt.Sprintf("Skill '%s' evaluated in dry-run mode. Use --force to execute.", skillName)))
 }
 
+// evaluateSkill runs a skill invocation through access control, safety, policy
+// and pre-condition gates, printing each stage. It reports whether the skill
+// may proceed. Plan previews that never execute pass nil decisions, so the
+// policy outcome is not logged, and a break-glass Preview manager.
+func evaluateSkill(skill *core.Skill, params map[string]interface{}, env, user string, renderer *output.Renderer, safetyLayer *safety.Layer, pe *policy.Engine, decisions *policy.DecisionLog, rbacEngine *rbac.Engine, bg *breakglass.Manager, checker *health.Checker) bool {
+	// Break-glass sessions belong to OS accounts; acting as another user
+	// with --user never picks up their session.
+	bgUser := ""
+	if user == accountUser() {
+		bgUser = user
+	}
+
+	// Access control
+	allowed, reason := rbacEngine.CanExecute(user, skill, env)
+	if allowed, reason = bg.AuthorizeRBAC(allowed, reason, bgUser, skill, env); !allowed {
+		fmt.Println(renderer.RenderError(fmt.Errorf("%s", reason)))
+		return false
+	} else if reason != "" {
+		fmt.Println(renderer.RenderWarning(reason))
+	}
+
+	// Safety evaluation feeds the policy input, so it runs first.
+	report := safetyLayer.Evaluate(skill, params, env)
+
+	// Policy check
+	plan, manifests, err := loadPolicyDocuments(params)
+	if err != nil {
+		fmt.Println(renderer.RenderError(err))
+		return false
+	}
+	input := &policy.Input{Skill: skill, Params: params, Environment: env, User: user, Safety: report, Plan: plan, Manifests: manifests}
+	policyResult := pe.EvaluateInput(input)
+	if _, err := bg.ApplyPolicy(policyResult, bgUser, skill, env); err != nil {
+		fmt.Println(renderer.RenderError(fmt.Errorf("break-glass bypass refused: %w", err)))
+	}
+	// Logged after break-glass so the log shows what was enforced.
+	if decisions != nil {
+		if err := decisions.Record(input, policyResult); err != nil {
+			fmt.Println(renderer.RenderWarning(fmt.Sprintf("policy decision not logged: %v", err)))
+		}
+	}
+	if !policyResult.Passed {
+		fmt.Print(policyResult.Render())
+		return false
+	}
+	if len(policyResult.Warnings) > 0 || len(policyResult.Audited) > 0 {
+		fmt.Print(policyResult.Render())
+	}
+
+	fmt.Print(renderer.RenderSafetyReport(report))
+
+	// Pre-condition health gates; post gates run only on real execution.
+	gated := executor.NewGatedExecutor(executor.NewDryRunExecutor(), checker)
+	for _, gate := range skill.Gates {
+		if gate.Phase == core.GatePost {
+			within := gate.Within
+			if within == 0 {
+				within = executor.DefaultGateWithin
+			}
+			fmt.Printf("🚦 Post-gate: '%s' must be healthy within %s after execution or the change is rolled back\n", gate.Probe, within)
+			continue
+		}
+		g := gated.CheckGates(context.Background(), []core.SafetyGate{gate})[0]
+		if !g.Passed {
+			fmt.Println(renderer.RenderError(fmt.Errorf("pre-gate '%s' is %s: %s — execution blocked", gate.Probe, g.Status, g.Message)))
+			return false
+		}
+		fmt.Printf("🚦 Pre-gate: '%s' %s\n", gate.Probe, g.Status)
+	}
+	return true
+}
+
 // ─── Plan ─────────────────────────────────────────────────────
 
 func handlePlan(args []string, renderer *output.Renderer, planEngine *planner.Engine) {
@@ -337,9 +627,9 @@ func handleDiscover(args []string, registry *skills.Registry, renderer *output.R
 
 // ─── Policy ───────────────────────────────────────────────────
 
-func handlePolicy(args []string, pe *policy.Engine, registry *skills.Registry, renderer *output.Renderer) {
+func handlePolicy(args []string, pe *policy.Engine, decisions *policy.DecisionLog, registry *skills.Registry, renderer *output.Renderer, cfg *config.Config, classifier *environment.Classifier) {
 	if len(args) == 0 {
-		fmt.Println("Usage: infracore policy <list|check> [options]")
+		fmt.Println("Usage: infracore policy <list|check|validate|test|exceptions|report|bundle> [options]")
 		return
 	}
 	switch args[0] {
@@ -347,11 +637,123 @@ func handlePolicy(args []string, pe *policy.Engine, registry *skills.Registry, r
 		policies := pe.ListPolicies()
 		fmt.Printf("🛡️  POLICIES (%d registered)\n", len(policies))
 		for _, p := range policies {
-			fmt.Printf("  • %-25s [%s/%s] %s\n", p.Name, p.Enforcement, p.Severity, p.Description)
+			enforcement := string(p.Enforcement)
+			if pe.AuditMode(p.Name) {
+				enforcement = "audit:" + enforcement
+			}
+			fmt.Printf("  • %-25s [%s/%s] %s\n", p.Name, enforcement, p.Severity, p.Description)
+		}
+		for _, b := range pe.Backends() {
+			if r, ok := b.(*rego.Backend); ok {
+				for _, m := range r.Modules() {
+					fmt.Printf("  • %-25s [rego] %s\n", m.Package, m.File)
+				}
+			}
+		}
+	case "validate":
+		if len(args) < 2 {
+			fmt.Println("Usage: infracore policy validate <dir>")
+			return
+		}
+		if _, err := os.Stat(args[1]); err != nil {
+			fmt.Println(renderer.RenderError(err))
+			os.Exit(1)
+		}
+		loaded, err := policy.NewEngine(policy.EnforcementWarn).LoadDir(args[1])
+		modules, regoErr := rego.LoadDir(args[1])
+		if err = errors.Join(err, regoErr); err != nil {
+			fmt.Printf("❌ Policy validation failed:\n%v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ %d policies and %d Rego modules valid\n", len(loaded), len(modules.Modules()))
+		for _, p := range loaded {
+			fmt.Printf("  • %-25s %s\n", p.Name, p.Source)
+		}
+		for _, m := range modules.Modules() {
+			fmt.Printf("  • %-25s %s\n", m.Package, m.File)
+		}
+	case "test":
+		if len(args) < 2 {
+			fmt.Println("Usage: infracore policy test <fixture_dir> [--policies=<dir>] [--now=<RFC 3339 time>]")
+			return
+		}
+		policiesDir := extractFlag(args[2:], "--policies")
+		if policiesDir == "" {
+			policiesDir = args[1]
+		}
+		fixtures, err := policy.LoadFixtures(args[1])
+		if err != nil {
+			fmt.Printf("❌ Invalid fixtures:\n%v\n", err)
+			os.Exit(1)
+		}
+		// A hermetic engine: default built-ins and tiers plus the policies
+		// under test, so results do not depend on the local configuration.
+		engine := policy.NewEngine(policy.EnforcementWarn)
+		engine.LoadBuiltins()
+		if now := extractFlag(args[2:], "--now"); now != "" {
+			at, err := time.Parse(time.RFC3339, now)
+			if err != nil {
+				fmt.Println(renderer.RenderError(fmt.Errorf("invalid --now: %w", err)))
+				os.Exit(1)
+			}
+			engine.SetClock(func() time.Time { return at })
 		}
+		_, err = engine.LoadDir(policiesDir)
+		modules, regoErr := rego.LoadDir(policiesDir)
+		if err = errors.Join(err, regoErr); err != nil {
+			fmt.Printf("❌ Invalid policies:\n%v\n", err)
+			os.Exit(1)
+		}
+		if len(modules.Modules()) > 0 {
+			engine.AddBackend(modules)
+		}
+		results := engine.RunFixtures(fixtures, func(name string) *core.Skill {
+			if skill, err := registry.Get(name); err == nil {
+				return skill
+			}
+			return &core.Skill{Name: name}
+		})
+		fmt.Print(policy.RenderFixtureResults(results))
+		for _, r := range results {
+			if !r.Passed {
+				os.Exit(1)
+			}
+		}
+	case "exceptions":
+		if len(args) < 2 || args[1] != "list" {
+			fmt.Println("Usage: infracore policy exceptions list [--expiring-in=14d]")
+			return
+		}
+		registry := pe.Exceptions()
+		if registry == nil {
+			registry = policy.NewExceptionRegistry()
+		}
+		list := registry.List()
+		if s := extractFlag(args[2:], "--expiring-in"); s != "" {
+			within, err := parseWindow(s)
+			if err != nil {
+				fmt.Printf("❌ Invalid --expiring-in: %v\n", err)
+				return
+			}
+			list = registry.Expiring(within)
+		}
+		fmt.Print(registry.Render(list))
+	case "bundle":
+		handlePolicyBundle(args[1:], renderer, cfg)
+	case "report":
+		window := extractFlag(args[1:], "--since")
+		if window == "" {
+			window = "7d"
+		}
+		d, err := parseWindow(window)
+		if err != nil {
+			fmt.Printf("❌ Invalid --since: %v\n", err)
+			return
+		}
+		fmt.Print(decisions.Report(time.Now().Add(-d)).Render())
 	case "check":
 		if len(args) < 2 {
-			fmt.Println("Usage: infracore policy check <skill_name> [--env=<env>]")
+			fmt.Println("Usage: infracore policy check <skill_name> [--env=<env>] [plan_file=<plan.json>] [manifest_file=<path>]")
 			return
 		}
 		skill, err := registry.Get(args[1])
@@ -364,21 +766,344 @@ func handlePolicy(args []string, pe *policy.Engine, registry *skills.Registry, r
 			env = "staging"
 		}
 		params := parseParams(args[2:])
-		result := pe.Evaluate(skill, params, env)
+		plan, manifests, err := loadPolicyDocuments(params)
+		if err != nil {
+			fmt.Println(renderer.RenderError(err))
+			return
+		}
+		result := pe.EvaluateInput(&policy.Input{Skill: skill, Params: params, Environment: env, Plan: plan, Manifests: manifests})
 		fmt.Print(result.Render())
 	}
 }
 
+// loadPolicyDocuments loads the Terraform plan JSON and rendered Kubernetes
+// manifests named by the plan_file and manifest_file params, if any, so
+// policies can check each planned resource and object.
+func loadPolicyDocuments(params map[string]interface{}) (*policy.Plan, []*policy.K8sObject, error) {
+	var plan *policy.Plan
+	var manifests []*policy.K8sObject
+	var err error
+	if path, _ := params["plan_file"].(string); path != "" {
+		if plan, err = policy.LoadPlan(path); err != nil {
+			return nil, nil, err
+		}
+	}
+	if path, _ := params["manifest_file"].(string); path != "" {
+		if manifests, err = policy.LoadManifests(path); err != nil {
+			return nil, nil, err
+		}
+	}
+	return plan, manifests, nil
+}
+
+// trustedKeys parses the configured policy bundle signing keys.
+func trustedKeys(cfg *config.Config) ([]ed25519.PublicKey, error) {
+	var keys []ed25519.PublicKey
+	if cfg.Policies == nil {
+		return keys, nil
+	}
+	for i, s := range cfg.Policies.TrustedKeys {
+		key, err := policy.ParsePublicKey(s)
+		if err != nil {
+			return nil, fmt.Errorf("policies.trusted_keys[%d]: %w", i, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// loadPolicyBundles verifies and loads the configured policy bundles,
+// including their Rego modules. Any failure is fatal: a bundle that cannot be
+// trusted must not silently leave its guardrails out.
+func loadPolicyBundles(pe *policy.Engine, cfg *config.Config) error {
+	if len(cfg.Policies.Bundles) == 0 {
+		return nil
+	}
+	keys, err := trustedKeys(cfg)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("policies.trusted_keys: at least one key is required to load bundles")
+	}
+	lock, err := policy.OpenBundleLock(cfg.PolicyBundleLockPath())
+	if err != nil {
+		return err
+	}
+	for i, bc := range cfg.Policies.Bundles {
+		if bc == nil || bc.Path == "" {
+			return fmt.Errorf("policies.bundles[%d]: path is required", i)
+		}
+		if _, _, err := pe.LoadBundle(expandHome(bc.Path), policy.BundleTrust{Keys: keys, Pin: bc.Version, Lock: lock}, rego.CompileBundle); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func handlePolicyBundle(args []string, renderer *output.Renderer, cfg *config.Config) {
+	if len(args) == 0 {
+		fmt.Println("Usage: infracore policy bundle <keygen|sign <dir> --key=<file>|verify <path>>")
+		return
+	}
+	switch args[0] {
+	case "keygen":
+		pub, priv, err := ed25519.GenerateKey(nil)
+		if err != nil {
+			fmt.Println(renderer.RenderError(err))
+			os.Exit(1)
+		}
+		fmt.Println("🔑 Policy bundle signing key (keep the private key secret)")
+		fmt.Printf("  public:  %s\n", base64.StdEncoding.EncodeToString(pub))
+		fmt.Printf("  private: %s\n", base64.StdEncoding.EncodeToString(priv))
+	case "sign":
+		keyFile := extractFlag(args[1:], "--key")
+		if len(args) < 2 || keyFile == "" {
+			fmt.Println("Usage: infracore policy bundle sign <dir> --key=<private_key_file>")
+			return
+		}
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			fmt.Println(renderer.RenderError(err))
+			os.Exit(1)
+		}
+		key, err := policy.ParsePrivateKey(string(data))
+		if err != nil {
+			fmt.Println(renderer.RenderError(err))
+			os.Exit(1)
+		}
+		m, err := policy.SignBundle(args[1], key)
+		if err != nil {
+			fmt.Println(renderer.RenderError(err))
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Signed bundle %s %s (%d files)\n", m.Name, m.Version, len(m.Files))
+	case "verify":
+		if len(args) < 2 {
+			fmt.Println("Usage: infracore policy bundle verify <path>")
+			return
+		}
+		keys, err := trustedKeys(cfg)
+		if err != nil {
+			fmt.Println(renderer.RenderError(err))
+			os.Exit(1)
+		}
+		bundle, policies, err := policy.NewEngine(policy.EnforcementWarn).LoadBundle(args[1], policy.BundleTrust{Keys: keys}, rego.CompileBundle)
+		if err != nil {
+			fmt.Println(renderer.RenderError(err))
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Bundle %s %s verified (%d files, %d policies)\n", bundle.Manifest.Name, bundle.Manifest.Version, len(bundle.Files()), len(policies))
+		for _, p := range policies {
+			fmt.Printf("  • %-25s [%s/%s] %s\n", p.Name, p.Enforcement, p.Severity, p.Source)
+		}
+	default:
+		fmt.Printf("Unknown bundle command: %s\n", args[0])
+	}
+}
+
 // ─── Compliance ───────────────────────────────────────────────
 
-func handleCompliance(args []string, auditor *compliance.Auditor) {
+func handleCompliance(args []string, auditor *compliance.Auditor, history *compliance.History, registry *skills.Registry, safetyLayer *safety.Layer) {
+	if len(args) >= 1 && args[0] == "matrix" {
+		fmt.Print(auditor.Matrix().Render())
+		return
+	}
+	if len(args) >= 1 && args[0] == "frameworks" {
+		fmt.Print(auditor.RenderFrameworks())
+		return
+	}
+	if len(args) >= 1 && args[0] == "waivers" {
+		list := auditor.Waivers().List()
+		if s := extractFlag(args[1:], "--expiring-in"); s != "" {
+			within, err := parseWindow(s)
+			if err != nil {
+				fmt.Printf("❌ Invalid --expiring-in: %v\n", err)
+				return
+			}
+			list = auditor.Waivers().Expiring(within)
+		}
+		fmt.Print(auditor.Waivers().Render(list))
+		return
+	}
+	if len(args) >= 2 && args[0] == "trend" {
+		fw := compliance.Framework(strings.ToUpper(args[1]))
+		fmt.Print(history.Trend(fw, extractFlag(args[2:], "--env")).Render())
+		return
+	}
 	if len(args) < 2 || args[0] != "audit" {
-		fmt.Println("Usage: infracore compliance audit <CIS|SOC2|HIPAA>")
+		fmt.Println("Usage: infracore compliance audit <CIS|SOC2|HIPAA|PCI-DSS|--all> [--evidence=<file|dir>] [--live [--scan-images=<img,...>]] [--env=<env>] [--format=text|json|junit|sarif|html] [--min-score=<pct>] [--fail-on-critical] [--since=90d]")
+		fmt.Println("       infracore compliance remediate <framework|--all> [--evidence=<file|dir>] [--live] [--env=<env>] [--user=<u>]")
+		fmt.Println("       infracore compliance trend <framework> [--env=<env>]")
+		fmt.Println("       infracore compliance waivers [--expiring-in=14d]")
+		fmt.Println("       infracore compliance frameworks")
+		fmt.Println("       infracore compliance matrix")
 		return
 	}
-	fw := compliance.Framework(strings.ToUpper(args[1]))
-	report := auditor.RunAudit(fw)
-	fmt.Print(report.Render())
+	flags := args[1:]
+	configureAudit(flags, auditor, registry, safetyLayer)
+	if window := extractFlag(flags, "--since"); window != "" {
+		d, err := parseWindow(window)
+		if err != nil {
+			fmt.Printf("❌ Invalid --since: %v\n", err)
+			os.Exit(1)
+		}
+		auditor.SetPeriod(d)
+	}
+	if v := extractFlag(flags, "--min-score"); v != "" || hasFlag(flags, "--fail-on-critical") {
+		scoring := auditor.Scoring()
+		if v != "" {
+			minScore, err := strconv.ParseFloat(strings.TrimSuffix(v, "%"), 64)
+			if err != nil || minScore < 0 || minScore > 100 {
+				fmt.Printf("❌ Invalid --min-score '%s': want a percentage between 0 and 100\n", v)
+				os.Exit(1)
+			}
+			scoring.Thresholds.MinScore = minScore
+		}
+		if hasFlag(flags, "--fail-on-critical") {
+			scoring.Thresholds.FailOnCritical = true
+		}
+		auditor.SetScoring(scoring)
+	}
+	format, err := compliance.ParseFormat(extractFlag(flags, "--format"))
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	var out []byte
+	var reports []*compliance.Report
+	if hasFlag(flags, "--all") {
+		set := auditor.AuditAll()
+		reports = set.Reports
+		out, err = set.Export(format)
+	} else {
+		report := auditor.RunAudit(compliance.Framework(strings.ToUpper(args[1])))
+		reports = []*compliance.Report{report}
+		out, err = report.Export(format)
+	}
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	os.Stdout.Write(out)
+
+	// Unscored runs measured nothing, so they would only break up the trend.
+	for _, report := range reports {
+		if report.TotalChecks == 0 || report.Unscored {
+			continue
+		}
+		trend, err := history.Record(report)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Failed to record compliance history: %v\n", err)
+			continue
+		}
+		if trend.Regressed() {
+			fmt.Fprintf(os.Stderr, "📉 %s regressed since the previous audit (%+.1f, %d newly failing) — see: infracore compliance trend %s\n",
+				report.Framework, trend.ScoreDelta, len(trend.NewlyFailing), report.Framework)
+		}
+	}
+
+	breached := false
+	for _, report := range reports {
+		for _, breach := range report.Breaches {
+			fmt.Fprintf(os.Stderr, "🚫 %s: %s\n", report.Framework, breach)
+			breached = true
+		}
+	}
+	if breached {
+		os.Exit(1)
+	}
+}
+
+// configureAudit sets the audit environment and evidence collector from
+// flags and returns the environment. Live audits default to production.
+func configureAudit(flags []string, auditor *compliance.Auditor, registry *skills.Registry, safetyLayer *safety.Layer) string {
+	env := extractFlag(flags, "--env")
+	if env == "" && hasFlag(flags, "--live") {
+		env = "production"
+	}
+	auditor.SetEnvironment(env)
+	if path := extractFlag(flags, "--evidence"); path != "" {
+		auditor.SetCollector(compliance.NewFileCollector(expandHome(path)))
+	} else if hasFlag(flags, "--live") {
+		exec := executor.NewCLIExecutor(safetyLayer, false)
+		sources := compliance.DefaultSkillSources()
+		if images := extractFlag(flags, "--scan-images"); images != "" {
+			for _, image := range strings.Split(images, ",") {
+				sources = append(sources, compliance.ScanSource(strings.TrimSpace(image)))
+			}
+		}
+		auditor.SetCollector(compliance.NewSkillCollector(registry, exec, env, sources...))
+	}
+	return env
+}
+
+// handleComplianceRemediate audits, turns the fixes for failed checks into a
+// plan and evaluates each step like 'infracore run': RBAC, safety, policy and
+// gates, in dry-run mode. Plan steps are previews, so their policy outcomes
+// are not written to the decision log.
+func handleComplianceRemediate(args []string, auditor *compliance.Auditor, registry *skills.Registry, renderer *output.Renderer, planEngine *planner.Engine, safetyLayer *safety.Layer, pe *policy.Engine, rbacEngine *rbac.Engine, bg *breakglass.Manager, checker *health.Checker) {
+	if len(args) < 2 {
+		fmt.Println("Usage: infracore compliance remediate <framework|--all> [--evidence=<file|dir>] [--live] [--env=<env>] [--user=<u>]")
+		return
+	}
+	flags := args[1:]
+	env := configureAudit(flags, auditor, registry, safetyLayer)
+	var reports []*compliance.Report
+	if hasFlag(flags, "--all") {
+		reports = auditor.AuditAll().Reports
+	} else {
+		reports = []*compliance.Report{auditor.RunAudit(compliance.Framework(strings.ToUpper(args[1])))}
+	}
+	user := extractFlag(flags, "--user")
+	if user == "" {
+		user = os.Getenv("USER")
+	}
+
+	plan, err := compliance.RemediationPlan(planEngine, reports...)
+	if err != nil {
+		fmt.Println(renderer.RenderError(fmt.Errorf("invalid remediation:\n%w", err)))
+		os.Exit(1)
+	}
+	if len(plan.Steps) == 0 {
+		fmt.Println("✅ No automated remediations: no failed check has a fix.")
+	} else {
+		for _, err := range planEngine.Validate(plan) {
+			fmt.Println(renderer.RenderWarning(err.Error()))
+		}
+		fmt.Print(renderer.RenderPlan(plan))
+
+		// The steps only run in dry-run mode here, so break-glass bypasses
+		// are shown but neither recorded nor alerted.
+		preview := bg.Preview()
+		blocked := 0
+		for _, step := range plan.Steps {
+			skill, err := registry.Get(step.SkillName)
+			if err != nil {
+				fmt.Println(renderer.RenderError(err))
+				blocked++
+				continue
+			}
+			fmt.Printf("\n▶ Step %d: %s\n", step.StepNumber, step.Description)
+			if !evaluateSkill(skill, step.Params, env, user, renderer, safetyLayer, pe, nil, rbacEngine, preview, checker) {
+				blocked++
+			}
+		}
+		fmt.Println()
+		fmt.Println(renderer.RenderSuccess(fmt.Sprintf("%d of %d remediation step(s) evaluated in dry-run mode (%d blocked). Confirm and apply each with 'infracore run'.",
+			len(plan.Steps)-blocked, len(plan.Steps), blocked)))
+	}
+
+	if manual := compliance.ManualRemediations(reports...); len(manual) > 0 {
+		fmt.Println()
+		fmt.Println("🛠  MANUAL REMEDIATION")
+		for _, c := range manual {
+			fmt.Printf("  • %s — %s\n", c.ID, c.Title)
+			if c.Remediation != "" {
+				fmt.Printf("    %s\n", c.Remediation)
+			}
+		}
+	}
 }
 
 // ─── Drift ────────────────────────────────────────────────────
@@ -483,8 +1208,163 @@ func handleRBAC(args []string, engine *rbac.Engine) {
 	fmt.Print(engine.Render())
 }
 
+// ─── Calendar ─────────────────────────────────────────────────
+
+func handleCalendar(args []string, calendars *calendar.Registry) {
+	if len(args) == 0 {
+		fmt.Println("Usage: infracore calendar <list|check> [--env=<env>] [--team=<team>] [--at=<RFC3339>]")
+		return
+	}
+	switch args[0] {
+	case "list":
+		fmt.Print(calendars.Render())
+	case "check":
+		env := extractFlag(args[1:], "--env")
+		if env == "" {
+			env = "staging"
+		}
+		at := calendars.Now()
+		if s := extractFlag(args[1:], "--at"); s != "" {
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				fmt.Printf("❌ Invalid --at: %v\n", err)
+				return
+			}
+			at = t
+		}
+		d := calendars.Check(env, extractFlag(args[1:], "--team"), at)
+		switch {
+		case d.Overridden:
+			fmt.Printf("🔓 %s — overridden by %s until %s\n", d.Reason, d.Override.User, d.Override.Expires.Format(time.RFC3339))
+		case !d.Allowed:
+			fmt.Printf("❄️  BLOCKED: %s\n", d.Reason)
+		case d.Escalate:
+			fmt.Printf("⚠️  ESCALATED: %s\n", d.Reason)
+		default:
+			fmt.Printf("✅ Changes allowed in %s at %s\n", env, at.Format(time.RFC3339))
+		}
+	}
+}
+
+// ─── Break-glass ──────────────────────────────────────────────
+
+func handleBreakGlass(args []string, bg *breakglass.Manager) {
+	if len(args) == 0 {
+		fmt.Println("Usage: infracore breakglass <activate|status|log|end> [--incident=<id>] [--reason=<text>] [--env=<env>] [--duration=1h]")
+		return
+	}
+	// Break-glass acts as the OS account running the command, which --user
+	// and $USER cannot change.
+	user := accountUser()
+	if user == "" {
+		fmt.Println("❌ Cannot determine the OS account for break-glass")
+		os.Exit(1)
+	}
+	if u := extractFlag(args[1:], "--user"); u != "" && u != user {
+		fmt.Printf("❌ Break-glass acts as the OS account '%s'; --user cannot select another identity\n", user)
+		os.Exit(1)
+	}
+	switch args[0] {
+	case "activate":
+		duration := time.Hour
+		if s := extractFlag(args[1:], "--duration"); s != "" {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				fmt.Printf("❌ Invalid --duration: %v\n", err)
+				return
+			}
+			duration = d
+		}
+		s, err := bg.Activate(user, extractFlag(args[1:], "--reason"), extractFlag(args[1:], "--incident"),
+			extractFlag(args[1:], "--env"), duration)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("🔓 Break-glass session %s active for %s until %s\n", s.ID, s.User, s.ExpiresAt.Format(time.RFC3339))
+	case "status":
+		if s := bg.ActiveSession(user, extractFlag(args[1:], "--env")); s != nil {
+			fmt.Printf("🔓 %s: session %s (%s) active until %s — %s\n", s.User, s.ID, s.IncidentID, s.ExpiresAt.Format(time.RFC3339), s.Justification)
+		} else {
+			fmt.Printf("🔒 No active break-glass session for %s\n", user)
+		}
+	case "log", "verify":
+		fmt.Print(bg.Ledger().Render())
+		if err := bg.Ledger().Verify(); err != nil {
+			os.Exit(1)
+		}
+	case "end":
+		if err := bg.Deactivate(user); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+		fmt.Printf("🔒 Break-glass session ended for %s\n", user)
+	}
+}
+
 // ─── Helpers ──────────────────────────────────────────────────
 
+// accountUser returns the name of the OS account running the command, or ""
+// if it cannot be determined. Unlike --user and $USER the caller cannot set
+// it, so break-glass uses it as the identity.
+func accountUser() string {
+	u, err := osuser.Current()
+	if err != nil {
+		return ""
+	}
+	return u.Username
+}
+
+// expandHome expands a leading ~/ to the user's home directory.
+func expandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	}
+	return path
+}
+
+// buildDispatcher creates a notification dispatcher from the configured channels.
+func buildDispatcher(cfg *config.Config) *notify.Dispatcher {
+	d := notify.NewDispatcher()
+	if cfg.Notifications == nil || !cfg.Notifications.Enabled {
+		return d
+	}
+	for _, ch := range cfg.Notifications.Channels {
+		switch ch.Type {
+		case "console":
+			d.AddNotifier(notify.NewConsoleNotifier())
+		case "slack":
+			d.AddNotifier(notify.NewSlackNotifier(ch.WebhookURL, ch.Channel))
+		case "webhook":
+			d.AddNotifier(notify.NewWebhookNotifier(ch.WebhookURL, nil))
+		}
+	}
+	return d
+}
+
+// parseWindow parses a time window such as "14d", "36h" or "90m".
+func parseWindow(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid number of days '%s'", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
+
+func hasFlag(args []string, flag string) bool {
+	for _, arg := range args {
+		if arg == flag {
+			return true
+		}
+	}
+	return false
+}
+
 func extractFlag(args []string, flag string) string {
 	prefix := flag + "="
 	for _, arg := range args {
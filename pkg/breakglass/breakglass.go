@@ -0,0 +1,341 @@
+// Package breakglass provides a time-boxed emergency override that lets
+// designated users bypass denying policies and RBAC limits during incidents,
+// with mandatory justification, forced alerts and a tamper-evident audit trail.
+package breakglass
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/parth14193/ownbot/pkg/calendar"
+	"github.com/parth14193/ownbot/pkg/core"
+	"github.com/parth14193/ownbot/pkg/notify"
+	"github.com/parth14193/ownbot/pkg/policy"
+)
+
+// DefaultMaxDuration is the longest a break-glass session may last unless configured.
+const DefaultMaxDuration = 4 * time.Hour
+
+// minJustificationLength rejects placeholder justifications like "fix".
+const minJustificationLength = 15
+
+// Session is an active break-glass grant.
+type Session struct {
+	ID            string    `json:"id"`
+	User          string    `json:"user"`
+	IncidentID    string    `json:"incident_id"`
+	Justification string    `json:"justification"`
+	Environment   string    `json:"environment,omitempty"` // empty = all environments
+	StartedAt     time.Time `json:"started_at"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
+
+// Active reports whether the session is valid at t.
+func (s *Session) Active(at time.Time) bool {
+	return !at.Before(s.StartedAt) && at.Before(s.ExpiresAt)
+}
+
+// Manager grants break-glass sessions and applies them to policy and RBAC decisions.
+type Manager struct {
+	mu          sync.Mutex
+	designated  map[string]bool
+	maxDuration time.Duration
+	ledger      *Ledger
+	dispatcher  *notify.Dispatcher
+	calendars   *calendar.Registry
+	now         func() time.Time
+	preview     bool
+}
+
+// NewManager creates a manager that records to the given ledger.
+func NewManager(ledger *Ledger) *Manager {
+	return &Manager{
+		designated:  make(map[string]bool),
+		maxDuration: DefaultMaxDuration,
+		ledger:      ledger,
+		now:         time.Now,
+	}
+}
+
+// SetDesignatedUsers replaces the set of users allowed to break glass.
+func (m *Manager) SetDesignatedUsers(users ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.designated = make(map[string]bool, len(users))
+	for _, u := range users {
+		m.designated[u] = true
+	}
+}
+
+// SetMaxDuration caps how long a session may last.
+func (m *Manager) SetMaxDuration(d time.Duration) { m.maxDuration = d }
+
+// SetDispatcher sets the dispatcher used to alert every channel on break-glass use.
+func (m *Manager) SetDispatcher(d *notify.Dispatcher) { m.dispatcher = d }
+
+// SetCalendars records activations as change freeze overrides.
+func (m *Manager) SetCalendars(c *calendar.Registry) { m.calendars = c }
+
+// SetClock replaces the time source, mainly for tests.
+func (m *Manager) SetClock(now func() time.Time) { m.now = now }
+
+// Preview returns a manager that applies the same sessions but records and
+// alerts nothing, for evaluating plans that never execute.
+func (m *Manager) Preview() *Manager {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return &Manager{
+		designated:  m.designated,
+		maxDuration: m.maxDuration,
+		ledger:      m.ledger,
+		calendars:   m.calendars,
+		now:         m.now,
+		preview:     true,
+	}
+}
+
+// Ledger returns the audit ledger.
+func (m *Manager) Ledger() *Ledger { return m.ledger }
+
+// Activate opens a break-glass session for user. It fails unless the user is
+// designated, a justification and incident ID are given, and the duration
+// is within the configured maximum.
+func (m *Manager) Activate(user, justification, incidentID, env string, duration time.Duration) (*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	var reason string
+	switch {
+	case !m.designated[user]:
+		reason = fmt.Sprintf("user '%s' is not designated for break-glass access", user)
+	case strings.TrimSpace(incidentID) == "":
+		reason = "an incident ID is required"
+	case len(strings.TrimSpace(justification)) < minJustificationLength:
+		reason = fmt.Sprintf("justification must be at least %d characters", minJustificationLength)
+	case duration <= 0 || duration > m.maxDuration:
+		reason = fmt.Sprintf("duration must be between 0 and %s", m.maxDuration)
+	}
+	if reason != "" {
+		denied := fmt.Errorf("break-glass denied: %s", reason)
+		if _, err := m.ledger.Append(Entry{
+			Timestamp: now, Event: EventDeniedRequest, User: user, IncidentID: incidentID,
+			Justification: justification, Environment: env, Details: reason,
+		}); err != nil {
+			return nil, errors.Join(denied, fmt.Errorf("failed to record denied request: %w", err))
+		}
+		return nil, denied
+	}
+
+	s := &Session{
+		ID:            fmt.Sprintf("bg-%s-%d-%d", user, now.Unix(), len(m.ledger.Entries())+1),
+		User:          user,
+		IncidentID:    incidentID,
+		Justification: justification,
+		Environment:   env,
+		StartedAt:     now,
+		ExpiresAt:     now.Add(duration),
+	}
+	if _, err := m.ledger.Append(Entry{
+		Timestamp: now, Event: EventActivated, SessionID: s.ID, User: user, IncidentID: incidentID,
+		Justification: justification, Environment: env, ExpiresAt: s.ExpiresAt,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to record break-glass activation: %w", err)
+	}
+
+	if m.calendars != nil {
+		m.calendars.RecordOverride(calendar.Override{
+			Environment: env,
+			User:        user,
+			Reason:      fmt.Sprintf("break-glass %s: %s", incidentID, justification),
+			Expires:     s.ExpiresAt,
+		})
+	}
+	m.alert(s, "", fmt.Sprintf("🚨 BREAK-GLASS ACTIVATED by %s for %s (until %s): %s",
+		user, incidentID, s.ExpiresAt.Format(time.RFC3339), justification))
+	return s, nil
+}
+
+// Deactivate ends every active session held by user.
+func (m *Manager) Deactivate(user string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sessions := m.activeSessions(user, "")
+	if len(sessions) == 0 {
+		return fmt.Errorf("no active break-glass session for %s", user)
+	}
+	for _, s := range sessions {
+		if _, err := m.ledger.Append(Entry{
+			Timestamp: m.now(), Event: EventDeactivated, SessionID: s.ID, User: user, IncidentID: s.IncidentID,
+		}); err != nil {
+			return fmt.Errorf("failed to record break-glass deactivation: %w", err)
+		}
+	}
+	return nil
+}
+
+// ActiveSession returns the user's active session covering env, if any.
+// Sessions are reconstructed from the ledger so they survive restarts.
+func (m *Manager) ActiveSession(user, env string) *Session {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.activeSession(user, env)
+}
+
+// activeSession returns the user's active session covering env that expires
+// last, or nil.
+func (m *Manager) activeSession(user, env string) *Session {
+	var found *Session
+	for _, s := range m.activeSessions(user, env) {
+		if found == nil || s.ExpiresAt.After(found.ExpiresAt) {
+			found = s
+		}
+	}
+	return found
+}
+
+// activeSessions returns the user's active sessions covering env, ordered by
+// start time.
+func (m *Manager) activeSessions(user, env string) []*Session {
+	sessions := make(map[string]*Session)
+	for _, e := range m.ledger.Entries() {
+		switch e.Event {
+		case EventActivated:
+			sessions[e.SessionID] = &Session{
+				ID: e.SessionID, User: e.User, IncidentID: e.IncidentID, Justification: e.Justification,
+				Environment: e.Environment, StartedAt: e.Timestamp, ExpiresAt: e.ExpiresAt,
+			}
+		case EventDeactivated:
+			delete(sessions, e.SessionID)
+		}
+	}
+
+	now := m.now()
+	var active []*Session
+	for _, s := range sessions {
+		if s.User != user || !s.Active(now) || !m.designated[user] {
+			continue
+		}
+		if env != "" && s.Environment != "" && !strings.EqualFold(s.Environment, env) {
+			continue
+		}
+		active = append(active, s)
+	}
+	sort.Slice(active, func(i, j int) bool { return active[i].StartedAt.Before(active[j].StartedAt) })
+	return active
+}
+
+// ApplyPolicy downgrades denying violations to warnings when user holds an
+// active session. Each bypass is recorded and alerted. It returns true if
+// the result was overridden. The bypass fails closed: if it cannot be
+// recorded, the result stays denied and the error is returned.
+func (m *Manager) ApplyPolicy(result *policy.EvaluationResult, user string, skill *core.Skill, env string) (bool, error) {
+	if result.Passed || !result.Denied {
+		return false, nil
+	}
+	s := m.ActiveSession(user, env)
+	if s == nil {
+		return false, nil
+	}
+
+	var names []string
+	for _, v := range result.Violations {
+		names = append(names, v.PolicyName)
+	}
+	if err := m.record(s, EventPolicyBypass, skill.Name, env, "bypassed policies: "+strings.Join(names, ", ")); err != nil {
+		return false, err
+	}
+	for _, v := range result.Violations {
+		v.Enforcement = policy.EnforcementWarn
+		v.Reason = fmt.Sprintf("[BREAK-GLASS %s] %s", s.IncidentID, v.Reason)
+		result.Warnings = append(result.Warnings, v)
+	}
+	result.Violations = []policy.Violation{}
+	result.Passed = true
+	result.Denied = false
+	return true, nil
+}
+
+// AuthorizeRBAC overrides an RBAC denial when user holds an active session.
+// Like ApplyPolicy it fails closed when the bypass cannot be recorded.
+func (m *Manager) AuthorizeRBAC(allowed bool, reason, user string, skill *core.Skill, env string) (bool, string) {
+	if allowed {
+		return true, reason
+	}
+	s := m.ActiveSession(user, env)
+	if s == nil {
+		return false, reason
+	}
+	if err := m.record(s, EventRBACBypass, skill.Name, env, "bypassed RBAC: "+reason); err != nil {
+		return false, fmt.Sprintf("%s (break-glass bypass refused: %v)", reason, err)
+	}
+	return true, fmt.Sprintf("[BREAK-GLASS %s] %s", s.IncidentID, reason)
+}
+
+// record writes a bypass to the ledger and alerts on it. Nothing is alerted
+// if the ledger write fails, and nothing is recorded in preview.
+func (m *Manager) record(s *Session, event EventType, skillName, env, details string) error {
+	if m.preview {
+		return nil
+	}
+	if _, err := m.ledger.Append(Entry{
+		Timestamp: m.now(), Event: event, SessionID: s.ID, User: s.User, IncidentID: s.IncidentID,
+		Justification: s.Justification, Environment: env, SkillName: skillName, Details: details,
+	}); err != nil {
+		return fmt.Errorf("failed to record break-glass %s: %w", event, err)
+	}
+	m.alert(s, skillName, fmt.Sprintf("🚨 BREAK-GLASS %s by %s (%s): %s", event, s.User, s.IncidentID, details))
+	return nil
+}
+
+// alert sends to every notification channel, ignoring event filters.
+func (m *Manager) alert(s *Session, skillName, message string) {
+	if m.dispatcher == nil {
+		return
+	}
+	m.dispatcher.DispatchAll(&notify.Event{
+		SkillName:   skillName,
+		Status:      core.StatusPending,
+		Environment: s.Environment,
+		RiskLevel:   core.RiskCritical,
+		Message:     message,
+		Timestamp:   m.now(),
+		Details: map[string]interface{}{
+			"break_glass":   true,
+			"session_id":    s.ID,
+			"user":          s.User,
+			"incident_id":   s.IncidentID,
+			"justification": s.Justification,
+			"expires_at":    s.ExpiresAt,
+		},
+	})
+}
+
+// Render formats the ledger for display.
+func (l *Ledger) Render() string {
+	var b strings.Builder
+	entries := l.Entries()
+	b.WriteString(fmt.Sprintf("🔓 BREAK-GLASS LEDGER (%d entries)\n", len(entries)))
+	b.WriteString("─────────────────────────────────────────\n")
+	if err := l.Verify(); err != nil {
+		b.WriteString(fmt.Sprintf("❌ INTEGRITY CHECK FAILED: %s\n", err))
+	} else {
+		b.WriteString("✅ Hash chain verified\n")
+	}
+	for _, e := range entries {
+		b.WriteString(fmt.Sprintf("  #%-3d %s %-15s %-10s %s", e.Sequence, e.Timestamp.Format(time.RFC3339), e.Event, e.User, e.IncidentID))
+		if e.SkillName != "" {
+			b.WriteString(fmt.Sprintf(" skill=%s", e.SkillName))
+		}
+		if e.Details != "" {
+			b.WriteString(fmt.Sprintf(" — %s", e.Details))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,316 @@
+package breakglass_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parth14193/ownbot/pkg/breakglass"
+	"github.com/parth14193/ownbot/pkg/core"
+	"github.com/parth14193/ownbot/pkg/notify"
+	"github.com/parth14193/ownbot/pkg/policy"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+type recordingNotifier struct{ events []*notify.Event }
+
+func (r *recordingNotifier) Name() string { return "recorder" }
+
+func (r *recordingNotifier) Send(e *notify.Event) error {
+	r.events = append(r.events, e)
+	return nil
+}
+
+func newManager(t *testing.T, now time.Time) (*breakglass.Manager, *recordingNotifier) {
+	t.Helper()
+	rec := &recordingNotifier{}
+	d := notify.NewDispatcher()
+	d.SetFilters(false, false, false) // break-glass alerts must bypass filters
+	d.AddNotifier(rec)
+
+	m := breakglass.NewManager(breakglass.NewLedger(testKey))
+	m.SetDesignatedUsers("alice")
+	m.SetMaxDuration(2 * time.Hour)
+	m.SetDispatcher(d)
+	m.SetClock(func() time.Time { return now })
+	return m, rec
+}
+
+func TestActivateValidation(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	m, rec := newManager(t, now)
+
+	cases := []struct {
+		user, reason, incident string
+		duration               time.Duration
+	}{
+		{"mallory", "database primary is down", "INC-1", time.Hour},
+		{"alice", "database primary is down", "", time.Hour},
+		{"alice", "fix", "INC-1", time.Hour},
+		{"alice", "database primary is down", "INC-1", 3 * time.Hour},
+	}
+	for _, c := range cases {
+		if _, err := m.Activate(c.user, c.reason, c.incident, "production", c.duration); err == nil {
+			t.Errorf("expected activation to be denied for %+v", c)
+		}
+	}
+
+	s, err := m.Activate("alice", "database primary is down", "INC-1", "production", time.Hour)
+	if err != nil {
+		t.Fatalf("Activate: %v", err)
+	}
+	if !s.ExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("unexpected expiry %s", s.ExpiresAt)
+	}
+	if len(rec.events) != 1 {
+		t.Fatalf("expected activation alert despite filters, got %d events", len(rec.events))
+	}
+
+	entries := m.Ledger().Entries()
+	if len(entries) != 5 || entries[0].Event != breakglass.EventDeniedRequest || entries[4].Event != breakglass.EventActivated {
+		t.Errorf("expected 4 denied requests and 1 activation in ledger, got %+v", entries)
+	}
+}
+
+func TestSessionExpiry(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	m, _ := newManager(t, now)
+	if _, err := m.Activate("alice", "database primary is down", "INC-1", "production", time.Hour); err != nil {
+		t.Fatalf("Activate: %v", err)
+	}
+
+	if m.ActiveSession("alice", "production") == nil {
+		t.Error("expected an active session")
+	}
+	if m.ActiveSession("alice", "staging") != nil {
+		t.Error("session scoped to production should not cover staging")
+	}
+	m.SetClock(func() time.Time { return now.Add(61 * time.Minute) })
+	if m.ActiveSession("alice", "production") != nil {
+		t.Error("session should expire")
+	}
+}
+
+func TestPolicyAndRBACBypass(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	m, rec := newManager(t, now)
+
+	pe := policy.NewEngine(policy.EnforcementDeny)
+	pe.Register(&policy.Policy{
+		Name: "always_deny", Enforcement: policy.EnforcementDeny, Severity: policy.SeverityCritical,
+		CheckFunc: func(*core.Skill, map[string]interface{}, string) (bool, string) { return true, "denied" },
+	})
+	skill := &core.Skill{Name: "aws.rds.failover", RiskLevel: core.RiskHigh}
+
+	result := pe.Evaluate(skill, nil, "production")
+	if bypassed, _ := m.ApplyPolicy(result, "alice", skill, "production"); bypassed || result.Passed {
+		t.Fatal("policy should not be bypassed without an active session")
+	}
+
+	if _, err := m.Activate("alice", "database primary is down", "INC-7", "production", time.Hour); err != nil {
+		t.Fatalf("Activate: %v", err)
+	}
+	if bypassed, err := m.ApplyPolicy(result, "alice", skill, "production"); !bypassed || err != nil {
+		t.Fatal("expected policy bypass")
+	}
+	if !result.Passed || len(result.Violations) != 0 || len(result.Warnings) != 1 {
+		t.Errorf("expected violation downgraded to warning, got %+v", result)
+	}
+	if !strings.Contains(result.Warnings[0].Reason, "INC-7") {
+		t.Errorf("warning should reference the incident, got %q", result.Warnings[0].Reason)
+	}
+
+	allowed, _ := m.AuthorizeRBAC(false, "Role 'viewer' cannot execute HIGH-risk operations", "alice", skill, "production")
+	if !allowed {
+		t.Error("expected RBAC bypass")
+	}
+	if allowed, _ := m.AuthorizeRBAC(false, "denied", "bob", skill, "production"); allowed {
+		t.Error("users without a session must not bypass RBAC")
+	}
+
+	var events []breakglass.EventType
+	for _, e := range m.Ledger().Entries() {
+		events = append(events, e.Event)
+	}
+	want := []breakglass.EventType{breakglass.EventActivated, breakglass.EventPolicyBypass, breakglass.EventRBACBypass}
+	if len(events) != len(want) {
+		t.Fatalf("expected ledger events %v, got %v", want, events)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Errorf("ledger event %d = %s, want %s", i, events[i], want[i])
+		}
+	}
+	if len(rec.events) != 3 {
+		t.Errorf("expected an alert for activation and each bypass, got %d", len(rec.events))
+	}
+}
+
+func TestPreviewRecordsNothing(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	m, rec := newManager(t, now)
+	if _, err := m.Activate("alice", "database primary is down", "INC-8", "production", time.Hour); err != nil {
+		t.Fatalf("Activate: %v", err)
+	}
+
+	pe := policy.NewEngine(policy.EnforcementDeny)
+	pe.Register(&policy.Policy{
+		Name: "always_deny", Enforcement: policy.EnforcementDeny, Severity: policy.SeverityCritical,
+		CheckFunc: func(*core.Skill, map[string]interface{}, string) (bool, string) { return true, "denied" },
+	})
+	skill := &core.Skill{Name: "aws.rds.failover", RiskLevel: core.RiskHigh}
+
+	// A preview shows the plan as it would run under the session...
+	preview := m.Preview()
+	result := pe.Evaluate(skill, nil, "production")
+	if bypassed, err := preview.ApplyPolicy(result, "alice", skill, "production"); !bypassed || err != nil || !result.Passed {
+		t.Fatalf("expected the preview to apply the session, got bypassed=%t err=%v", bypassed, err)
+	}
+	if allowed, reason := preview.AuthorizeRBAC(false, "denied", "alice", skill, "production"); !allowed || !strings.Contains(reason, "INC-8") {
+		t.Errorf("expected the preview to apply the session to RBAC, got %t %q", allowed, reason)
+	}
+
+	// ...without recording or alerting a bypass that never happens.
+	if entries := m.Ledger().Entries(); len(entries) != 1 || entries[0].Event != breakglass.EventActivated {
+		t.Errorf("expected only the activation in the ledger, got %+v", entries)
+	}
+	if len(rec.events) != 1 {
+		t.Errorf("expected only the activation alert, got %d", len(rec.events))
+	}
+}
+
+func TestBypassFailsClosed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "breakglass.jsonl")
+	ledger, err := breakglass.OpenLedger(path, testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := breakglass.NewManager(ledger)
+	m.SetDesignatedUsers("alice")
+	if _, err := m.Activate("alice", "database primary is down", "INC-9", "production", time.Hour); err != nil {
+		t.Fatalf("Activate: %v", err)
+	}
+	// Make further ledger writes fail.
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(path, 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	pe := policy.NewEngine(policy.EnforcementDeny)
+	pe.Register(&policy.Policy{
+		Name: "always_deny", Enforcement: policy.EnforcementDeny, Severity: policy.SeverityCritical,
+		CheckFunc: func(*core.Skill, map[string]interface{}, string) (bool, string) { return true, "denied" },
+	})
+	skill := &core.Skill{Name: "aws.rds.failover", RiskLevel: core.RiskHigh}
+	result := pe.Evaluate(skill, nil, "production")
+	if bypassed, err := m.ApplyPolicy(result, "alice", skill, "production"); bypassed || err == nil || result.Passed {
+		t.Errorf("an unrecorded policy bypass must be refused, got bypassed=%t err=%v", bypassed, err)
+	}
+	if allowed, _ := m.AuthorizeRBAC(false, "denied", "alice", skill, "production"); allowed {
+		t.Error("an unrecorded RBAC bypass must be refused")
+	}
+}
+
+func TestDeactivateEndsEverySession(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	m, _ := newManager(t, now)
+	for _, env := range []string{"production", "staging"} {
+		if _, err := m.Activate("alice", "database primary is down", "INC-3", env, time.Hour); err != nil {
+			t.Fatalf("Activate: %v", err)
+		}
+	}
+	if n := len(m.Ledger().Entries()); n != 2 {
+		t.Fatalf("expected two activations, got %d entries", n)
+	}
+	if err := m.Deactivate("alice"); err != nil {
+		t.Fatal(err)
+	}
+	var ended int
+	for _, e := range m.Ledger().Entries() {
+		if e.Event == breakglass.EventDeactivated {
+			ended++
+		}
+	}
+	if ended != 2 {
+		t.Errorf("expected both sessions deactivated, got %d", ended)
+	}
+	for _, env := range []string{"production", "staging"} {
+		if m.ActiveSession("alice", env) != nil {
+			t.Errorf("session for %s should have ended", env)
+		}
+	}
+}
+
+func TestLedgerTamperDetection(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "breakglass.jsonl")
+	l, err := breakglass.OpenLedger(path, testKey)
+	if err != nil {
+		t.Fatalf("OpenLedger: %v", err)
+	}
+	for _, user := range []string{"alice", "bob", "carol"} {
+		if _, err := l.Append(breakglass.Entry{Event: breakglass.EventActivated, User: user, IncidentID: "INC-1"}); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+	if _, err := breakglass.OpenLedger(path, testKey); err != nil {
+		t.Fatalf("persisted ledger should verify: %v", err)
+	}
+	if _, err := breakglass.OpenLedger(path, []byte("another-key-another-key")); !errors.Is(err, breakglass.ErrTampered) {
+		t.Errorf("a ledger opened with the wrong key must fail verification, got %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	original := strings.Split(strings.TrimSpace(string(data)), "\n")
+	write := func(lines ...string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expectTampered := func(what string) {
+		t.Helper()
+		if _, err := breakglass.OpenLedger(path, testKey); !errors.Is(err, breakglass.ErrTampered) {
+			t.Errorf("%s: expected OpenLedger to fail closed, got %v", what, err)
+		}
+	}
+
+	// Rewriting an entry without updating its hash.
+	var e breakglass.Entry
+	_ = json.Unmarshal([]byte(original[1]), &e)
+	e.User = "mallory"
+	edited, _ := json.Marshal(e)
+	write(original[0], string(edited), original[2])
+	expectTampered("edited entry")
+
+	// Removing an entry in the middle or cutting off the tail.
+	write(original[0], original[2])
+	expectTampered("removed entry")
+	write(original[0], original[1])
+	expectTampered("truncated ledger")
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	expectTampered("deleted ledger")
+
+	// Appending a forged activation whose hash is recomputed without the key.
+	write(original...)
+	var last breakglass.Entry
+	_ = json.Unmarshal([]byte(original[2]), &last)
+	forged := breakglass.Entry{Sequence: 4, Timestamp: time.Now().UTC(), Event: breakglass.EventActivated,
+		SessionID: "bg-mallory", User: "mallory", ExpiresAt: time.Now().Add(time.Hour).UTC(), PrevHash: last.Hash}
+	body, _ := json.Marshal(forged)
+	sum := sha256.Sum256(body)
+	forged.Hash = hex.EncodeToString(sum[:])
+	line, _ := json.Marshal(forged)
+	write(append(original, string(line))...)
+	expectTampered("forged activation")
+}
@@ -0,0 +1,292 @@
+package breakglass
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrTampered is returned by OpenLedger when a persisted ledger fails
+// verification.
+var ErrTampered = errors.New("break-glass ledger failed verification")
+
+// minKeyLength is the shortest accepted ledger key, in bytes.
+const minKeyLength = 16
+
+// EventType identifies what a ledger entry records.
+type EventType string
+
+const (
+	EventActivated     EventType = "activated"
+	EventDeactivated   EventType = "deactivated"
+	EventPolicyBypass  EventType = "policy_bypass"
+	EventRBACBypass    EventType = "rbac_bypass"
+	EventDeniedRequest EventType = "denied_request"
+)
+
+// Entry is a single hash-chained break-glass audit record. Each entry
+// carries the hash of its predecessor, and hashes are HMAC-SHA256 under the
+// ledger key, so editing, reordering or removing an entry breaks the chain
+// and cannot be papered over by recomputing hashes without the key.
+type Entry struct {
+	Sequence      int       `json:"sequence"`
+	Timestamp     time.Time `json:"timestamp"`
+	Event         EventType `json:"event"`
+	SessionID     string    `json:"session_id,omitempty"`
+	User          string    `json:"user"`
+	IncidentID    string    `json:"incident_id,omitempty"`
+	Justification string    `json:"justification,omitempty"`
+	Environment   string    `json:"environment,omitempty"`
+	SkillName     string    `json:"skill_name,omitempty"`
+	ExpiresAt     time.Time `json:"expires_at,omitempty"`
+	Details       string    `json:"details,omitempty"`
+	PrevHash      string    `json:"prev_hash"`
+	Hash          string    `json:"hash"`
+}
+
+// computeHash returns the HMAC-SHA256 under key of the entry with its Hash
+// field cleared.
+func (e Entry) computeHash(key []byte) string {
+	e.Hash = ""
+	data, _ := json.Marshal(e)
+	return mac(key, data)
+}
+
+func mac(key, data []byte) string {
+	h := hmac.New(sha256.New, key)
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// head anchors the end of a persisted chain. It is kept next to the ledger
+// and rewritten on every append, so cutting off trailing entries no longer
+// matches it, and its MAC keeps it from being rewritten without the key.
+type head struct {
+	Sequence int    `json:"sequence"`
+	Hash     string `json:"hash"`
+	MAC      string `json:"mac"`
+}
+
+func (h head) computeMAC(key []byte) string {
+	return mac(key, []byte(fmt.Sprintf("head:%d:%s", h.Sequence, h.Hash)))
+}
+
+// Ledger is an append-only, hash-chained break-glass audit log. When a path
+// is set, entries are persisted as JSON lines and the chain head is anchored
+// in a .head file beside it.
+type Ledger struct {
+	mu      sync.RWMutex
+	path    string
+	key     []byte
+	entries []Entry
+}
+
+// NewLedger creates an in-memory ledger keyed with key.
+func NewLedger(key []byte) *Ledger {
+	return &Ledger{key: key}
+}
+
+// OpenLedger loads a persisted ledger from path, creating it on first
+// append, and verifies it under key. A ledger that fails verification is
+// not returned: its sessions cannot be trusted, so the error wraps
+// ErrTampered.
+func OpenLedger(path string, key []byte) (*Ledger, error) {
+	if len(key) < minKeyLength {
+		return nil, fmt.Errorf("break-glass ledger key must be at least %d bytes", minKeyLength)
+	}
+	l := &Ledger{path: path, key: key}
+
+	f, err := os.Open(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to open break-glass ledger: %w", err)
+	}
+	if err == nil {
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		for line := 1; scanner.Scan(); line++ {
+			if len(scanner.Bytes()) == 0 {
+				continue
+			}
+			var e Entry
+			if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+				return nil, fmt.Errorf("%w: %s:%d: %v", ErrTampered, path, line, err)
+			}
+			l.entries = append(l.entries, e)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read break-glass ledger: %w", err)
+		}
+	}
+	if err := l.Verify(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTampered, err)
+	}
+	return l, nil
+}
+
+// LoadKey reads the ledger key from path. If the file does not exist a
+// random key is generated and written there (mode 0600), so a fresh install
+// works without setup; a key lost after entries were written makes the
+// ledger fail verification rather than start a new chain.
+func LoadKey(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		raw := make([]byte, 32)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, fmt.Errorf("failed to generate break-glass ledger key: %w", err)
+		}
+		data = []byte(hex.EncodeToString(raw))
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return nil, fmt.Errorf("failed to create key directory: %w", err)
+		}
+		if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+			return nil, fmt.Errorf("failed to write break-glass ledger key: %w", err)
+		}
+		return data, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read break-glass ledger key: %w", err)
+	}
+	key := []byte(strings.TrimSpace(string(data)))
+	if len(key) < minKeyLength {
+		return nil, fmt.Errorf("break-glass ledger key %s must be at least %d bytes", path, minKeyLength)
+	}
+	return key, nil
+}
+
+// Append chains and stores a new entry, returning it with sequence and hash set.
+func (l *Ledger) Append(e Entry) (Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e.Sequence = len(l.entries) + 1
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now()
+	}
+	e.Timestamp = e.Timestamp.UTC()
+	e.ExpiresAt = e.ExpiresAt.UTC()
+	if len(l.entries) > 0 {
+		e.PrevHash = l.entries[len(l.entries)-1].Hash
+	}
+	e.Hash = e.computeHash(l.key)
+
+	if l.path != "" {
+		if err := l.persist(e); err != nil {
+			return Entry{}, err
+		}
+	}
+	l.entries = append(l.entries, e)
+	return e, nil
+}
+
+func (l *Ledger) persist(e Entry) error {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create ledger directory: %w", err)
+	}
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open break-glass ledger: %w", err)
+	}
+	defer f.Close()
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ledger entry: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write break-glass ledger: %w", err)
+	}
+	return l.writeHead(e)
+}
+
+func (l *Ledger) headPath() string { return l.path + ".head" }
+
+// writeHead anchors e as the end of the chain. The head is replaced
+// atomically so a crash leaves either the old or the new anchor.
+func (l *Ledger) writeHead(e Entry) error {
+	h := head{Sequence: e.Sequence, Hash: e.Hash}
+	h.MAC = h.computeMAC(l.key)
+	data, err := json.Marshal(h)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ledger head: %w", err)
+	}
+	tmp := l.headPath() + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write break-glass ledger head: %w", err)
+	}
+	if err := os.Rename(tmp, l.headPath()); err != nil {
+		return fmt.Errorf("failed to write break-glass ledger head: %w", err)
+	}
+	return nil
+}
+
+// verifyHead checks that the anchored head matches the last entry.
+func (l *Ledger) verifyHead() error {
+	data, err := os.ReadFile(l.headPath())
+	if os.IsNotExist(err) {
+		if len(l.entries) == 0 {
+			return nil
+		}
+		return fmt.Errorf("head anchor %s is missing", l.headPath())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read ledger head: %w", err)
+	}
+	var h head
+	if err := json.Unmarshal(data, &h); err != nil {
+		return fmt.Errorf("head anchor: %w", err)
+	}
+	if !hmac.Equal([]byte(h.MAC), []byte(h.computeMAC(l.key))) {
+		return fmt.Errorf("head anchor does not match the ledger key")
+	}
+	last := Entry{}
+	if len(l.entries) > 0 {
+		last = l.entries[len(l.entries)-1]
+	}
+	if h.Sequence != last.Sequence || h.Hash != last.Hash {
+		return fmt.Errorf("ledger ends at entry %d but its head anchor is entry %d (entries removed)", last.Sequence, h.Sequence)
+	}
+	return nil
+}
+
+// Entries returns a copy of all ledger entries.
+func (l *Ledger) Entries() []Entry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return append([]Entry(nil), l.entries...)
+}
+
+// Verify checks the keyed hash chain and, for a persisted ledger, its head
+// anchor. It returns an error describing the first entry that has been
+// altered, reordered or removed.
+func (l *Ledger) Verify() error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	prev := ""
+	for i, e := range l.entries {
+		if e.Sequence != i+1 {
+			return fmt.Errorf("entry %d: sequence %d out of order", i+1, e.Sequence)
+		}
+		if e.PrevHash != prev {
+			return fmt.Errorf("entry %d: chain broken (previous hash mismatch)", e.Sequence)
+		}
+		if !hmac.Equal([]byte(e.computeHash(l.key)), []byte(e.Hash)) {
+			return fmt.Errorf("entry %d: contents do not match hash", e.Sequence)
+		}
+		prev = e.Hash
+	}
+	if l.path != "" {
+		return l.verifyHead()
+	}
+	return nil
+}
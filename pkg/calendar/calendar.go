@@ -0,0 +1,298 @@
+// Package calendar provides change freeze periods and maintenance windows
+// that block or escalate infrastructure mutations at the wrong time.
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/parth14193/ownbot/pkg/environment"
+)
+
+// Action determines what happens to a mutation inside a freeze.
+type Action string
+
+const (
+	ActionBlock    Action = "block"    // Deny the mutation
+	ActionEscalate Action = "escalate" // Allow, but escalate risk and require confirmation
+)
+
+// FreezePeriod is a named interval during which changes are restricted,
+// such as a holiday or a release freeze.
+type FreezePeriod struct {
+	Name   string    `json:"name"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Reason string    `json:"reason,omitempty"`
+	Action Action    `json:"action"`
+}
+
+// Contains reports whether t falls inside the freeze period.
+func (f *FreezePeriod) Contains(t time.Time) bool {
+	return !t.Before(f.Start) && t.Before(f.End)
+}
+
+// MaintenanceWindow is a recurring weekly window in which changes are expected.
+type MaintenanceWindow struct {
+	Name     string         `json:"name"`
+	Days     []time.Weekday `json:"days"`
+	Start    string         `json:"start"` // HH:MM in TimeZone
+	Duration time.Duration  `json:"duration"`
+	TimeZone string         `json:"time_zone"`
+}
+
+// Contains reports whether t falls inside an occurrence of the window.
+func (w *MaintenanceWindow) Contains(t time.Time) (bool, error) {
+	loc := time.UTC
+	if w.TimeZone != "" {
+		l, err := time.LoadLocation(w.TimeZone)
+		if err != nil {
+			return false, fmt.Errorf("window '%s': %w", w.Name, err)
+		}
+		loc = l
+	}
+	startOfDay, err := time.Parse("15:04", w.Start)
+	if err != nil {
+		return false, fmt.Errorf("window '%s': invalid start %q (want HH:MM)", w.Name, w.Start)
+	}
+
+	local := t.In(loc)
+	// Check today's and previous days' occurrences so windows spanning midnight work.
+	for back := 0; back <= int(w.Duration/(24*time.Hour))+1; back++ {
+		day := local.AddDate(0, 0, -back)
+		if !containsDay(w.Days, day.Weekday()) {
+			continue
+		}
+		open := time.Date(day.Year(), day.Month(), day.Day(), startOfDay.Hour(), startOfDay.Minute(), 0, 0, loc)
+		if !local.Before(open) && local.Before(open.Add(w.Duration)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Calendar groups freeze periods and maintenance windows for a set of
+// environments and teams. Empty Environments or Teams means "all".
+type Calendar struct {
+	Name          string              `json:"name"`
+	Environments  []string            `json:"environments,omitempty"` // environment or tier names
+	Teams         []string            `json:"teams,omitempty"`
+	Freezes       []FreezePeriod      `json:"freezes,omitempty"`
+	Windows       []MaintenanceWindow `json:"windows,omitempty"`
+	RequireWindow bool                `json:"require_window"` // escalate mutations outside all windows
+}
+
+// Override is a recorded break-glass exemption from freezes in an environment.
+type Override struct {
+	Environment string    `json:"environment"`
+	User        string    `json:"user"`
+	Reason      string    `json:"reason"`
+	Expires     time.Time `json:"expires"`
+}
+
+// Decision is the outcome of checking a mutation against all calendars.
+type Decision struct {
+	Allowed    bool      `json:"allowed"`
+	Escalate   bool      `json:"escalate"`
+	Calendar   string    `json:"calendar,omitempty"`
+	Period     string    `json:"period,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+	Overridden bool      `json:"overridden"`
+	Override   *Override `json:"override,omitempty"`
+}
+
+// Restricted reports whether the decision blocks or escalates the mutation.
+func (d *Decision) Restricted() bool {
+	return !d.Allowed || d.Escalate
+}
+
+// Registry holds calendars and override records.
+type Registry struct {
+	mu         sync.RWMutex
+	calendars  []*Calendar
+	overrides  []Override
+	classifier *environment.Classifier
+	now        func() time.Time
+}
+
+// NewRegistry creates an empty calendar registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		classifier: environment.DefaultClassifier(),
+		now:        time.Now,
+	}
+}
+
+// SetClassifier replaces the environment tier classifier used to match
+// calendar environments against tier names.
+func (r *Registry) SetClassifier(classifier *environment.Classifier) {
+	r.classifier = classifier
+}
+
+// SetClock replaces the time source, mainly for tests.
+func (r *Registry) SetClock(now func() time.Time) {
+	r.now = now
+}
+
+// Now returns the current time from the registry clock.
+func (r *Registry) Now() time.Time {
+	return r.now()
+}
+
+// Add registers a calendar.
+func (r *Registry) Add(cal *Calendar) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calendars = append(r.calendars, cal)
+}
+
+// List returns all registered calendars.
+func (r *Registry) List() []*Calendar {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]*Calendar(nil), r.calendars...)
+}
+
+// RecordOverride records a break-glass override that lifts freezes for an
+// environment until it expires.
+func (r *Registry) RecordOverride(o Override) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.overrides = append(r.overrides, o)
+}
+
+// Check evaluates a mutation in env by team at time at.
+func (r *Registry) Check(env, team string, at time.Time) *Decision {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	decision := &Decision{Allowed: true}
+	for _, cal := range r.calendars {
+		if !r.calendarApplies(cal, env, team) {
+			continue
+		}
+
+		for i := range cal.Freezes {
+			f := &cal.Freezes[i]
+			if !f.Contains(at) {
+				continue
+			}
+			reason := fmt.Sprintf("%s freeze '%s' until %s", cal.Name, f.Name, f.End.Format(time.RFC3339))
+			if f.Reason != "" {
+				reason += " — " + f.Reason
+			}
+			if f.Action == ActionEscalate {
+				if decision.Allowed && !decision.Escalate {
+					decision.Escalate = true
+					decision.Calendar, decision.Period, decision.Reason = cal.Name, f.Name, reason
+				}
+				continue
+			}
+			if decision.Allowed {
+				decision.Allowed = false
+				decision.Calendar, decision.Period, decision.Reason = cal.Name, f.Name, reason
+			}
+		}
+
+		if cal.RequireWindow && len(cal.Windows) > 0 && decision.Allowed && !decision.Escalate {
+			if !inAnyWindow(cal.Windows, at) {
+				decision.Escalate = true
+				decision.Calendar = cal.Name
+				decision.Reason = fmt.Sprintf("%s: outside all maintenance windows", cal.Name)
+			}
+		}
+	}
+
+	if decision.Restricted() {
+		if o := r.activeOverride(env, at); o != nil {
+			decision.Allowed = true
+			decision.Overridden = true
+			decision.Override = o
+		}
+	}
+	return decision
+}
+
+func (r *Registry) calendarApplies(cal *Calendar, env, team string) bool {
+	if len(cal.Environments) > 0 && !r.classifier.MatchesAny(env, cal.Environments) {
+		return false
+	}
+	if len(cal.Teams) > 0 && team != "" {
+		for _, t := range cal.Teams {
+			if strings.EqualFold(t, team) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func (r *Registry) activeOverride(env string, at time.Time) *Override {
+	for i := len(r.overrides) - 1; i >= 0; i-- {
+		o := r.overrides[i]
+		if at.Before(o.Expires) && (o.Environment == "" || r.classifier.MatchesAny(env, []string{o.Environment})) {
+			return &o
+		}
+	}
+	return nil
+}
+
+func inAnyWindow(windows []MaintenanceWindow, at time.Time) bool {
+	for i := range windows {
+		if ok, err := windows[i].Contains(at); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+func containsDay(days []time.Weekday, d time.Weekday) bool {
+	for _, day := range days {
+		if day == d {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseWeekday converts names like "mon" or "Saturday" to time.Weekday.
+func ParseWeekday(s string) (time.Weekday, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if s == name || (len(s) >= 2 && strings.HasPrefix(name, s)) {
+			return d, nil
+		}
+	}
+	return time.Sunday, fmt.Errorf("unknown weekday: %s", s)
+}
+
+// Render formats the registry for display.
+func (r *Registry) Render() string {
+	var b strings.Builder
+	cals := r.List()
+	b.WriteString(fmt.Sprintf("📅 CHANGE CALENDARS (%d)\n", len(cals)))
+	b.WriteString("─────────────────────────────────────────\n")
+	for _, cal := range cals {
+		scope := "all environments"
+		if len(cal.Environments) > 0 {
+			scope = strings.Join(cal.Environments, ", ")
+		}
+		b.WriteString(fmt.Sprintf("  • %s (%s)\n", cal.Name, scope))
+		for _, f := range cal.Freezes {
+			b.WriteString(fmt.Sprintf("      ❄️  %-22s %s → %s [%s]\n", f.Name,
+				f.Start.Format("2006-01-02 15:04 MST"), f.End.Format("2006-01-02 15:04 MST"), f.Action))
+		}
+		for _, w := range cal.Windows {
+			var days []string
+			for _, d := range w.Days {
+				days = append(days, d.String()[:3])
+			}
+			b.WriteString(fmt.Sprintf("      🛠️  %-22s %s %s +%s (%s)\n", w.Name, strings.Join(days, ","), w.Start, w.Duration, w.TimeZone))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,142 @@
+package calendar_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parth14193/ownbot/pkg/calendar"
+	"github.com/parth14193/ownbot/pkg/config"
+	"github.com/parth14193/ownbot/pkg/core"
+	"github.com/parth14193/ownbot/pkg/policy"
+)
+
+func newRegistry(t *testing.T) *calendar.Registry {
+	t.Helper()
+	r := calendar.NewRegistry()
+	err := r.LoadConfig([]*config.CalendarConfig{{
+		Name:          "platform",
+		Environments:  []string{"production"},
+		RequireWindow: true,
+		Freezes: []*config.FreezeConfig{
+			{Name: "year-end", Start: "2026-12-20", End: "2027-01-02", Action: "block"},
+			{Name: "launch", Start: "2026-11-02T00:00:00Z", End: "2026-11-03T00:00:00Z", Action: "escalate"},
+		},
+		Windows: []*config.MaintenanceWindowConfig{
+			{Name: "weekend", Days: []string{"sat"}, Start: "22:00", Duration: "4h", TimeZone: "Europe/Berlin"},
+		},
+	}})
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	return r
+}
+
+func TestFreezeBlocks(t *testing.T) {
+	r := newRegistry(t)
+
+	d := r.Check("production", "", time.Date(2027, 1, 2, 12, 0, 0, 0, time.UTC))
+	if d.Allowed {
+		t.Error("date-only freeze end should include the whole last day")
+	}
+	if d := r.Check("staging", "", time.Date(2026, 12, 24, 12, 0, 0, 0, time.UTC)); d.Restricted() {
+		t.Error("calendar scoped to production should not restrict staging")
+	}
+	if d := r.Check("production", "", time.Date(2026, 11, 2, 12, 0, 0, 0, time.UTC)); !d.Allowed || !d.Escalate {
+		t.Errorf("escalating freeze should allow but escalate, got %+v", d)
+	}
+}
+
+func TestMaintenanceWindowTimeZone(t *testing.T) {
+	r := newRegistry(t)
+
+	// Saturday 23:30 Berlin (CET, UTC+1) is inside the window, and the window
+	// continues past midnight into Sunday.
+	inside := time.Date(2026, 11, 7, 22, 30, 0, 0, time.UTC)
+	if d := r.Check("production", "", inside); d.Restricted() {
+		t.Errorf("expected change inside maintenance window to be allowed, got %+v", d)
+	}
+	afterMidnight := time.Date(2026, 11, 8, 0, 30, 0, 0, time.UTC)
+	if d := r.Check("production", "", afterMidnight); d.Restricted() {
+		t.Errorf("window should span midnight, got %+v", d)
+	}
+	outside := time.Date(2026, 11, 4, 10, 0, 0, 0, time.UTC)
+	if d := r.Check("production", "", outside); !d.Escalate {
+		t.Error("change outside all maintenance windows should be escalated")
+	}
+}
+
+func TestBreakGlassOverride(t *testing.T) {
+	r := newRegistry(t)
+	at := time.Date(2026, 12, 24, 12, 0, 0, 0, time.UTC)
+	r.RecordOverride(calendar.Override{Environment: "production", User: "alice", Reason: "INC-42", Expires: at.Add(time.Hour)})
+
+	d := r.Check("production", "", at)
+	if !d.Allowed || !d.Overridden {
+		t.Errorf("override should lift the freeze, got %+v", d)
+	}
+	if d := r.Check("production", "", at.Add(2*time.Hour)); d.Allowed {
+		t.Error("expired override should no longer lift the freeze")
+	}
+}
+
+func TestParseICS(t *testing.T) {
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"SUMMARY:Christmas",
+		"DTSTART;VALUE=DATE:20261224",
+		"DTEND;VALUE=DATE:20261227",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"SUMMARY:Patch ",
+		" Tuesday",
+		"DTSTART;TZID=America/New_York:20261103T200000",
+		"DTEND;TZID=America/New_York:20261103T230000",
+		"RRULE:FREQ=WEEKLY;BYDAY=TU",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	freezes, windows, err := calendar.ParseICS(strings.NewReader(ics), calendar.ActionBlock)
+	if err != nil {
+		t.Fatalf("ParseICS: %v", err)
+	}
+	if len(freezes) != 1 || freezes[0].Name != "Christmas" || freezes[0].End.Sub(freezes[0].Start) != 72*time.Hour {
+		t.Errorf("unexpected freezes: %+v", freezes)
+	}
+	if len(windows) != 1 || windows[0].Name != "Patch Tuesday" || windows[0].Duration != 3*time.Hour {
+		t.Fatalf("unexpected windows: %+v", windows)
+	}
+	if windows[0].TimeZone != "America/New_York" || windows[0].Days[0] != time.Tuesday {
+		t.Errorf("window should keep time zone and day, got %+v", windows[0])
+	}
+}
+
+func TestMissingICalFile(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "holidays.ics")
+	_, err := calendar.FromConfig(&config.CalendarConfig{Name: "platform", ICalFiles: []string{missing}})
+	if err == nil || !strings.Contains(err.Error(), "platform") {
+		t.Errorf("a missing iCalendar file must fail the calendar, got %v", err)
+	}
+}
+
+func TestFreezePolicy(t *testing.T) {
+	r := newRegistry(t)
+	r.SetClock(func() time.Time { return time.Date(2026, 12, 24, 12, 0, 0, 0, time.UTC) })
+
+	e := policy.NewEngine(policy.EnforcementDeny)
+	for _, p := range calendar.Policies(r) {
+		e.Register(p)
+	}
+
+	deploy := &core.Skill{Name: "k8s.deploy", RiskLevel: core.RiskHigh}
+	if e.Evaluate(deploy, nil, "production").Passed {
+		t.Error("deploys during a freeze should be denied")
+	}
+	list := &core.Skill{Name: "aws.ec2.list", RiskLevel: core.RiskLow}
+	if !e.Evaluate(list, nil, "production").Passed {
+		t.Error("read-only skills should not be affected by freezes")
+	}
+}
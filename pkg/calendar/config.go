@@ -0,0 +1,146 @@
+package calendar
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/parth14193/ownbot/pkg/config"
+)
+
+// LoadConfig builds calendars from configuration, including any referenced
+// iCalendar files, and registers them.
+func (r *Registry) LoadConfig(cfgs []*config.CalendarConfig) error {
+	for _, cc := range cfgs {
+		cal, err := FromConfig(cc)
+		if err != nil {
+			return err
+		}
+		r.Add(cal)
+	}
+	return nil
+}
+
+// FromConfig converts a calendar config section into a Calendar. A configured
+// iCalendar file that cannot be read is an error: skipping it would let
+// changes through a freeze the operator configured.
+func FromConfig(cc *config.CalendarConfig) (*Calendar, error) {
+	cal := &Calendar{
+		Name:          cc.Name,
+		Environments:  cc.Environments,
+		Teams:         cc.Teams,
+		RequireWindow: cc.RequireWindow,
+	}
+
+	for _, fc := range cc.Freezes {
+		f, err := freezeFromConfig(fc)
+		if err != nil {
+			return nil, fmt.Errorf("calendar '%s': %w", cc.Name, err)
+		}
+		cal.Freezes = append(cal.Freezes, f)
+	}
+
+	for _, wc := range cc.Windows {
+		w, err := windowFromConfig(wc)
+		if err != nil {
+			return nil, fmt.Errorf("calendar '%s': %w", cc.Name, err)
+		}
+		cal.Windows = append(cal.Windows, w)
+	}
+
+	action, err := parseAction(cc.ICalAction)
+	if err != nil {
+		return nil, fmt.Errorf("calendar '%s': %w", cc.Name, err)
+	}
+	for _, path := range cc.ICalFiles {
+		freezes, windows, err := LoadICS(expandHome(path), action)
+		if err != nil {
+			return nil, fmt.Errorf("calendar '%s': %w", cc.Name, err)
+		}
+		cal.Freezes = append(cal.Freezes, freezes...)
+		cal.Windows = append(cal.Windows, windows...)
+	}
+
+	return cal, nil
+}
+
+func freezeFromConfig(fc *config.FreezeConfig) (FreezePeriod, error) {
+	loc := time.UTC
+	if fc.TimeZone != "" {
+		l, err := time.LoadLocation(fc.TimeZone)
+		if err != nil {
+			return FreezePeriod{}, fmt.Errorf("freeze '%s': %w", fc.Name, err)
+		}
+		loc = l
+	}
+	start, _, err := parseTimeOrDate(fc.Start, loc)
+	if err != nil {
+		return FreezePeriod{}, fmt.Errorf("freeze '%s': start: %w", fc.Name, err)
+	}
+	end, dateOnly, err := parseTimeOrDate(fc.End, loc)
+	if err != nil {
+		return FreezePeriod{}, fmt.Errorf("freeze '%s': end: %w", fc.Name, err)
+	}
+	if dateOnly {
+		end = end.AddDate(0, 0, 1)
+	}
+	if !end.After(start) {
+		return FreezePeriod{}, fmt.Errorf("freeze '%s': end must be after start", fc.Name)
+	}
+	action, err := parseAction(fc.Action)
+	if err != nil {
+		return FreezePeriod{}, fmt.Errorf("freeze '%s': %w", fc.Name, err)
+	}
+	return FreezePeriod{Name: fc.Name, Start: start, End: end, Reason: fc.Reason, Action: action}, nil
+}
+
+func windowFromConfig(wc *config.MaintenanceWindowConfig) (MaintenanceWindow, error) {
+	d, err := time.ParseDuration(wc.Duration)
+	if err != nil {
+		return MaintenanceWindow{}, fmt.Errorf("window '%s': %w", wc.Name, err)
+	}
+	w := MaintenanceWindow{Name: wc.Name, Start: wc.Start, Duration: d, TimeZone: wc.TimeZone}
+	for _, day := range wc.Days {
+		wd, err := ParseWeekday(day)
+		if err != nil {
+			return MaintenanceWindow{}, fmt.Errorf("window '%s': %w", wc.Name, err)
+		}
+		w.Days = append(w.Days, wd)
+	}
+	// Validate the start time and time zone up front.
+	if _, err := w.Contains(time.Now()); err != nil {
+		return MaintenanceWindow{}, err
+	}
+	return w, nil
+}
+
+func parseAction(s string) (Action, error) {
+	switch strings.ToLower(s) {
+	case "", string(ActionBlock):
+		return ActionBlock, nil
+	case string(ActionEscalate):
+		return ActionEscalate, nil
+	default:
+		return "", fmt.Errorf("unknown freeze action: %s", s)
+	}
+}
+
+// parseTimeOrDate accepts RFC 3339 or YYYY-MM-DD and reports which form was used.
+func parseTimeOrDate(s string, loc *time.Location) (time.Time, bool, error) {
+	if t, err := time.ParseInLocation("2006-01-02", s, loc); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	return t, false, err
+}
+
+func expandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	}
+	return path
+}
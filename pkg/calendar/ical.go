@@ -0,0 +1,178 @@
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// icsEvent is the subset of a VEVENT that calendars understand.
+type icsEvent struct {
+	summary string
+	start   time.Time
+	end     time.Time
+	tzid    string
+	rrule   map[string]string
+}
+
+// ParseICS reads iCalendar (RFC 5545) events. One-off events become freeze
+// periods with the given action; weekly recurring events (RRULE FREQ=WEEKLY)
+// become maintenance windows.
+func ParseICS(r io.Reader, action Action) ([]FreezePeriod, []MaintenanceWindow, error) {
+	lines, err := unfoldICS(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var freezes []FreezePeriod
+	var windows []MaintenanceWindow
+	var ev *icsEvent
+
+	for n, line := range lines {
+		switch {
+		case line == "BEGIN:VEVENT":
+			ev = &icsEvent{}
+			continue
+		case line == "END:VEVENT":
+			if ev == nil {
+				return nil, nil, fmt.Errorf("line %d: END:VEVENT without BEGIN", n+1)
+			}
+			if ev.start.IsZero() {
+				return nil, nil, fmt.Errorf("line %d: event '%s' has no DTSTART", n+1, ev.summary)
+			}
+			if ev.end.IsZero() {
+				ev.end = ev.start.Add(24 * time.Hour)
+			}
+			if ev.rrule != nil {
+				w, err := ev.window()
+				if err != nil {
+					return nil, nil, fmt.Errorf("line %d: %w", n+1, err)
+				}
+				windows = append(windows, w)
+			} else {
+				freezes = append(freezes, FreezePeriod{Name: ev.summary, Start: ev.start, End: ev.end, Action: action})
+			}
+			ev = nil
+			continue
+		}
+		if ev == nil {
+			continue
+		}
+
+		name, params, value := splitICSLine(line)
+		switch name {
+		case "SUMMARY":
+			ev.summary = value
+		case "DTSTART", "DTEND":
+			t, err := parseICSTime(value, params)
+			if err != nil {
+				return nil, nil, fmt.Errorf("line %d: %s: %w", n+1, name, err)
+			}
+			if name == "DTSTART" {
+				ev.start, ev.tzid = t, params["TZID"]
+			} else {
+				ev.end = t
+			}
+		case "RRULE":
+			ev.rrule = make(map[string]string)
+			for _, part := range strings.Split(value, ";") {
+				if kv := strings.SplitN(part, "=", 2); len(kv) == 2 {
+					ev.rrule[kv[0]] = kv[1]
+				}
+			}
+		}
+	}
+	return freezes, windows, nil
+}
+
+// LoadICS reads freeze periods and maintenance windows from an .ics file.
+func LoadICS(path string, action Action) ([]FreezePeriod, []MaintenanceWindow, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open calendar: %w", err)
+	}
+	defer f.Close()
+
+	freezes, windows, err := ParseICS(f, action)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return freezes, windows, nil
+}
+
+func (ev *icsEvent) window() (MaintenanceWindow, error) {
+	if ev.rrule["FREQ"] != "WEEKLY" {
+		return MaintenanceWindow{}, fmt.Errorf("event '%s': only FREQ=WEEKLY recurrences are supported", ev.summary)
+	}
+	w := MaintenanceWindow{
+		Name:     ev.summary,
+		Start:    ev.start.Format("15:04"),
+		Duration: ev.end.Sub(ev.start),
+		TimeZone: ev.tzid,
+	}
+	if w.TimeZone == "" {
+		w.TimeZone = ev.start.Location().String()
+	}
+	byDay := ev.rrule["BYDAY"]
+	if byDay == "" {
+		w.Days = []time.Weekday{ev.start.Weekday()}
+		return w, nil
+	}
+	for _, code := range strings.Split(byDay, ",") {
+		d, err := ParseWeekday(code)
+		if err != nil {
+			return MaintenanceWindow{}, fmt.Errorf("event '%s': %w", ev.summary, err)
+		}
+		w.Days = append(w.Days, d)
+	}
+	return w, nil
+}
+
+// unfoldICS joins RFC 5545 folded lines (continuations start with a space or tab).
+func unfoldICS(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// splitICSLine splits "NAME;PARAM=V:value" into its parts.
+func splitICSLine(line string) (string, map[string]string, string) {
+	head, value, _ := strings.Cut(line, ":")
+	parts := strings.Split(head, ";")
+	params := make(map[string]string)
+	for _, p := range parts[1:] {
+		if kv := strings.SplitN(p, "=", 2); len(kv) == 2 {
+			params[kv[0]] = kv[1]
+		}
+	}
+	return strings.ToUpper(parts[0]), params, value
+}
+
+func parseICSTime(value string, params map[string]string) (time.Time, error) {
+	if params["VALUE"] == "DATE" || len(value) == 8 {
+		return time.ParseInLocation("20060102", value, time.UTC)
+	}
+	if strings.HasSuffix(value, "Z") {
+		return time.Parse("20060102T150405Z", value)
+	}
+	loc := time.UTC
+	if tz := params["TZID"]; tz != "" {
+		l, err := time.LoadLocation(tz)
+		if err != nil {
+			return time.Time{}, err
+		}
+		loc = l
+	}
+	return time.ParseInLocation("20060102T150405", value, loc)
+}
@@ -0,0 +1,71 @@
+package calendar
+
+import (
+	"fmt"
+
+	"github.com/parth14193/ownbot/pkg/core"
+	"github.com/parth14193/ownbot/pkg/policy"
+)
+
+// Policies returns the guardrails backed by the calendar registry:
+// change_freeze denies mutations inside blocking freezes, and
+// maintenance_window warns about escalating freezes, mutations outside
+// maintenance windows and freezes lifted by a break-glass override.
+func Policies(r *Registry) []*policy.Policy {
+	return []*policy.Policy{
+		{
+			Name:        "change_freeze",
+			Description: "Deny mutations during change freeze periods",
+			Enforcement: policy.EnforcementDeny,
+			Severity:    policy.SeverityCritical,
+			AppliesTo:   []string{"*"},
+			CheckFunc: func(skill *core.Skill, params map[string]interface{}, env string) (bool, string) {
+				if !IsMutation(skill) {
+					return false, ""
+				}
+				d := r.Check(env, Team(params), r.Now())
+				if !d.Allowed {
+					return true, fmt.Sprintf("Change freeze in effect: %s — record a break-glass override to proceed", d.Reason)
+				}
+				return false, ""
+			},
+		},
+		{
+			Name:        "maintenance_window",
+			Description: "Warn about mutations outside maintenance windows or during escalating freezes",
+			Enforcement: policy.EnforcementWarn,
+			Severity:    policy.SeverityWarning,
+			AppliesTo:   []string{"*"},
+			CheckFunc: func(skill *core.Skill, params map[string]interface{}, env string) (bool, string) {
+				if !IsMutation(skill) {
+					return false, ""
+				}
+				d := r.Check(env, Team(params), r.Now())
+				switch {
+				case d.Overridden:
+					return true, fmt.Sprintf("%s — overridden by break-glass (%s: %s)", d.Reason, d.Override.User, d.Override.Reason)
+				case d.Escalate:
+					return true, d.Reason
+				}
+				return false, ""
+			},
+		},
+	}
+}
+
+// IsMutation reports whether calendars apply to a skill. Low-risk skills are
+// read-only queries and are never restricted.
+func IsMutation(skill *core.Skill) bool {
+	return skill.RiskLevel > core.RiskLow
+}
+
+// Team extracts the owning team of an action from its parameters.
+func Team(params map[string]interface{}) string {
+	if params == nil {
+		return ""
+	}
+	if team, ok := params["_team"]; ok {
+		return fmt.Sprintf("%v", team)
+	}
+	return ""
+}
@@ -0,0 +1,217 @@
+package compliance
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// awsListFields maps the top-level list field of AWS CLI describe output to
+// the evidence key it provides.
+var awsListFields = map[string]string{
+	"trailList":      EvidenceTrails,         // aws cloudtrail describe-trails
+	"FlowLogs":       EvidenceFlowLogs,       // aws ec2 describe-flow-logs
+	"SecurityGroups": EvidenceSecurityGroups, // aws ec2 describe-security-groups
+	"Vpcs":           EvidenceVPCs,           // aws ec2 describe-vpcs
+	"Volumes":        EvidenceVolumes,        // aws ec2 describe-volumes
+	"DBInstances":    EvidenceDBInstances,    // aws rds describe-db-instances
+	"Listeners":      EvidenceListeners,      // aws elbv2 describe-listeners
+	"logGroups":      EvidenceLogGroups,      // aws logs describe-log-groups
+}
+
+// ParseAWSExport recognises decoded AWS CLI JSON output and returns the
+// evidence key and value it provides. ok is false for documents that are not
+// a recognised export.
+func ParseAWSExport(doc interface{}) (key string, value interface{}, ok bool, err error) {
+	obj, isObj := doc.(map[string]interface{})
+	if !isObj {
+		return "", nil, false, nil
+	}
+	for field, key := range awsListFields {
+		if list, isList := obj[field].([]interface{}); isList {
+			return key, list, true, nil
+		}
+	}
+	// aws configservice select-resource-config returns one JSON string per
+	// resource.
+	if results, isQuery := obj["Results"].([]interface{}); isQuery && obj["QueryInfo"] != nil {
+		buckets, err := configBuckets(results)
+		if err != nil {
+			return "", nil, false, err
+		}
+		return EvidenceBuckets, buckets, true, nil
+	}
+	// aws iam get-credential-report returns the CSV base64 encoded.
+	if content, isReport := obj["Content"].(string); isReport && obj["ReportFormat"] == "text/csv" {
+		data, err := base64.StdEncoding.DecodeString(content)
+		if err != nil {
+			return "", nil, false, fmt.Errorf("invalid credential report content: %w", err)
+		}
+		rows, err := ParseCredentialReport(data)
+		if err != nil {
+			return "", nil, false, err
+		}
+		return EvidenceCredentialReport, rows, true, nil
+	}
+	return "", nil, false, nil
+}
+
+// isTrivyReport reports whether doc is trivy JSON output (--format json).
+func isTrivyReport(doc interface{}) bool {
+	obj := object(doc)
+	_, hasResults := obj["Results"]
+	_, hasArtifact := obj["ArtifactName"]
+	return hasResults && hasArtifact
+}
+
+// ParseCredentialReport parses an IAM credential report CSV into one object
+// per user keyed by column name.
+func ParseCredentialReport(data []byte) ([]interface{}, error) {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid credential report: %w", err)
+	}
+	if len(records) == 0 || len(records[0]) < 2 || records[0][0] != "user" {
+		return nil, fmt.Errorf("invalid credential report: expected a header starting with 'user'")
+	}
+	header := records[0]
+	rows := make([]interface{}, 0, len(records)-1)
+	for _, rec := range records[1:] {
+		row := make(map[string]interface{}, len(header))
+		for i, col := range header {
+			if i < len(rec) {
+				row[col] = rec[i]
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// configBuckets converts AWS Config S3 bucket results (resourceName and the
+// supplementary encryption, logging and versioning configuration) into
+// buckets shaped like the s3api get-bucket-* exports.
+func configBuckets(results []interface{}) ([]interface{}, error) {
+	buckets := make([]interface{}, 0, len(results))
+	for _, r := range results {
+		raw, _ := r.(string)
+		var item map[string]interface{}
+		if err := json.Unmarshal([]byte(raw), &item); err != nil {
+			return nil, fmt.Errorf("invalid AWS Config result: %w", err)
+		}
+		name := str(item, "resourceName")
+		if name == "" {
+			return nil, fmt.Errorf("invalid AWS Config result: expected resourceName (select it for AWS::S3::Bucket)")
+		}
+		supp := object(item["supplementaryConfiguration"])
+		bucket := map[string]interface{}{"Name": name, "Encryption": map[string]interface{}{}, "Logging": map[string]interface{}{}}
+
+		var rules []interface{}
+		sse, _ := configValue(supp["ServerSideEncryptionConfiguration"]).(map[string]interface{})
+		list, _ := sse["rules"].([]interface{})
+		for _, rule := range list {
+			def := object(object(rule)["applyServerSideEncryptionByDefault"])
+			rules = append(rules, map[string]interface{}{"ApplyServerSideEncryptionByDefault": map[string]interface{}{
+				"SSEAlgorithm":   str(def, "sseAlgorithm"),
+				"KMSMasterKeyID": str(def, "kmsMasterKeyID"),
+			}})
+		}
+		if len(rules) > 0 {
+			bucket["Encryption"] = map[string]interface{}{"ServerSideEncryptionConfiguration": map[string]interface{}{"Rules": rules}}
+		}
+
+		logging, _ := configValue(supp["BucketLoggingConfiguration"]).(map[string]interface{})
+		if target := str(logging, "destinationBucketName"); target != "" {
+			bucket["Logging"] = map[string]interface{}{"LoggingEnabled": map[string]interface{}{"TargetBucket": target, "TargetPrefix": str(logging, "logFilePrefix")}}
+		}
+
+		versioning, _ := configValue(supp["BucketVersioningConfiguration"]).(map[string]interface{})
+		bucket["Versioning"] = map[string]interface{}{"Status": str(versioning, "status")}
+		buckets = append(buckets, bucket)
+	}
+	return buckets, nil
+}
+
+// configValue decodes an AWS Config supplementary configuration value, which
+// may be embedded as a JSON string.
+func configValue(v interface{}) interface{} {
+	if s, ok := v.(string); ok {
+		var decoded interface{}
+		if json.Unmarshal([]byte(s), &decoded) == nil {
+			return decoded
+		}
+	}
+	return v
+}
+
+// bucketAspects maps per-bucket export file suffixes to the bucket field
+// they fill.
+var bucketAspects = map[string]string{
+	"encryption": "Encryption", // aws s3api get-bucket-encryption
+	"logging":    "Logging",    // aws s3api get-bucket-logging
+	"versioning": "Versioning", // aws s3api get-bucket-versioning
+}
+
+// bucketSet assembles s3.buckets evidence from per-bucket exports named
+// s3/<bucket>.<encryption|logging|versioning>.json and from aws s3api
+// list-buckets output. Buckets listed without an export of an aspect have
+// that aspect unset (get-bucket-encryption fails for unencrypted buckets and
+// get-bucket-logging prints nothing when logging is off).
+type bucketSet struct {
+	buckets map[string]map[string]interface{}
+	order   []string
+}
+
+func newBucketSet() *bucketSet {
+	return &bucketSet{buckets: make(map[string]map[string]interface{})}
+}
+
+func (s *bucketSet) bucket(name string) map[string]interface{} {
+	b, ok := s.buckets[name]
+	if !ok {
+		b = map[string]interface{}{"Name": name}
+		s.buckets[name] = b
+		s.order = append(s.order, name)
+	}
+	return b
+}
+
+func (s *bucketSet) addList(list []interface{}) {
+	for _, item := range list {
+		if name := str(object(item), "Name"); name != "" {
+			s.bucket(name)
+		}
+	}
+}
+
+func (s *bucketSet) addFile(file string, data []byte) error {
+	base := strings.TrimSuffix(filepath.Base(file), ".json")
+	i := strings.LastIndex(base, ".")
+	if i <= 0 || bucketAspects[base[i+1:]] == "" {
+		return fmt.Errorf("%s: bucket exports must be named <bucket>.<encryption|logging|versioning>.json", file)
+	}
+	doc := map[string]interface{}{}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	s.bucket(base[:i])[bucketAspects[base[i+1:]]] = doc
+	return nil
+}
+
+// apply adds the assembled buckets to ev.
+func (s *bucketSet) apply(ev *Evidence) {
+	if len(s.order) == 0 {
+		return
+	}
+	list := make([]interface{}, 0, len(s.order))
+	for _, name := range s.order {
+		list = append(list, s.buckets[name])
+	}
+	ev.Append(EvidenceBuckets, list, "s3 exports")
+}
@@ -0,0 +1,523 @@
+package compliance
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// rootUser is the credential report row of the account root user.
+const rootUser = "<root_account>"
+
+// noEvidence is the result of a check whose evidence was not collected.
+func noEvidence(keys ...string) CheckResult {
+	return CheckResult{
+		Status:  StatusSkip,
+		Details: "no evidence: " + strings.Join(keys, ", "),
+	}
+}
+
+// evaluate fails when any resource failed and passes otherwise.
+func evaluate(resources []ResourceResult, passed, remediation string) CheckResult {
+	var failed int
+	for _, r := range resources {
+		if r.Status == StatusFail {
+			failed++
+		}
+	}
+	if failed == 0 {
+		return CheckResult{Status: StatusPass, Details: fmt.Sprintf("%s (%d resource(s) evaluated)", passed, len(resources)), Resources: resources}
+	}
+	return CheckResult{
+		Status:      StatusFail,
+		Details:     fmt.Sprintf("%d of %d resource(s) failing", failed, len(resources)),
+		Remediation: remediation,
+		Resources:   resources,
+	}
+}
+
+func pass(resource, details string) ResourceResult {
+	return ResourceResult{Resource: resource, Status: StatusPass, Details: details}
+}
+
+func fail(resource, details string) ResourceResult {
+	return ResourceResult{Resource: resource, Status: StatusFail, Details: details}
+}
+
+// ── IAM credential report ──────────────────────────────────────
+
+func userResource(row map[string]interface{}) string {
+	if user := str(row, "user"); user != rootUser {
+		return "user/" + user
+	}
+	return "root account"
+}
+
+// rootActivity checks that the root password and access keys were not used
+// within days of now.
+func rootActivity(rows []map[string]interface{}, now time.Time, days int) []ResourceResult {
+	var results []ResourceResult
+	for _, row := range rows {
+		if str(row, "user") != rootUser {
+			continue
+		}
+		var used []string
+		for _, field := range []string{"password_last_used", "access_key_1_last_used_date", "access_key_2_last_used_date"} {
+			if at, ok := reportTime(row, field); ok && now.Sub(at) < time.Duration(days)*24*time.Hour {
+				used = append(used, fmt.Sprintf("%s %s", strings.ReplaceAll(field, "_", " "), at.Format("2006-01-02")))
+			}
+		}
+		if len(used) > 0 {
+			results = append(results, fail(userResource(row), strings.Join(used, ", ")))
+		} else {
+			results = append(results, pass(userResource(row), fmt.Sprintf("not used in the last %d days", days)))
+		}
+	}
+	return results
+}
+
+// rootAccessKeys checks that the root user has no active access keys.
+func rootAccessKeys(rows []map[string]interface{}) []ResourceResult {
+	var results []ResourceResult
+	for _, row := range rows {
+		if str(row, "user") != rootUser {
+			continue
+		}
+		var active []string
+		for _, key := range []string{"access_key_1", "access_key_2"} {
+			if truthy(row[key+"_active"]) {
+				active = append(active, strings.ReplaceAll(key, "_", " "))
+			}
+		}
+		if len(active) > 0 {
+			results = append(results, fail(userResource(row), "active "+strings.Join(active, " and ")))
+		} else {
+			results = append(results, pass(userResource(row), "no active access keys"))
+		}
+	}
+	return results
+}
+
+// consoleMFA checks that IAM users with a console password have MFA.
+func consoleMFA(rows []map[string]interface{}) []ResourceResult {
+	var results []ResourceResult
+	for _, row := range rows {
+		if str(row, "user") == rootUser || !truthy(row["password_enabled"]) {
+			continue
+		}
+		if truthy(row["mfa_active"]) {
+			results = append(results, pass(userResource(row), "MFA active"))
+		} else {
+			results = append(results, fail(userResource(row), "console access without MFA"))
+		}
+	}
+	return results
+}
+
+// rootMFA checks that the root user has MFA.
+func rootMFA(rows []map[string]interface{}) []ResourceResult {
+	var results []ResourceResult
+	for _, row := range rows {
+		if str(row, "user") != rootUser {
+			continue
+		}
+		if truthy(row["mfa_active"]) {
+			results = append(results, pass(userResource(row), "MFA active"))
+		} else {
+			results = append(results, fail(userResource(row), "no MFA"))
+		}
+	}
+	return results
+}
+
+// staleCredentials checks that enabled passwords and active access keys of
+// IAM users were used within days. Credentials never used age from when they
+// were created or rotated.
+func staleCredentials(rows []map[string]interface{}, now time.Time, days int) []ResourceResult {
+	limit := time.Duration(days) * 24 * time.Hour
+	var results []ResourceResult
+	for _, row := range rows {
+		if str(row, "user") == rootUser {
+			continue
+		}
+		var stale []string
+		var checked int
+		if truthy(row["password_enabled"]) {
+			checked++
+			if at, ok := lastUse(row, "password_last_used", "user_creation_time"); ok && now.Sub(at) > limit {
+				stale = append(stale, fmt.Sprintf("password unused since %s", at.Format("2006-01-02")))
+			}
+		}
+		for _, key := range []string{"access_key_1", "access_key_2"} {
+			if !truthy(row[key+"_active"]) {
+				continue
+			}
+			checked++
+			if at, ok := lastUse(row, key+"_last_used_date", key+"_last_rotated"); ok && now.Sub(at) > limit {
+				stale = append(stale, fmt.Sprintf("%s unused since %s", strings.ReplaceAll(key, "_", " "), at.Format("2006-01-02")))
+			}
+		}
+		switch {
+		case len(stale) > 0:
+			results = append(results, fail(userResource(row), strings.Join(stale, ", ")))
+		case checked > 0:
+			results = append(results, pass(userResource(row), fmt.Sprintf("credentials used within %d days", days)))
+		}
+	}
+	return results
+}
+
+func lastUse(row map[string]interface{}, used, fallback string) (time.Time, bool) {
+	if at, ok := reportTime(row, used); ok {
+		return at, true
+	}
+	return reportTime(row, fallback)
+}
+
+// reportTime parses a credential report timestamp; N/A, no_information and
+// not_supported are not times.
+func reportTime(row map[string]interface{}, field string) (time.Time, bool) {
+	at, err := time.Parse(time.RFC3339, str(row, field))
+	return at, err == nil
+}
+
+// ── CloudTrail ─────────────────────────────────────────────────
+
+// multiRegionTrail checks that at least one multi-region trail is logging.
+func multiRegionTrail(trails []map[string]interface{}) []ResourceResult {
+	for _, t := range trails {
+		if truthy(t["IsMultiRegionTrail"]) && (t["IsLogging"] == nil || truthy(t["IsLogging"])) {
+			return []ResourceResult{pass("trail/"+str(t, "Name"), "multi-region trail logging")}
+		}
+	}
+	return []ResourceResult{fail("cloudtrail", fmt.Sprintf("none of %d trail(s) is a logging multi-region trail", len(trails)))}
+}
+
+// trailsWith checks that field is set on every trail.
+func trailsWith(trails []map[string]interface{}, field, what string) []ResourceResult {
+	if len(trails) == 0 {
+		return []ResourceResult{fail("cloudtrail", "no CloudTrail trails configured")}
+	}
+	var results []ResourceResult
+	for _, t := range trails {
+		if v := t[field]; v == nil || v == false || v == "" {
+			results = append(results, fail("trail/"+str(t, "Name"), "no "+what))
+		} else {
+			results = append(results, pass("trail/"+str(t, "Name"), what+" enabled"))
+		}
+	}
+	return results
+}
+
+// ── EC2 networking ─────────────────────────────────────────────
+
+// vpcFlowLogs checks that every VPC has an active flow log.
+func vpcFlowLogs(vpcs, flowLogs []map[string]interface{}) []ResourceResult {
+	logged := make(map[string]string)
+	for _, fl := range flowLogs {
+		if status := str(fl, "FlowLogStatus"); status == "" || status == "ACTIVE" {
+			logged[str(fl, "ResourceId")] = str(fl, "FlowLogId")
+		}
+	}
+	var results []ResourceResult
+	for _, v := range vpcs {
+		id := str(v, "VpcId")
+		if flowLog, ok := logged[id]; ok {
+			results = append(results, pass(id, strings.TrimSpace("flow log "+flowLog)))
+		} else {
+			results = append(results, fail(id, "no active flow logs"))
+		}
+	}
+	return results
+}
+
+// defaultGroupsClosed checks that default security groups have no rules.
+func defaultGroupsClosed(groups []map[string]interface{}) []ResourceResult {
+	var results []ResourceResult
+	for _, g := range groups {
+		if str(g, "GroupName") != "default" {
+			continue
+		}
+		resource := fmt.Sprintf("%s (%s default)", str(g, "GroupId"), str(g, "VpcId"))
+		in, _ := g["IpPermissions"].([]interface{})
+		out, _ := g["IpPermissionsEgress"].([]interface{})
+		if len(in)+len(out) > 0 {
+			results = append(results, fail(resource, fmt.Sprintf("%d inbound and %d outbound rule(s)", len(in), len(out))))
+		} else {
+			results = append(results, pass(resource, "no rules"))
+		}
+	}
+	return results
+}
+
+// publicPorts may be open to the internet; any other inbound rule from
+// 0.0.0.0/0 or ::/0 breaks segmentation.
+var publicPorts = map[int]bool{80: true, 443: true}
+
+// internetIngress checks that security groups only admit internet traffic on
+// web ports.
+func internetIngress(groups []map[string]interface{}) []ResourceResult {
+	var results []ResourceResult
+	for _, g := range groups {
+		resource := fmt.Sprintf("%s (%s)", str(g, "GroupId"), str(g, "GroupName"))
+		var open []string
+		for _, p := range asList(g["IpPermissions"]) {
+			perm := object(p)
+			if !fromInternet(perm) {
+				continue
+			}
+			from, to := portNumber(perm["FromPort"]), portNumber(perm["ToPort"])
+			switch {
+			case str(perm, "IpProtocol") == "-1":
+				open = append(open, "all traffic")
+			case from == to && publicPorts[from]:
+				continue
+			case from == to:
+				open = append(open, fmt.Sprintf("%s/%d", str(perm, "IpProtocol"), from))
+			default:
+				open = append(open, fmt.Sprintf("%s/%d-%d", str(perm, "IpProtocol"), from, to))
+			}
+		}
+		if len(open) > 0 {
+			results = append(results, fail(resource, "open to the internet: "+strings.Join(open, ", ")))
+		} else {
+			results = append(results, pass(resource, "no internet ingress beyond 80/443"))
+		}
+	}
+	return results
+}
+
+func fromInternet(perm map[string]interface{}) bool {
+	for _, r := range asList(perm["IpRanges"]) {
+		if str(object(r), "CidrIp") == "0.0.0.0/0" {
+			return true
+		}
+	}
+	for _, r := range asList(perm["Ipv6Ranges"]) {
+		if str(object(r), "CidrIpv6") == "::/0" {
+			return true
+		}
+	}
+	return false
+}
+
+func portNumber(v interface{}) int {
+	f, _ := v.(float64)
+	return int(f)
+}
+
+// ── Load balancers ─────────────────────────────────────────────
+
+// listenerTLS checks that listeners use TLS 1.2+ policies, and that plain
+// HTTP listeners only redirect to HTTPS.
+func listenerTLS(listeners []map[string]interface{}) []ResourceResult {
+	var results []ResourceResult
+	for _, l := range listeners {
+		resource := str(l, "ListenerArn")
+		if resource == "" {
+			resource = fmt.Sprintf("listener %s:%d", str(l, "Protocol"), portNumber(l["Port"]))
+		}
+		switch protocol := str(l, "Protocol"); protocol {
+		case "HTTPS", "TLS":
+			if policy := str(l, "SslPolicy"); strings.Contains(policy, "1-2") || strings.Contains(policy, "TLS13") {
+				results = append(results, pass(resource, "TLS policy "+policy))
+			} else {
+				results = append(results, fail(resource, fmt.Sprintf("TLS policy '%s' allows protocols older than TLS 1.2", policy)))
+			}
+		case "HTTP":
+			if redirectsToHTTPS(l) {
+				results = append(results, pass(resource, "redirects HTTP to HTTPS"))
+			} else {
+				results = append(results, fail(resource, "serves plain HTTP"))
+			}
+		default:
+			results = append(results, fail(resource, fmt.Sprintf("unencrypted %s listener", protocol)))
+		}
+	}
+	return results
+}
+
+func redirectsToHTTPS(listener map[string]interface{}) bool {
+	for _, a := range asList(listener["DefaultActions"]) {
+		action := object(a)
+		if str(action, "Type") == "redirect" && str(object(action["RedirectConfig"]), "Protocol") == "HTTPS" {
+			return true
+		}
+	}
+	return false
+}
+
+// ── Logging and reviews ────────────────────────────────────────
+
+// logRetention checks that log groups keep logs for at least days. Groups
+// without a retention setting never expire.
+func logRetention(groups []map[string]interface{}, days int) []ResourceResult {
+	var results []ResourceResult
+	for _, g := range groups {
+		resource := "log-group/" + str(g, "logGroupName")
+		retention := portNumber(g["retentionInDays"])
+		switch {
+		case g["retentionInDays"] == nil:
+			results = append(results, pass(resource, "never expires"))
+		case retention < days:
+			results = append(results, fail(resource, fmt.Sprintf("retained %d days, need %d", retention, days)))
+		default:
+			results = append(results, pass(resource, fmt.Sprintf("retained %d days", retention)))
+		}
+	}
+	return results
+}
+
+// recentReviews checks that every review scope was reviewed within days of
+// now. Reviews are {scope, reviewer, reviewed_at}; the latest per scope counts.
+func recentReviews(reviews []map[string]interface{}, now time.Time, days int) []ResourceResult {
+	if len(reviews) == 0 {
+		return []ResourceResult{fail("access reviews", "no access reviews recorded")}
+	}
+	latest := make(map[string]map[string]interface{})
+	var scopes []string
+	for _, r := range reviews {
+		scope := str(r, "scope")
+		prev, seen := latest[scope]
+		if !seen {
+			scopes = append(scopes, scope)
+		}
+		at, _ := reportTime(r, "reviewed_at")
+		prevAt, _ := reportTime(prev, "reviewed_at")
+		if !seen || at.After(prevAt) {
+			latest[scope] = r
+		}
+	}
+	var results []ResourceResult
+	for _, scope := range scopes {
+		r := latest[scope]
+		at, ok := reportTime(r, "reviewed_at")
+		switch {
+		case !ok:
+			results = append(results, fail(scope, fmt.Sprintf("invalid reviewed_at '%s'", str(r, "reviewed_at"))))
+		case now.Sub(at) > time.Duration(days)*24*time.Hour:
+			results = append(results, fail(scope, fmt.Sprintf("last reviewed %s by %s", at.Format("2006-01-02"), str(r, "reviewer"))))
+		default:
+			results = append(results, pass(scope, fmt.Sprintf("reviewed %s by %s", at.Format("2006-01-02"), str(r, "reviewer"))))
+		}
+	}
+	return results
+}
+
+// ── Vulnerability scans ────────────────────────────────────────
+
+// scanFindings checks that no scanned artifact has vulnerabilities of the
+// given severities.
+func scanFindings(reports []map[string]interface{}, severities ...string) []ResourceResult {
+	blocking := make(map[string]bool)
+	for _, s := range severities {
+		blocking[s] = true
+	}
+	var results []ResourceResult
+	for _, r := range reports {
+		counts := make(map[string]int)
+		for _, target := range asList(r["Results"]) {
+			for _, v := range asList(object(target)["Vulnerabilities"]) {
+				if sev := str(object(v), "Severity"); blocking[sev] {
+					counts[sev]++
+				}
+			}
+		}
+		var found []string
+		for _, sev := range severities {
+			if counts[sev] > 0 {
+				found = append(found, fmt.Sprintf("%d %s", counts[sev], sev))
+			}
+		}
+		if len(found) > 0 {
+			results = append(results, fail(str(r, "ArtifactName"), strings.Join(found, ", ")+" vulnerabilities"))
+		} else {
+			results = append(results, pass(str(r, "ArtifactName"), "no "+strings.Join(severities, "/")+" vulnerabilities"))
+		}
+	}
+	return results
+}
+
+// ── Storage ────────────────────────────────────────────────────
+
+func bucketLogging(buckets []map[string]interface{}) []ResourceResult {
+	var results []ResourceResult
+	for _, b := range buckets {
+		logging := object(object(b["Logging"])["LoggingEnabled"])
+		if logging == nil {
+			results = append(results, fail("bucket/"+str(b, "Name"), "no access logging"))
+		} else {
+			results = append(results, pass("bucket/"+str(b, "Name"), "logs to "+str(logging, "TargetBucket")))
+		}
+	}
+	return results
+}
+
+func bucketEncryption(buckets []map[string]interface{}) []ResourceResult {
+	var results []ResourceResult
+	for _, b := range buckets {
+		sse := object(object(b["Encryption"])["ServerSideEncryptionConfiguration"])
+		rules, _ := sse["Rules"].([]interface{})
+		if len(rules) == 0 {
+			results = append(results, fail("bucket/"+str(b, "Name"), "no default encryption"))
+			continue
+		}
+		algorithm := str(object(object(rules[0])["ApplyServerSideEncryptionByDefault"]), "SSEAlgorithm")
+		results = append(results, pass("bucket/"+str(b, "Name"), strings.TrimSpace("encrypted "+algorithm)))
+	}
+	return results
+}
+
+func bucketVersioning(buckets []map[string]interface{}) []ResourceResult {
+	var results []ResourceResult
+	for _, b := range buckets {
+		if status := str(object(b["Versioning"]), "Status"); status == "Enabled" {
+			results = append(results, pass("bucket/"+str(b, "Name"), "versioning enabled"))
+		} else {
+			results = append(results, fail("bucket/"+str(b, "Name"), "versioning not enabled"))
+		}
+	}
+	return results
+}
+
+// encrypted checks that the boolean field is true on every resource.
+func encrypted(resources []map[string]interface{}, idField, field, kind string) []ResourceResult {
+	var results []ResourceResult
+	for _, r := range resources {
+		if truthy(r[field]) {
+			results = append(results, pass(kind+"/"+str(r, idField), "encrypted"))
+		} else {
+			results = append(results, fail(kind+"/"+str(r, idField), "not encrypted"))
+		}
+	}
+	return results
+}
+
+// ── Value helpers ──────────────────────────────────────────────
+
+func str(m map[string]interface{}, key string) string {
+	switch v := m[key].(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// truthy accepts JSON booleans and the "true"/"false" strings of CSV exports.
+func truthy(v interface{}) bool {
+	switch b := v.(type) {
+	case bool:
+		return b
+	case string:
+		return strings.EqualFold(b, "true")
+	}
+	return false
+}
+
+func object(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
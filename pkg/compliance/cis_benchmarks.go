@@ -10,13 +10,7 @@ func CISBenchmarks() []*Check {
 			Description: "The root account has unrestricted access. Verify it has not been used recently.",
 			Severity:    SeverityCritical,
 			Category:    "Identity and Access Management",
-			CheckFunc: func() CheckResult {
-				return CheckResult{
-					Status:      StatusWarn,
-					Details:     "Cannot verify — requires AWS API access to check root account last login",
-					Remediation: "Run: aws iam get-credential-report and verify root LastUsedDate > 90 days",
-				}
-			},
+			Technical:   []string{"iam-root-unused"},
 		},
 		{
 			ID:          "CIS-1.2",
@@ -25,13 +19,7 @@ func CISBenchmarks() []*Check {
 			Description: "Multi-factor authentication adds a second layer of protection.",
 			Severity:    SeverityHigh,
 			Category:    "Identity and Access Management",
-			CheckFunc: func() CheckResult {
-				return CheckResult{
-					Status:      StatusWarn,
-					Details:     "Cannot verify — requires AWS API access to list IAM users and MFA devices",
-					Remediation: "Run: aws iam list-users + aws iam list-mfa-devices for each user",
-				}
-			},
+			Technical:   []string{"iam-console-mfa"},
 		},
 		{
 			ID:          "CIS-1.3",
@@ -40,13 +28,7 @@ func CISBenchmarks() []*Check {
 			Description: "Stale credentials increase attack surface.",
 			Severity:    SeverityMedium,
 			Category:    "Identity and Access Management",
-			CheckFunc: func() CheckResult {
-				return CheckResult{
-					Status:      StatusWarn,
-					Details:     "Cannot verify — requires credential report analysis",
-					Remediation: "Run: aws iam generate-credential-report && aws iam get-credential-report",
-				}
-			},
+			Technical:   []string{"iam-stale-credentials-90d"},
 		},
 		{
 			ID:          "CIS-2.1",
@@ -55,13 +37,7 @@ func CISBenchmarks() []*Check {
 			Description: "CloudTrail logs all API calls for audit and forensic purposes.",
 			Severity:    SeverityHigh,
 			Category:    "Logging",
-			CheckFunc: func() CheckResult {
-				return CheckResult{
-					Status:      StatusWarn,
-					Details:     "Cannot verify — requires AWS API: aws cloudtrail describe-trails",
-					Remediation: "Enable multi-region CloudTrail: aws cloudtrail create-trail --is-multi-region-trail",
-				}
-			},
+			Technical:   []string{"cloudtrail-multi-region"},
 		},
 		{
 			ID:          "CIS-2.2",
@@ -70,13 +46,7 @@ func CISBenchmarks() []*Check {
 			Description: "Log file validation ensures logs are not tampered with.",
 			Severity:    SeverityMedium,
 			Category:    "Logging",
-			CheckFunc: func() CheckResult {
-				return CheckResult{
-					Status:      StatusWarn,
-					Details:     "Cannot verify — requires CloudTrail trail configuration",
-					Remediation: "aws cloudtrail update-trail --enable-log-file-validation",
-				}
-			},
+			Technical:   []string{"cloudtrail-log-validation"},
 		},
 		{
 			ID:          "CIS-2.3",
@@ -85,13 +55,7 @@ func CISBenchmarks() []*Check {
 			Description: "Server-side encryption protects log data at rest.",
 			Severity:    SeverityHigh,
 			Category:    "Logging",
-			CheckFunc: func() CheckResult {
-				return CheckResult{
-					Status:      StatusWarn,
-					Details:     "Cannot verify — requires trail encryption configuration check",
-					Remediation: "aws cloudtrail update-trail --kms-key-id <KMS_KEY_ARN>",
-				}
-			},
+			Technical:   []string{"cloudtrail-kms"},
 		},
 		{
 			ID:          "CIS-3.1",
@@ -100,13 +64,7 @@ func CISBenchmarks() []*Check {
 			Description: "VPC flow logs capture IP traffic for network monitoring.",
 			Severity:    SeverityMedium,
 			Category:    "Networking",
-			CheckFunc: func() CheckResult {
-				return CheckResult{
-					Status:      StatusWarn,
-					Details:     "Cannot verify — requires VPC and flow log enumeration",
-					Remediation: "aws ec2 create-flow-logs --resource-ids <VPC_ID> --traffic-type ALL",
-				}
-			},
+			Technical:   []string{"vpc-flow-logs"},
 		},
 		{
 			ID:          "CIS-3.2",
@@ -115,13 +73,7 @@ func CISBenchmarks() []*Check {
 			Description: "The default security group should not allow any inbound/outbound traffic.",
 			Severity:    SeverityHigh,
 			Category:    "Networking",
-			CheckFunc: func() CheckResult {
-				return CheckResult{
-					Status:      StatusWarn,
-					Details:     "Cannot verify — requires SG rule inspection",
-					Remediation: "Remove all rules from default SGs: aws ec2 revoke-security-group-ingress",
-				}
-			},
+			Technical:   []string{"sg-default-closed"},
 		},
 		{
 			ID:          "CIS-4.1",
@@ -130,13 +82,7 @@ func CISBenchmarks() []*Check {
 			Description: "Access logging tracks requests made to S3 buckets.",
 			Severity:    SeverityMedium,
 			Category:    "Storage",
-			CheckFunc: func() CheckResult {
-				return CheckResult{
-					Status:      StatusWarn,
-					Details:     "Cannot verify — requires S3 bucket logging configuration check",
-					Remediation: "aws s3api put-bucket-logging --bucket <BUCKET> --bucket-logging-status ...",
-				}
-			},
+			Technical:   []string{"s3-access-logging"},
 		},
 		{
 			ID:          "CIS-4.2",
@@ -145,13 +91,7 @@ func CISBenchmarks() []*Check {
 			Description: "Encryption at rest protects data stored in S3.",
 			Severity:    SeverityHigh,
 			Category:    "Storage",
-			CheckFunc: func() CheckResult {
-				return CheckResult{
-					Status:      StatusWarn,
-					Details:     "Cannot verify — requires S3 encryption configuration check",
-					Remediation: "aws s3api put-bucket-encryption --bucket <BUCKET> --sse AES256",
-				}
-			},
+			Technical:   []string{"s3-encryption"},
 		},
 	}
 }
@@ -166,13 +106,7 @@ func SOC2Controls() []*Check {
 			Description: "Ensure access to systems is restricted and monitored.",
 			Severity:    SeverityHigh,
 			Category:    "Access Control",
-			CheckFunc: func() CheckResult {
-				return CheckResult{
-					Status:      StatusWarn,
-					Details:     "Verify IAM policies follow least-privilege principle",
-					Remediation: "Review IAM policies and remove overly permissive access",
-				}
-			},
+			Technical:   []string{"iam-root-access-keys", "iam-console-mfa", "s3-encryption"},
 		},
 		{
 			ID:          "SOC2-CC6.2",
@@ -181,13 +115,7 @@ func SOC2Controls() []*Check {
 			Description: "Ensure timely provisioning and removal of access.",
 			Severity:    SeverityHigh,
 			Category:    "Access Control",
-			CheckFunc: func() CheckResult {
-				return CheckResult{
-					Status:      StatusWarn,
-					Details:     "Verify inactive accounts are disabled within 30 days",
-					Remediation: "Implement automated deprovisioning via SSO integration",
-				}
-			},
+			Technical:   []string{"iam-stale-credentials-30d"},
 		},
 		{
 			ID:          "SOC2-CC7.1",
@@ -196,13 +124,7 @@ func SOC2Controls() []*Check {
 			Description: "Ensure infrastructure monitoring and alerting is in place.",
 			Severity:    SeverityMedium,
 			Category:    "Monitoring",
-			CheckFunc: func() CheckResult {
-				return CheckResult{
-					Status:      StatusWarn,
-					Details:     "Verify CloudWatch/Datadog monitoring covers all production resources",
-					Remediation: "Enable comprehensive monitoring with alerting for anomalies",
-				}
-			},
+			Technical:   []string{"cloudtrail-multi-region", "vpc-flow-logs"},
 		},
 		{
 			ID:          "SOC2-CC8.1",
@@ -211,13 +133,7 @@ func SOC2Controls() []*Check {
 			Description: "Ensure all infrastructure changes go through a controlled process.",
 			Severity:    SeverityHigh,
 			Category:    "Change Management",
-			CheckFunc: func() CheckResult {
-				return CheckResult{
-					Status:      StatusWarn,
-					Details:     "Verify all changes go through IaC with peer review",
-					Remediation: "Enforce Terraform/Pulumi for all infra changes with PR reviews",
-				}
-			},
+			Technical:   []string{"iac-drift"},
 		},
 	}
 }
@@ -232,13 +148,7 @@ func HIPAAControls() []*Check {
 			Description: "Each user accessing ePHI must have a unique ID.",
 			Severity:    SeverityCritical,
 			Category:    "Access Control",
-			CheckFunc: func() CheckResult {
-				return CheckResult{
-					Status:      StatusWarn,
-					Details:     "Verify no shared IAM accounts for systems handling PHI",
-					Remediation: "Use SSO with individual user accounts; disable shared credentials",
-				}
-			},
+			Technical:   []string{"iam-root-unused", "iam-root-access-keys"},
 		},
 		{
 			ID:          "HIPAA-164.312c",
@@ -247,13 +157,7 @@ func HIPAAControls() []*Check {
 			Description: "Ensure ePHI is not improperly altered or destroyed.",
 			Severity:    SeverityCritical,
 			Category:    "Data Integrity",
-			CheckFunc: func() CheckResult {
-				return CheckResult{
-					Status:      StatusWarn,
-					Details:     "Verify S3 versioning and MFA-delete are enabled for PHI buckets",
-					Remediation: "Enable S3 versioning: aws s3api put-bucket-versioning --status Enabled",
-				}
-			},
+			Technical:   []string{"s3-versioning"},
 		},
 		{
 			ID:          "HIPAA-164.312e",
@@ -262,13 +166,7 @@ func HIPAAControls() []*Check {
 			Description: "All ePHI must be encrypted in transit (TLS) and at rest (AES-256/KMS).",
 			Severity:    SeverityCritical,
 			Category:    "Encryption",
-			CheckFunc: func() CheckResult {
-				return CheckResult{
-					Status:      StatusWarn,
-					Details:     "Verify TLS 1.2+ enforced on all endpoints; S3/RDS/EBS encrypted",
-					Remediation: "Enable encryption on all storage; enforce TLS on ALBs and CloudFront",
-				}
-			},
+			Technical:   []string{"s3-encryption", "ebs-encryption", "rds-encryption"},
 		},
 	}
 }
@@ -3,9 +3,13 @@
 package compliance
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/parth14193/ownbot/pkg/breakglass"
+	"github.com/parth14193/ownbot/pkg/policy"
 )
 
 // Framework identifies a compliance standard.
@@ -27,6 +31,9 @@ const (
 	StatusFail CheckStatus = "FAIL"
 	StatusWarn CheckStatus = "WARN"
 	StatusSkip CheckStatus = "SKIP"
+
+	// StatusWaived marks a failure whose risk was accepted (see Waiver).
+	StatusWaived CheckStatus = "WAIVED"
 )
 
 // Severity classifies check importance.
@@ -39,6 +46,10 @@ const (
 	SeverityCritical Severity = "CRITICAL"
 )
 
+// CheckFunc evaluates a check against collected evidence. It returns SKIP
+// when the evidence it needs was not collected.
+type CheckFunc func(ev *Evidence) CheckResult
+
 // Check defines a single compliance check.
 type Check struct {
 	ID          string    `json:"id"`
@@ -47,7 +58,11 @@ type Check struct {
 	Description string    `json:"description"`
 	Severity    Severity  `json:"severity"`
 	Category    string    `json:"category"`
-	CheckFunc   func() CheckResult `json:"-"`
+	CheckFunc   CheckFunc `json:"-"`
+
+	// Technical lists the technical checks that satisfy this control; it is
+	// used when CheckFunc is nil.
+	Technical []string `json:"technical,omitempty"`
 }
 
 // CheckResult is the outcome of a single compliance check.
@@ -56,33 +71,93 @@ type CheckResult struct {
 	Status      CheckStatus `json:"status"`
 	Title       string      `json:"title"`
 	Severity    Severity    `json:"severity"`
+	Category    string      `json:"category,omitempty"`
 	Details     string      `json:"details"`
 	Remediation string      `json:"remediation"`
+
+	// Technical holds the results of the technical checks the control maps to.
+	Technical []TechnicalResult `json:"technical,omitempty"`
+
+	// Resources holds the per-resource outcomes the status was derived from.
+	Resources []ResourceResult `json:"resources,omitempty"`
+
+	// Actions are the skill runs that fix failing resources.
+	Actions []Action `json:"actions,omitempty"`
+
+	// Waivers that waived the check or some of its resources, and the IDs of
+	// expired waivers that would otherwise have applied.
+	Waivers       []*Waiver `json:"waivers,omitempty"`
+	LapsedWaivers []string  `json:"lapsed_waivers,omitempty"`
+}
+
+// ResourceResult is the outcome of a check for one resource, such as an IAM
+// user, VPC or bucket.
+type ResourceResult struct {
+	Resource string      `json:"resource"`
+	Status   CheckStatus `json:"status"`
+	Details  string      `json:"details,omitempty"`
+	Waiver   string      `json:"waiver,omitempty"` // ID of the waiver, when WAIVED
 }
 
 // Report aggregates compliance check results for a framework.
 type Report struct {
-	Framework   Framework      `json:"framework"`
-	Timestamp   time.Time      `json:"timestamp"`
-	Results     []CheckResult  `json:"results"`
-	TotalChecks int            `json:"total_checks"`
-	Passed      int            `json:"passed"`
-	Failed      int            `json:"failed"`
-	Warnings    int            `json:"warnings"`
-	Skipped     int            `json:"skipped"`
-	Score       float64        `json:"score"` // percentage passed
+	Framework   Framework     `json:"framework"`
+	Environment string        `json:"environment,omitempty"`
+	Timestamp   time.Time     `json:"timestamp"`
+	Results     []CheckResult `json:"results"`
+	TotalChecks int           `json:"total_checks"`
+	Passed      int           `json:"passed"`
+	Failed      int           `json:"failed"`
+	Warnings    int           `json:"warnings"`
+	Skipped     int           `json:"skipped"`
+	Waived      int           `json:"waived"`
+	Score       float64       `json:"score"`              // severity-weighted percentage, SKIPs and WAIVEDs excluded
+	Unscored    bool          `json:"unscored,omitempty"` // no check was evaluated: Score is N/A and any threshold is breached
+
+	// Weighted subscores per category and the scoring thresholds breached.
+	Categories []CategoryScore `json:"categories,omitempty"`
+	Breaches   []string        `json:"breaches,omitempty"`
+
+	// Evidence keys the audit was evaluated against and collection problems.
+	Evidence         []string `json:"evidence,omitempty"`
+	CollectionErrors []string `json:"collection_errors,omitempty"`
+
+	// Break-glass activity recorded during the audit period, which starts
+	// at PeriodStart and ends at Timestamp.
+	PeriodStart         time.Time          `json:"period_start,omitempty"`
+	BreakGlassEntries   []breakglass.Entry `json:"break_glass_entries,omitempty"`
+	BreakGlassIntegrity string             `json:"break_glass_integrity,omitempty"`
 }
 
+// DefaultAuditPeriod is how far back reports list break-glass activity
+// unless set with SetPeriod.
+const DefaultAuditPeriod = 90 * 24 * time.Hour
+
 // Auditor runs compliance audits against a specific framework.
 type Auditor struct {
-	checks map[Framework][]*Check
+	checks    map[Framework][]*Check
+	technical map[string]*TechnicalCheck
+	ledger    *breakglass.Ledger
+	period    time.Duration
+	collector Collector
+	env       string
+	scoring   ScoringModel
+	waivers   *WaiverRegistry
 }
 
-// NewAuditor creates a new ComplianceAuditor.
+// NewAuditor creates a new ComplianceAuditor with the built-in technical
+// checks.
 func NewAuditor() *Auditor {
-	return &Auditor{
-		checks: make(map[Framework][]*Check),
+	a := &Auditor{
+		checks:    make(map[Framework][]*Check),
+		technical: make(map[string]*TechnicalCheck),
+		scoring:   DefaultScoringModel(),
+		period:    DefaultAuditPeriod,
+	}
+	for _, tc := range TechnicalChecks() {
+		a.RegisterTechnical(tc)
 	}
+	return a
 }
 
 // Register adds a check to the auditor.
@@ -90,6 +165,53 @@ func (a *Auditor) Register(check *Check) {
 	a.checks[check.Framework] = append(a.checks[check.Framework], check)
 }
 
+// RegisterTechnical adds a technical check, replacing one with the same ID.
+func (a *Auditor) RegisterTechnical(tc *TechnicalCheck) {
+	a.technical[tc.ID] = tc
+}
+
+// SetBreakGlassLedger includes break-glass activity from ledger in reports.
+func (a *Auditor) SetBreakGlassLedger(ledger *breakglass.Ledger) {
+	a.ledger = ledger
+}
+
+// SetPeriod sets how far back from the audit time reports list break-glass
+// activity.
+func (a *Auditor) SetPeriod(d time.Duration) {
+	a.period = d
+}
+
+// SetCollector sets where audits gather their evidence. Without a collector
+// every check that needs evidence is skipped.
+func (a *Auditor) SetCollector(c Collector) {
+	a.collector = c
+}
+
+// SetEnvironment sets the environment reports are recorded against.
+func (a *Auditor) SetEnvironment(env string) {
+	a.env = env
+}
+
+// SetScoring sets the scoring model and thresholds reports are scored with.
+func (a *Auditor) SetScoring(m ScoringModel) {
+	a.scoring = m
+}
+
+// Scoring returns the scoring model in use.
+func (a *Auditor) Scoring() ScoringModel {
+	return a.scoring
+}
+
+// SetWaivers sets the waivers applied to failed checks.
+func (a *Auditor) SetWaivers(w *WaiverRegistry) {
+	a.waivers = w
+}
+
+// Waivers returns the waivers applied to failed checks, or nil.
+func (a *Auditor) Waivers() *WaiverRegistry {
+	return a.waivers
+}
+
 // LoadCISBenchmarks registers all CIS AWS Foundation Benchmark checks.
 func (a *Auditor) LoadCISBenchmarks() {
 	for _, check := range CISBenchmarks() {
@@ -106,6 +228,9 @@ func (a *Auditor) LoadAll() {
 	for _, check := range HIPAAControls() {
 		a.Register(check)
 	}
+	for _, check := range PCIDSSControls() {
+		a.Register(check)
+	}
 }
 
 // RunAudit executes all checks for a given framework and returns a report.
@@ -113,22 +238,30 @@ func (a *Auditor) RunAudit(framework Framework) *Report {
 	checks, exists := a.checks[framework]
 	if !exists {
 		return &Report{
-			Framework: framework,
-			Timestamp: time.Now(),
+			Framework:   framework,
+			Environment: a.env,
+			Timestamp:   time.Now(),
 		}
 	}
 
-	report := &Report{
-		Framework:   framework,
-		Timestamp:   time.Now(),
-		TotalChecks: len(checks),
-	}
+	report := &Report{Framework: framework, Environment: a.env, Timestamp: time.Now()}
+	run := a.newRun(a.collect(report))
+	a.fillReport(report, checks, run)
+	return report
+}
 
+// fillReport evaluates checks into report.
+func (a *Auditor) fillReport(report *Report, checks []*Check, run *auditRun) {
+	report.TotalChecks = len(checks)
 	for _, check := range checks {
-		result := check.CheckFunc()
+		result := run.check(check)
 		result.ID = check.ID
 		result.Title = check.Title
 		result.Severity = check.Severity
+		result.Category = check.Category
+		if a.waivers != nil {
+			a.waivers.apply(&result, report.Framework, report.Environment)
+		}
 
 		switch result.Status {
 		case StatusPass:
@@ -139,27 +272,82 @@ func (a *Auditor) RunAudit(framework Framework) *Report {
 			report.Warnings++
 		case StatusSkip:
 			report.Skipped++
+		case StatusWaived:
+			report.Waived++
 		}
 
 		report.Results = append(report.Results, result)
 	}
 
-	if report.TotalChecks > 0 {
-		report.Score = float64(report.Passed) / float64(report.TotalChecks) * 100
+	a.scoring.score(report)
+	a.attachBreakGlass(report)
+}
+
+// collect gathers evidence from the collector, recording what was collected
+// and any collection errors in the report.
+func (a *Auditor) collect(report *Report) *Evidence {
+	if a.collector == nil {
+		return NewEvidence()
+	}
+	ev, err := a.collector.Collect(context.Background())
+	if err != nil {
+		for _, line := range strings.Split(err.Error(), "\n") {
+			report.CollectionErrors = append(report.CollectionErrors, line)
+		}
 	}
+	if ev == nil {
+		ev = NewEvidence()
+	}
+	report.Evidence = ev.Keys()
+	return ev
+}
 
-	return report
+// attachBreakGlass copies the break-glass entries of the audit period into
+// the report along with the result of verifying the whole ledger.
+func (a *Auditor) attachBreakGlass(report *Report) {
+	if a.ledger == nil {
+		return
+	}
+	report.PeriodStart = report.Timestamp.Add(-a.period)
+	for _, e := range a.ledger.Entries() {
+		if !e.Timestamp.Before(report.PeriodStart) && !e.Timestamp.After(report.Timestamp) {
+			report.BreakGlassEntries = append(report.BreakGlassEntries, e)
+		}
+	}
+	if err := a.ledger.Verify(); err != nil {
+		report.BreakGlassIntegrity = "TAMPERED: " + err.Error()
+	} else {
+		report.BreakGlassIntegrity = "VERIFIED"
+	}
 }
 
-// ListFrameworks returns all frameworks with registered checks.
+// ListFrameworks returns all frameworks with registered checks, built-in
+// frameworks first.
 func (a *Auditor) ListFrameworks() []Framework {
 	var frameworks []Framework
 	for f := range a.checks {
 		frameworks = append(frameworks, f)
 	}
+	sortFrameworks(frameworks)
 	return frameworks
 }
 
+// RenderFrameworks lists the registered frameworks and their checks.
+func (a *Auditor) RenderFrameworks() string {
+	var b strings.Builder
+	frameworks := a.ListFrameworks()
+	b.WriteString(fmt.Sprintf("📚 COMPLIANCE FRAMEWORKS (%d)\n", len(frameworks)))
+	b.WriteString("─────────────────────────────────────────\n")
+	for _, fw := range frameworks {
+		kind := "built-in"
+		if !builtinFrameworks[fw] {
+			kind = "custom"
+		}
+		b.WriteString(fmt.Sprintf("  %-15s %2d checks  (%s)\n", fw, len(a.checks[fw]), kind))
+	}
+	return b.String()
+}
+
 // Render formats a compliance report for display.
 func (r *Report) Render() string {
 	var b strings.Builder
@@ -167,13 +355,48 @@ func (r *Report) Render() string {
 	b.WriteString(fmt.Sprintf("📋 COMPLIANCE REPORT: %s\n", r.Framework))
 	b.WriteString("─────────────────────────────────────────\n")
 	b.WriteString(fmt.Sprintf("Timestamp: %s\n", r.Timestamp.Format(time.RFC3339)))
-	b.WriteString(fmt.Sprintf("Score:     %.1f%% (%d/%d passed)\n\n", r.Score, r.Passed, r.TotalChecks))
+	if r.Environment != "" {
+		b.WriteString(fmt.Sprintf("Env:       %s\n", r.Environment))
+	}
+	if r.Unscored {
+		b.WriteString(fmt.Sprintf("Score:     N/A — no evaluated checks (%d skipped)\n\n", r.Skipped))
+	} else {
+		b.WriteString(fmt.Sprintf("Score:     %.1f%% weighted (%d/%d passed)\n\n", r.Score, r.Passed, r.TotalChecks))
+	}
 
 	// Summary bar
 	b.WriteString(fmt.Sprintf("  ✅ Passed:   %d\n", r.Passed))
 	b.WriteString(fmt.Sprintf("  ❌ Failed:   %d\n", r.Failed))
 	b.WriteString(fmt.Sprintf("  ⚠️  Warnings: %d\n", r.Warnings))
-	b.WriteString(fmt.Sprintf("  ⏭️  Skipped:  %d\n\n", r.Skipped))
+	b.WriteString(fmt.Sprintf("  ⏭️  Skipped:  %d\n", r.Skipped))
+	if r.Waived > 0 {
+		b.WriteString(fmt.Sprintf("  🔕 Waived:   %d\n", r.Waived))
+	}
+	b.WriteString("\n")
+
+	if len(r.Categories) > 1 {
+		b.WriteString("Categories:\n")
+		for _, cs := range r.Categories {
+			b.WriteString(fmt.Sprintf("  %5.1f%%  %s (%d evaluated, %d failed)\n", cs.Score, cs.Category, cs.Evaluated, cs.Failed))
+		}
+		b.WriteString("\n")
+	}
+	for _, breach := range r.Breaches {
+		b.WriteString(fmt.Sprintf("  🚫 Threshold: %s\n", breach))
+	}
+	if len(r.Breaches) > 0 {
+		b.WriteString("\n")
+	}
+
+	if len(r.Evidence) > 0 {
+		b.WriteString(fmt.Sprintf("Evidence:  %s\n\n", strings.Join(r.Evidence, ", ")))
+	}
+	for _, e := range r.CollectionErrors {
+		b.WriteString(fmt.Sprintf("  ⚠️  Collection: %s\n", e))
+	}
+	if len(r.CollectionErrors) > 0 {
+		b.WriteString("\n")
+	}
 
 	// Failed checks (show first)
 	failedChecks := filterByStatus(r.Results, StatusFail)
@@ -185,9 +408,36 @@ func (r *Report) Render() string {
 			if c.Details != "" {
 				b.WriteString(fmt.Sprintf("          Detail: %s\n", c.Details))
 			}
+			for _, res := range filterResources(c.Resources, StatusFail) {
+				b.WriteString(fmt.Sprintf("          ✗ %s — %s\n", res.Resource, res.Details))
+			}
 			if c.Remediation != "" {
 				b.WriteString(fmt.Sprintf("          Fix: %s\n", c.Remediation))
 			}
+			for _, a := range c.Actions {
+				b.WriteString(fmt.Sprintf("          🔧 %s → %s\n", a.Resource, a.Skill))
+			}
+			for _, id := range c.LapsedWaivers {
+				b.WriteString(fmt.Sprintf("          ⌛ Waiver %s expired\n", id))
+			}
+		}
+	}
+
+	// Waived checks and resources, with the waivers that accepted them
+	if waived := waivedResults(r.Results); len(waived) > 0 {
+		b.WriteString(fmt.Sprintf("\n🔕 WAIVED (%d)\n", len(waived)))
+		for _, c := range waived {
+			b.WriteString(fmt.Sprintf("  [%s] %s — %s", c.Severity, c.ID, c.Title))
+			if c.Status != StatusWaived {
+				b.WriteString(" (partially)")
+			}
+			b.WriteString("\n")
+			for _, res := range filterResources(c.Resources, StatusWaived) {
+				b.WriteString(fmt.Sprintf("          ~ %s — %s (%s)\n", res.Resource, res.Details, res.Waiver))
+			}
+			for _, w := range c.Waivers {
+				b.WriteString(fmt.Sprintf("          %s approved by %s until %s: %s\n", w.ID, w.Approver, policy.FormatExpiry(w.Expires), w.Justification))
+			}
 		}
 	}
 
@@ -200,9 +450,59 @@ func (r *Report) Render() string {
 		}
 	}
 
+	// Skipped checks
+	skipped := filterByStatus(r.Results, StatusSkip)
+	if len(skipped) > 0 {
+		b.WriteString(fmt.Sprintf("\n⏭️  SKIPPED (%d)\n", len(skipped)))
+		for _, c := range skipped {
+			b.WriteString(fmt.Sprintf("  [%s] %s — %s", c.Severity, c.ID, c.Title))
+			if c.Details != "" {
+				b.WriteString(" (" + c.Details + ")")
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	// Break-glass overrides
+	if r.BreakGlassIntegrity != "" {
+		b.WriteString(fmt.Sprintf("\n🔓 BREAK-GLASS ACTIVITY since %s (%d entries, ledger %s)\n", r.PeriodStart.Format("2006-01-02"), len(r.BreakGlassEntries), r.BreakGlassIntegrity))
+		for _, e := range r.BreakGlassEntries {
+			b.WriteString(fmt.Sprintf("  %s %-15s %s (%s)", e.Timestamp.Format(time.RFC3339), e.Event, e.User, e.IncidentID))
+			if e.SkillName != "" {
+				b.WriteString(" " + e.SkillName)
+			}
+			b.WriteString("\n")
+			if e.Justification != "" {
+				b.WriteString(fmt.Sprintf("          Justification: %s\n", e.Justification))
+			}
+		}
+	}
+
 	return b.String()
 }
 
+func filterResources(results []ResourceResult, status CheckStatus) []ResourceResult {
+	var filtered []ResourceResult
+	for _, r := range results {
+		if r.Status == status {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
+// waivedResults returns the results with waived resources or that were
+// waived entirely.
+func waivedResults(results []CheckResult) []CheckResult {
+	var filtered []CheckResult
+	for _, r := range results {
+		if len(r.Waivers) > 0 {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
 func filterByStatus(results []CheckResult, status CheckStatus) []CheckResult {
 	var filtered []CheckResult
 	for _, r := range results {
@@ -1,9 +1,26 @@
 package compliance_test
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/parth14193/ownbot/pkg/breakglass"
 	"github.com/parth14193/ownbot/pkg/compliance"
+	"github.com/parth14193/ownbot/pkg/config"
+	"github.com/parth14193/ownbot/pkg/core"
+	"github.com/parth14193/ownbot/pkg/executor"
+	"github.com/parth14193/ownbot/pkg/notify"
+	"github.com/parth14193/ownbot/pkg/planner"
+	"github.com/parth14193/ownbot/pkg/safety"
+	"github.com/parth14193/ownbot/pkg/skills"
 )
 
 func TestCISAudit(t *testing.T) {
@@ -51,13 +68,13 @@ func TestReportScoring(t *testing.T) {
 	a := compliance.NewAuditor()
 	a.Register(&compliance.Check{
 		ID: "TEST-1", Framework: "TEST", Title: "Pass test",
-		CheckFunc: func() compliance.CheckResult {
+		CheckFunc: func(*compliance.Evidence) compliance.CheckResult {
 			return compliance.CheckResult{Status: compliance.StatusPass}
 		},
 	})
 	a.Register(&compliance.Check{
 		ID: "TEST-2", Framework: "TEST", Title: "Fail test",
-		CheckFunc: func() compliance.CheckResult {
+		CheckFunc: func(*compliance.Evidence) compliance.CheckResult {
 			return compliance.CheckResult{Status: compliance.StatusFail}
 		},
 	})
@@ -83,3 +100,950 @@ func TestReportRender(t *testing.T) {
 		t.Error("render should produce output")
 	}
 }
+
+func TestReportListsBreakGlass(t *testing.T) {
+	ledger := breakglass.NewLedger([]byte("0123456789abcdef"))
+	_, _ = ledger.Append(breakglass.Entry{Timestamp: time.Now().AddDate(0, -6, 0), Event: breakglass.EventActivated, User: "bob", IncidentID: "INC-7", Justification: "old outage, outside the audit period"})
+	_, _ = ledger.Append(breakglass.Entry{Event: breakglass.EventActivated, User: "alice", IncidentID: "INC-42", Justification: "primary database outage"})
+
+	a := compliance.NewAuditor()
+	a.LoadCISBenchmarks()
+	a.SetBreakGlassLedger(ledger)
+	report := a.RunAudit(compliance.FrameworkCIS)
+
+	if len(report.BreakGlassEntries) != 1 || report.BreakGlassEntries[0].IncidentID != "INC-42" || report.BreakGlassIntegrity != "VERIFIED" {
+		t.Errorf("expected the one verified break-glass entry of the audit period, got %+v (%s)", report.BreakGlassEntries, report.BreakGlassIntegrity)
+	}
+	if !strings.Contains(report.Render(), "INC-42") {
+		t.Error("rendered report should list break-glass activity")
+	}
+}
+
+const evidenceFixture = `{
+  "collected_at": "2026-03-01T00:00:00Z",
+  "iam.credential_report": [
+    {"user": "<root_account>", "password_last_used": "2025-06-01T00:00:00+00:00", "access_key_1_active": "false", "access_key_2_active": "false"},
+    {"user": "alice", "password_enabled": "true", "mfa_active": "true", "password_last_used": "2026-02-20T00:00:00+00:00"},
+    {"user": "bob", "password_enabled": "true", "mfa_active": "false", "password_last_used": "2025-10-01T00:00:00+00:00"}
+  ],
+  "cloudtrail.trails": [
+    {"Name": "org-trail", "IsMultiRegionTrail": true, "LogFileValidationEnabled": true}
+  ],
+  "ec2.vpcs": [{"VpcId": "vpc-1"}, {"VpcId": "vpc-2"}],
+  "ec2.flow_logs": [{"ResourceId": "vpc-1", "FlowLogStatus": "ACTIVE"}]
+}`
+
+func resultByID(report *compliance.Report, id string) compliance.CheckResult {
+	for _, r := range report.Results {
+		if r.ID == id {
+			return r
+		}
+	}
+	return compliance.CheckResult{}
+}
+
+// failing lists the failing resources of a result.
+func failing(r compliance.CheckResult) string {
+	var out []string
+	for _, res := range r.Resources {
+		if res.Status == compliance.StatusFail {
+			out = append(out, res.Resource)
+		}
+	}
+	return strings.Join(out, ",")
+}
+
+func TestEvidenceFileAudit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "evidence.json")
+	if err := os.WriteFile(path, []byte(evidenceFixture), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	a := compliance.NewAuditor()
+	a.LoadAll()
+	a.SetCollector(compliance.NewFileCollector(path))
+	report := a.RunAudit(compliance.FrameworkCIS)
+
+	want := map[string]compliance.CheckStatus{
+		"CIS-1.1": compliance.StatusPass, // root last used 9 months ago
+		"CIS-1.2": compliance.StatusFail, // bob has no MFA
+		"CIS-1.3": compliance.StatusFail, // bob's password unused for 150 days
+		"CIS-2.1": compliance.StatusPass,
+		"CIS-2.2": compliance.StatusPass,
+		"CIS-2.3": compliance.StatusFail, // no KmsKeyId
+		"CIS-3.1": compliance.StatusFail, // vpc-2 has no flow logs
+		"CIS-3.2": compliance.StatusSkip, // no security group evidence
+		"CIS-4.2": compliance.StatusSkip,
+	}
+	for id, status := range want {
+		if got := resultByID(report, id); got.Status != status {
+			t.Errorf("%s: expected %s, got %s (%s)", id, status, got.Status, got.Details)
+		}
+	}
+	if got := failing(resultByID(report, "CIS-1.2")); got != "user/bob" {
+		t.Errorf("CIS-1.2 should fail bob only, got %q", got)
+	}
+	if got := failing(resultByID(report, "CIS-3.1")); got != "vpc-2" {
+		t.Errorf("CIS-3.1 should fail vpc-2 only, got %q", got)
+	}
+	if d := resultByID(report, "CIS-4.2").Details; d != "no evidence: s3.buckets" {
+		t.Errorf("unexpected skip detail %q", d)
+	}
+	if len(report.Evidence) != 4 {
+		t.Errorf("expected 4 evidence keys, got %v", report.Evidence)
+	}
+
+	// Without a collector everything is skipped rather than guessed.
+	bare := compliance.NewAuditor()
+	bare.LoadCISBenchmarks()
+	if r := bare.RunAudit(compliance.FrameworkCIS); r.Skipped != r.TotalChecks {
+		t.Errorf("expected all checks skipped without evidence, got %d/%d", r.Skipped, r.TotalChecks)
+	}
+}
+
+func TestEvidenceDirectoryAndSkills(t *testing.T) {
+	dir := t.TempDir()
+	buckets := `[{"Name": "logs", "Encryption": {"ServerSideEncryptionConfiguration": {"Rules": [{}]}}, "Versioning": {"Status": "Enabled"}},
+	             {"Name": "phi-raw", "Versioning": {"Status": "Suspended"}}]`
+	if err := os.WriteFile(filepath.Join(dir, "s3.buckets.json"), []byte(buckets), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	ev, err := compliance.NewFileCollector(dir).Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objs, _ := ev.Objects(compliance.EvidenceBuckets); len(objs) != 2 {
+		t.Fatalf("expected 2 buckets from s3.buckets.json, got %d", len(objs))
+	}
+
+	registry := skills.NewRegistry()
+	_ = registry.Register(&core.Skill{Name: "aws.sg.audit", ReadOnly: true})
+	exec := stubExecutor(`{"SecurityGroups": [{"GroupName": "default", "GroupId": "sg-1", "VpcId": "vpc-1", "IpPermissions": [{"IpProtocol": "-1"}]}]}`)
+	skillEv, err := compliance.NewSkillCollector(registry, exec, "production", compliance.DefaultSkillSources()...).Collect(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "aws.s3.audit") {
+		t.Errorf("expected an error for unregistered source skills, got %v", err)
+	}
+	ev.Merge(skillEv)
+
+	a := compliance.NewAuditor()
+	a.LoadAll()
+	a.SetCollector(staticCollector{ev})
+	hipaa := a.RunAudit(compliance.FrameworkHIPAA)
+	if got := failing(resultByID(hipaa, "HIPAA-164.312c")); got != "bucket/phi-raw" {
+		t.Errorf("expected phi-raw unversioned, got %q", got)
+	}
+	if got := failing(resultByID(hipaa, "HIPAA-164.312e")); got != "bucket/phi-raw" {
+		t.Errorf("expected only phi-raw unencrypted, got %q", got)
+	}
+	cis := a.RunAudit(compliance.FrameworkCIS)
+	if got := failing(resultByID(cis, "CIS-3.2")); !strings.HasPrefix(got, "sg-1") {
+		t.Errorf("expected open default group from skill output, got %q", got)
+	}
+}
+
+func TestLiveCollectionThroughCLIExecutor(t *testing.T) {
+	// Fake aws and trivy binaries answer the built-in source commands, so the
+	// real skills run through the real executor and safety layer.
+	bin := t.TempDir()
+	write := func(name, content string, mode os.FileMode) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(bin, name), []byte(content), mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	csv := "user,arn,password_enabled,mfa_active\nalice,arn:aws:iam::1:user/alice,true,true\nbob,arn:aws:iam::1:user/bob,true,false\n"
+	write("iam.json", `{"Content": "`+base64.StdEncoding.EncodeToString([]byte(csv))+`", "ReportFormat": "text/csv"}`, 0o600)
+	write("sg.json", `{"SecurityGroups": [{"GroupName": "default", "GroupId": "sg-d", "VpcId": "vpc-a", "IpPermissions": [], "IpPermissionsEgress": []}]}`, 0o600)
+	logs, _ := json.Marshal(map[string]interface{}{
+		"resourceName": "logs",
+		"supplementaryConfiguration": map[string]interface{}{
+			"ServerSideEncryptionConfiguration": map[string]interface{}{"rules": []interface{}{map[string]interface{}{"applyServerSideEncryptionByDefault": map[string]interface{}{"sseAlgorithm": "aws:kms"}}}},
+			"BucketLoggingConfiguration":        `{"destinationBucketName": "audit", "logFilePrefix": "logs/"}`,
+			"BucketVersioningConfiguration":     map[string]interface{}{"status": "Enabled"},
+		},
+	})
+	raw, _ := json.Marshal(map[string]interface{}{
+		"resourceName": "raw",
+		"supplementaryConfiguration": map[string]interface{}{
+			"BucketLoggingConfiguration":    map[string]interface{}{"destinationBucketName": nil},
+			"BucketVersioningConfiguration": map[string]interface{}{"status": "Off"},
+		},
+	})
+	s3, _ := json.Marshal(map[string]interface{}{"Results": []string{string(logs), string(raw)}, "QueryInfo": map[string]interface{}{}})
+	write("s3.json", string(s3), 0o600)
+	write("aws", `#!/bin/sh
+case "$*" in
+*"{"*) echo "uninterpolated placeholder: $*" >&2; exit 2 ;;
+"iam get-credential-report --output json") cat "`+bin+`/iam.json" ;;
+"ec2 describe-security-groups --region us-east-1 --output json") cat "`+bin+`/sg.json" ;;
+"configservice select-resource-config --region us-east-1 --output json --expression SELECT "*"WHERE resourceType = 'AWS::S3::Bucket'") cat "`+bin+`/s3.json" ;;
+*) echo "unexpected: $*" >&2; exit 2 ;;
+esac
+`, 0o755)
+	write("trivy", `#!/bin/sh
+[ "$*" = "image --severity HIGH,CRITICAL --format json web:2.0" ] || { echo "unexpected: $*" >&2; exit 2; }
+echo '{"ArtifactName": "web:2.0", "Results": []}'
+`, 0o755)
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	registry := skills.NewRegistry()
+	if err := registry.LoadBuiltins(); err != nil {
+		t.Fatal(err)
+	}
+	exec := executor.NewCLIExecutor(safety.NewLayer(), false)
+	sources := append(compliance.DefaultSkillSources(), compliance.ScanSource("web:2.0"))
+	ev, err := compliance.NewSkillCollector(registry, exec, "production", sources...).Collect(context.Background())
+	if err != nil {
+		t.Fatalf("live collection in production should need no confirmation: %v", err)
+	}
+	if scans, _ := ev.Objects(compliance.EvidenceScans); len(scans) != 1 {
+		t.Errorf("expected one trivy report, got %d", len(scans))
+	}
+
+	a := compliance.NewAuditor()
+	a.LoadCISBenchmarks()
+	a.SetCollector(staticCollector{ev})
+	report := a.RunAudit(compliance.FrameworkCIS)
+	want := map[string]string{ // check → failing resources
+		"CIS-1.2": "user/bob",
+		"CIS-3.2": "",
+		"CIS-4.1": "bucket/raw",
+		"CIS-4.2": "bucket/raw",
+	}
+	for id, resources := range want {
+		r := resultByID(report, id)
+		if r.Status == compliance.StatusSkip || failing(r) != resources {
+			t.Errorf("%s: expected failing %q, got %q (%s)", id, resources, failing(r), r.Status)
+		}
+	}
+
+	// Mutating skills are never run to collect evidence.
+	_, err = compliance.NewSkillCollector(registry, exec, "production", compliance.SkillSource{Key: compliance.EvidenceBuckets, Skill: "aws.s3.encrypt"}).Collect(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "not a read-only skill") {
+		t.Errorf("expected a mutating source skill to be refused, got %v", err)
+	}
+}
+
+type staticCollector struct{ ev *compliance.Evidence }
+
+func (c staticCollector) Collect(context.Context) (*compliance.Evidence, error) { return c.ev, nil }
+
+type failingCollector struct{ err error }
+
+func (c failingCollector) Collect(context.Context) (*compliance.Evidence, error) { return nil, c.err }
+
+type stubExecutor string
+
+func (s stubExecutor) Execute(_ context.Context, skill *core.Skill, _ map[string]interface{}, _ string) *core.ExecutionResult {
+	return &core.ExecutionResult{SkillName: skill.Name, Status: core.StatusSuccess, Output: map[string]interface{}{"stdout": string(s)}}
+}
+
+func TestAWSExportEvidence(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("credential-report.csv", `user,arn,user_creation_time,password_enabled,password_last_used,mfa_active,access_key_1_active,access_key_1_last_used_date,access_key_2_active,access_key_2_last_used_date
+<root_account>,arn:aws:iam::1:root,2020-01-01T00:00:00+00:00,not_supported,2020-05-01T00:00:00+00:00,true,true,N/A,false,N/A
+alice,arn:aws:iam::1:user/alice,2024-01-01T00:00:00+00:00,true,2099-01-01T00:00:00+00:00,true,false,N/A,false,N/A
+carol,arn:aws:iam::1:user/carol,2024-01-01T00:00:00+00:00,true,no_information,false,false,N/A,false,N/A
+`)
+	write("trails.json", `{"trailList": [{"Name": "main", "IsMultiRegionTrail": true, "LogFileValidationEnabled": false, "KmsKeyId": "arn:aws:kms:k"}]}`)
+	write("vpcs.json", `{"Vpcs": [{"VpcId": "vpc-a"}, {"VpcId": "vpc-b"}]}`)
+	write("flow-logs-us-east-1.json", `{"FlowLogs": [{"FlowLogId": "fl-1", "ResourceId": "vpc-a", "FlowLogStatus": "ACTIVE"}]}`)
+	write("flow-logs-eu-west-1.json", `{"FlowLogs": [{"FlowLogId": "fl-2", "ResourceId": "vpc-b", "FlowLogStatus": "ACTIVE"}]}`)
+	write("security-groups.json", `{"SecurityGroups": [{"GroupName": "default", "GroupId": "sg-d", "VpcId": "vpc-a", "IpPermissions": [], "IpPermissionsEgress": []}]}`)
+	write("buckets.json", `{"Buckets": [{"Name": "assets"}, {"Name": "audit.logs"}]}`)
+	write("s3/audit.logs.encryption.json", `{"ServerSideEncryptionConfiguration": {"Rules": [{"ApplyServerSideEncryptionByDefault": {"SSEAlgorithm": "aws:kms"}}]}}`)
+	write("s3/audit.logs.logging.json", ``)
+	write("s3/assets.logging.json", `{"LoggingEnabled": {"TargetBucket": "audit.logs"}}`)
+
+	a := compliance.NewAuditor()
+	a.LoadCISBenchmarks()
+	a.SetCollector(compliance.NewFileCollector(dir))
+	report := a.RunAudit(compliance.FrameworkCIS)
+	if len(report.CollectionErrors) > 0 {
+		t.Fatalf("unexpected collection errors: %v", report.CollectionErrors)
+	}
+
+	want := map[string]string{ // check → failing resources
+		"CIS-1.1": "",
+		"CIS-1.2": "user/carol",
+		"CIS-1.3": "user/carol", // never used, created over 90 days ago
+		"CIS-2.1": "",
+		"CIS-2.2": "trail/main",
+		"CIS-2.3": "",
+		"CIS-3.1": "", // flow logs from both regions
+		"CIS-3.2": "",
+		"CIS-4.1": "bucket/audit.logs",
+		"CIS-4.2": "bucket/assets",
+	}
+	for id, resources := range want {
+		r := resultByID(report, id)
+		if got := failing(r); got != resources {
+			t.Errorf("%s: expected failing %q, got %q (%s)", id, resources, got, r.Status)
+		}
+		if (resources == "") != (r.Status == compliance.StatusPass) {
+			t.Errorf("%s: unexpected status %s", id, r.Status)
+		}
+	}
+	if !strings.Contains(report.Render(), "✗ user/carol — console access without MFA") {
+		t.Error("rendered report should list failing resources")
+	}
+
+	// The JSON output of aws iam get-credential-report carries the CSV base64 encoded.
+	csv := "user,arn,password_enabled,mfa_active\nbob,arn:aws:iam::1:user/bob,true,false\n"
+	doc := map[string]interface{}{"Content": base64.StdEncoding.EncodeToString([]byte(csv)), "ReportFormat": "text/csv"}
+	key, rows, ok, err := compliance.ParseAWSExport(doc)
+	if err != nil || !ok || key != compliance.EvidenceCredentialReport {
+		t.Fatalf("expected a credential report, got %q %v %v", key, ok, err)
+	}
+	if list := rows.([]interface{}); len(list) != 1 || list[0].(map[string]interface{})["mfa_active"] != "false" {
+		t.Errorf("unexpected rows %v", rows)
+	}
+}
+
+const pciFixture = `{
+  "collected_at": "2026-03-01T00:00:00Z",
+  "ec2.security_groups": [
+    {"GroupId": "sg-web", "GroupName": "web", "IpPermissions": [{"IpProtocol": "tcp", "FromPort": 443, "ToPort": 443, "IpRanges": [{"CidrIp": "0.0.0.0/0"}]}]},
+    {"GroupId": "sg-db", "GroupName": "db", "IpPermissions": [{"IpProtocol": "tcp", "FromPort": 5432, "ToPort": 5432, "Ipv6Ranges": [{"CidrIpv6": "::/0"}]}]}
+  ],
+  "elbv2.listeners": [
+    {"ListenerArn": "arn:listener/https", "Protocol": "HTTPS", "Port": 443, "SslPolicy": "ELBSecurityPolicy-TLS13-1-2-2021-06"},
+    {"ListenerArn": "arn:listener/http", "Protocol": "HTTP", "Port": 80, "DefaultActions": [{"Type": "redirect", "RedirectConfig": {"Protocol": "HTTPS"}}]},
+    {"ListenerArn": "arn:listener/legacy", "Protocol": "HTTPS", "Port": 8443, "SslPolicy": "ELBSecurityPolicy-2016-08"}
+  ],
+  "logs.log_groups": [
+    {"logGroupName": "cloudtrail", "retentionInDays": 400},
+    {"logGroupName": "app", "retentionInDays": 30}
+  ],
+  "iam.access_reviews": [
+    {"scope": "production", "reviewer": "alice", "reviewed_at": "2025-06-01T00:00:00Z"},
+    {"scope": "production", "reviewer": "alice", "reviewed_at": "2026-01-10T00:00:00Z"},
+    {"scope": "cde-admins", "reviewer": "bob", "reviewed_at": "2025-01-01T00:00:00Z"}
+  ]
+}`
+
+func TestPCIDSSAudit(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "pci.json"), []byte(pciFixture), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	trivy := `{"SchemaVersion": 2, "ArtifactName": "payments:1.4", "Results": [{"Target": "payments:1.4 (alpine 3.19)",
+	  "Vulnerabilities": [{"VulnerabilityID": "CVE-1", "Severity": "CRITICAL"}, {"VulnerabilityID": "CVE-2", "Severity": "LOW"}]}]}`
+	if err := os.WriteFile(filepath.Join(dir, "scan-payments.json"), []byte(trivy), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	clean := `{"SchemaVersion": 2, "ArtifactName": "web:2.0", "Results": [{"Target": "web:2.0"}]}`
+	if err := os.WriteFile(filepath.Join(dir, "scan-web.json"), []byte(clean), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	a := compliance.NewAuditor()
+	a.LoadAll()
+	a.SetCollector(compliance.NewFileCollector(dir))
+	report := a.RunAudit(compliance.FrameworkPCIDSS)
+	if report.TotalChecks < 8 {
+		t.Fatalf("expected a PCI-DSS control set, got %d checks", report.TotalChecks)
+	}
+
+	want := map[string]string{ // check → failing resources
+		"PCI-1.3.1":  "sg-db (db)",
+		"PCI-1.2.1":  "",
+		"PCI-4.2.1":  "arn:listener/legacy",
+		"PCI-7.2.4":  "cde-admins",
+		"PCI-10.5.1": "log-group/app",
+		"PCI-11.3.1": "payments:1.4",
+	}
+	for id, resources := range want {
+		if got := failing(resultByID(report, id)); got != resources {
+			t.Errorf("%s: expected failing %q, got %q", id, resources, got)
+		}
+	}
+	if r := resultByID(report, "PCI-8.4.1"); r.Status != compliance.StatusSkip {
+		t.Errorf("expected PCI-8.4.1 skipped without a credential report, got %s", r.Status)
+	}
+}
+
+func TestCrossFrameworkMapping(t *testing.T) {
+	evaluations := 0
+	a := compliance.NewAuditor()
+	a.LoadAll()
+	a.RegisterTechnical(&compliance.TechnicalCheck{
+		ID: "s3-encryption", Title: "S3 default encryption enabled",
+		Evaluate: func(*compliance.Evidence) compliance.CheckResult {
+			evaluations++
+			return compliance.CheckResult{Status: compliance.StatusFail, Details: "1 of 1 resource(s) failing",
+				Resources: []compliance.ResourceResult{{Resource: "bucket/raw", Status: compliance.StatusFail}}}
+		},
+	})
+
+	set := a.AuditAll()
+	if evaluations != 1 {
+		t.Errorf("expected s3-encryption evaluated once across frameworks, got %d", evaluations)
+	}
+	if len(set.Reports) != 4 || set.Reports[0].Framework != compliance.FrameworkCIS || set.Reports[3].Framework != compliance.FrameworkPCIDSS {
+		t.Fatalf("unexpected reports %v", set.Reports)
+	}
+	if set.Evaluated >= set.Controls {
+		t.Errorf("expected fewer technical evaluations (%d) than controls (%d)", set.Evaluated, set.Controls)
+	}
+	for _, id := range []string{"CIS-4.2", "SOC2-CC6.1", "HIPAA-164.312e", "PCI-3.5.1"} {
+		var found bool
+		for _, r := range set.Reports {
+			if res := resultByID(r, id); res.ID != "" {
+				found = true
+				if res.Status != compliance.StatusFail || failing(res) != "bucket/raw" {
+					t.Errorf("%s: expected the shared failure, got %s %q", id, res.Status, failing(res))
+				}
+			}
+		}
+		if !found {
+			t.Errorf("%s not audited", id)
+		}
+	}
+
+	var row *compliance.MatrixRow
+	for _, r := range set.Matrix.Rows {
+		if r.Check == "s3-encryption" {
+			row = r
+		}
+	}
+	if row == nil || row.Status != compliance.StatusFail {
+		t.Fatalf("expected a failing s3-encryption matrix row, got %+v", row)
+	}
+	if got := row.Controls[compliance.FrameworkHIPAA]; len(got) != 1 || got[0] != "HIPAA-164.312e" {
+		t.Errorf("unexpected HIPAA mapping %v", got)
+	}
+	if len(row.Controls) != 4 {
+		t.Errorf("expected s3-encryption mapped in 4 frameworks, got %v", row.Controls)
+	}
+	if !strings.Contains(set.Render(), "CONTROL MATRIX") {
+		t.Error("rendered audit set should include the matrix")
+	}
+}
+
+func TestPartialEvidence(t *testing.T) {
+	encrypted := map[string]interface{}{"Name": "cards", "Encryption": map[string]interface{}{
+		"ServerSideEncryptionConfiguration": map[string]interface{}{"Rules": []interface{}{map[string]interface{}{}}},
+	}}
+	audit := func(buckets ...interface{}) compliance.CheckResult {
+		ev := compliance.NewEvidence()
+		if len(buckets) > 0 {
+			ev.Set(compliance.EvidenceBuckets, buckets, "test")
+		}
+		a := compliance.NewAuditor()
+		a.LoadAll()
+		a.SetCollector(staticCollector{ev})
+		return resultByID(a.RunAudit(compliance.FrameworkPCIDSS), "PCI-3.5.1")
+	}
+
+	// S3 passes, but EBS and RDS were never evaluated.
+	r := audit(encrypted)
+	if r.Status != compliance.StatusWarn {
+		t.Errorf("a control with unevaluated mapped checks should warn, got %s", r.Status)
+	}
+	if !strings.Contains(r.Details, "not evaluated: ebs-encryption, rds-encryption") {
+		t.Errorf("details should name the unevaluated checks: %q", r.Details)
+	}
+	if r := audit(map[string]interface{}{"Name": "raw"}); r.Status != compliance.StatusFail {
+		t.Errorf("a failing mapped check should still fail the control, got %s", r.Status)
+	}
+	if r := audit(); r.Status != compliance.StatusSkip {
+		t.Errorf("a control with no evidence at all should be skipped, got %s", r.Status)
+	}
+}
+
+func TestReportExports(t *testing.T) {
+	a := compliance.NewAuditor()
+	a.LoadAll()
+	a.RegisterTechnical(&compliance.TechnicalCheck{
+		ID: "s3-encryption", Title: "S3 default encryption enabled",
+		Evaluate: func(*compliance.Evidence) compliance.CheckResult {
+			return compliance.CheckResult{Status: compliance.StatusFail, Details: "1 of 2 resource(s) failing",
+				Remediation: "aws s3api put-bucket-encryption --bucket <BUCKET> --sse AES256",
+				Resources: []compliance.ResourceResult{
+					{Resource: "bucket/raw", Status: compliance.StatusFail, Details: "no default encryption"},
+					{Resource: "bucket/logs", Status: compliance.StatusPass},
+				}}
+		},
+	})
+	report := a.RunAudit(compliance.FrameworkCIS)
+
+	if _, err := compliance.ParseFormat("pdf"); err == nil {
+		t.Error("expected an unknown format to be rejected")
+	}
+
+	data, err := report.Export(compliance.FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded compliance.Report
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("invalid JSON export: %v", err)
+	}
+	if decoded.Framework != report.Framework || len(decoded.Results) != report.TotalChecks || failing(resultByID(&decoded, "CIS-4.2")) != "bucket/raw" {
+		t.Errorf("JSON export did not round-trip: %+v", decoded)
+	}
+
+	data, err = report.Export(compliance.FormatJUnit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var suites struct {
+		Failures int `xml:"failures,attr"`
+		Suites   []struct {
+			Cases []struct {
+				Name    string `xml:"name,attr"`
+				Failure *struct {
+					Text string `xml:",chardata"`
+				} `xml:"failure"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	if err := xml.Unmarshal(data, &suites); err != nil {
+		t.Fatalf("invalid JUnit export: %v", err)
+	}
+	if suites.Failures != report.Failed || len(suites.Suites) != 1 || len(suites.Suites[0].Cases) != report.TotalChecks {
+		t.Fatalf("expected %d failures in %d cases, got %+v", report.Failed, report.TotalChecks, suites)
+	}
+	for _, c := range suites.Suites[0].Cases {
+		if strings.HasPrefix(c.Name, "CIS-4.2 ") && (c.Failure == nil || !strings.Contains(c.Failure.Text, "bucket/raw")) {
+			t.Errorf("expected CIS-4.2 to fail naming the bucket, got %+v", c.Failure)
+		}
+	}
+
+	data, err = report.Export(compliance.FormatSARIF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sarif struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Results []struct {
+				RuleID string `json:"ruleId"`
+				Level  string `json:"level"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(data, &sarif); err != nil {
+		t.Fatalf("invalid SARIF export: %v", err)
+	}
+	if sarif.Version != "2.1.0" || len(sarif.Runs) != 1 {
+		t.Fatalf("unexpected SARIF log %+v", sarif)
+	}
+	var found bool
+	for _, r := range sarif.Runs[0].Results {
+		if r.RuleID == "CIS-4.2" {
+			found = true
+			if r.Level != "error" {
+				t.Errorf("expected a HIGH failure reported as error, got %s", r.Level)
+			}
+		}
+	}
+	if !found {
+		t.Error("expected a SARIF result for the failing bucket")
+	}
+
+	data, err = report.Export(compliance.FormatHTML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := string(data)
+	for _, want := range []string{"<!DOCTYPE html>", "bucket/raw", "put-bucket-encryption --bucket &lt;BUCKET&gt;"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("HTML report missing %q", want)
+		}
+	}
+	if strings.Contains(page, "<script") || strings.Contains(page, "<link") {
+		t.Error("HTML report should be self-contained")
+	}
+}
+
+type recordingNotifier struct{ events []*notify.Event }
+
+func (r *recordingNotifier) Name() string { return "recording" }
+
+func (r *recordingNotifier) Send(e *notify.Event) error {
+	r.events = append(r.events, e)
+	return nil
+}
+
+func TestHistoryTrend(t *testing.T) {
+	status := map[string]compliance.CheckStatus{"iam-root-unused": compliance.StatusPass, "s3-encryption": compliance.StatusFail}
+	a := compliance.NewAuditor()
+	a.LoadAll()
+	a.SetEnvironment("production")
+	for id := range status {
+		id := id
+		a.RegisterTechnical(&compliance.TechnicalCheck{ID: id, Evaluate: func(*compliance.Evidence) compliance.CheckResult {
+			return compliance.CheckResult{Status: status[id]}
+		}})
+	}
+
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	h, err := compliance.OpenHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := &recordingNotifier{}
+	d := notify.NewDispatcher()
+	d.AddNotifier(n)
+	h.SetDispatcher(d)
+
+	if _, err := h.Record(a.RunAudit(compliance.FrameworkCIS)); err != nil {
+		t.Fatal(err)
+	}
+	status["iam-root-unused"], status["s3-encryption"] = compliance.StatusFail, compliance.StatusPass
+	trend, err := h.Record(a.RunAudit(compliance.FrameworkCIS))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trend.Points) != 2 || len(trend.NewlyFailing) != 1 || trend.NewlyFailing[0].ID != "CIS-1.1" {
+		t.Fatalf("expected CIS-1.1 newly failing, got %+v", trend)
+	}
+	if len(trend.NewlyFixed) != 1 || trend.NewlyFixed[0].ID != "CIS-4.2" {
+		t.Errorf("expected CIS-4.2 newly fixed, got %+v", trend.NewlyFixed)
+	}
+	if !trend.Regressed() || len(n.events) != 1 || n.events[0].RiskLevel != core.RiskCritical || n.events[0].Environment != "production" {
+		t.Errorf("expected one critical regression alert, got %+v", n.events)
+	}
+
+	// Records are keyed by environment and survive reopening.
+	a.SetEnvironment("staging")
+	if _, err := h.Record(a.RunAudit(compliance.FrameworkCIS)); err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := compliance.OpenHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reopened.Trend(compliance.FrameworkCIS, "production"); len(got.Points) != 2 || len(got.NewlyFailing) != 1 {
+		t.Errorf("expected the production trend after reopening, got %+v", got)
+	}
+	if got := reopened.Trend(compliance.FrameworkCIS, "staging"); len(got.Points) != 1 || got.Regressed() {
+		t.Errorf("expected a single staging audit, got %+v", got)
+	}
+	if !strings.Contains(trend.Render(), "NEWLY FAILING") {
+		t.Error("rendered trend should list newly failing checks")
+	}
+
+	// An audit without evidence is unscored and is not a regression.
+	blind := compliance.NewAuditor()
+	blind.LoadAll()
+	blind.SetEnvironment("production")
+	trend, err = reopened.Record(blind.RunAudit(compliance.FrameworkCIS))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trend.Regressed() || !trend.Points[len(trend.Points)-1].Unscored || !strings.Contains(trend.Render(), "N/A") {
+		t.Errorf("expected an unscored point without regression, got %+v", trend)
+	}
+}
+
+func TestWeightedScoring(t *testing.T) {
+	a := compliance.NewAuditor()
+	register := func(id string, severity compliance.Severity, category string, status compliance.CheckStatus) {
+		a.Register(&compliance.Check{
+			ID: id, Framework: "TEST", Severity: severity, Category: category,
+			CheckFunc: func(*compliance.Evidence) compliance.CheckResult { return compliance.CheckResult{Status: status} },
+		})
+	}
+	register("T-1", compliance.SeverityCritical, "IAM", compliance.StatusFail)
+	register("T-2", compliance.SeverityLow, "IAM", compliance.StatusPass)
+	register("T-3", compliance.SeverityHigh, "Storage", compliance.StatusPass)
+	register("T-4", compliance.SeverityMedium, "Storage", compliance.StatusWarn)
+	register("T-5", compliance.SeverityCritical, "Storage", compliance.StatusSkip)
+
+	// Earned 1 + 5 + 1.5 of 10 + 1 + 5 + 3; the skipped check is excluded.
+	report := a.RunAudit("TEST")
+	if want := 7.5 / 19 * 100; report.Score < want-0.01 || report.Score > want+0.01 {
+		t.Errorf("expected weighted score %.2f, got %.2f", want, report.Score)
+	}
+	if len(report.Categories) != 2 || report.Categories[0].Category != "IAM" || report.Categories[0].Evaluated != 2 || report.Categories[0].Failed != 1 {
+		t.Fatalf("unexpected categories %+v", report.Categories)
+	}
+	if got := report.Categories[1].Score; got < 81.24 || got > 81.26 {
+		t.Errorf("expected Storage subscore 81.25, got %.2f", got)
+	}
+	if len(report.Breaches) != 0 {
+		t.Errorf("expected no breaches without thresholds, got %v", report.Breaches)
+	}
+
+	credit := 0.0
+	scoring, err := compliance.ScoringFromConfig(&config.ComplianceScoringConfig{
+		Weights: map[string]float64{"critical": 20}, WarnCredit: &credit, MinScore: 50, MinCategoryScore: 90, FailOnCritical: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.SetScoring(scoring)
+	report = a.RunAudit("TEST")
+	if want := 6.0 / 29 * 100; report.Score < want-0.01 || report.Score > want+0.01 {
+		t.Errorf("expected configured weighted score %.2f, got %.2f", want, report.Score)
+	}
+	// Score, both categories and the CRITICAL failure breach.
+	if len(report.Breaches) != 4 || !strings.Contains(report.Breaches[3], "T-1") {
+		t.Errorf("unexpected breaches %v", report.Breaches)
+	}
+
+	if _, err := compliance.ScoringFromConfig(&config.ComplianceScoringConfig{Weights: map[string]float64{"urgent": 3}}); err == nil {
+		t.Error("expected an unknown severity weight to be rejected")
+	}
+
+	// Without evidence every check skips: the score is N/A, which breaches
+	// the thresholds but nothing else.
+	skipped := compliance.NewAuditor()
+	skipped.Register(&compliance.Check{
+		ID: "S-1", Framework: "SKIP", Severity: compliance.SeverityHigh,
+		CheckFunc: func(*compliance.Evidence) compliance.CheckResult {
+			return compliance.CheckResult{Status: compliance.StatusSkip}
+		},
+	})
+	report = skipped.RunAudit("SKIP")
+	if !report.Unscored || len(report.Breaches) != 0 {
+		t.Errorf("expected an unscored report without breaches, got unscored=%t breaches=%v", report.Unscored, report.Breaches)
+	}
+	if out := report.Render(); !strings.Contains(out, "N/A — no evaluated checks") {
+		t.Errorf("expected N/A score:\n%s", out)
+	}
+	skipped.SetScoring(scoring)
+	report = skipped.RunAudit("SKIP")
+	if len(report.Breaches) != 1 || !strings.Contains(report.Breaches[0], "no check was evaluated") {
+		t.Errorf("expected an unscored report to breach the thresholds, got %v", report.Breaches)
+	}
+
+	// Evidence that failed to collect breaches the thresholds even when the
+	// rest of the report passes.
+	lenient := compliance.DefaultScoringModel()
+	lenient.Thresholds.MinScore = 10
+	a.SetScoring(lenient)
+	a.SetCollector(failingCollector{errors.New("ec2.vpcs: access denied")})
+	report = a.RunAudit("TEST")
+	if len(report.Breaches) != 1 || !strings.Contains(report.Breaches[0], "evidence collection failed") {
+		t.Errorf("expected a collection failure breach, got %v", report.Breaches)
+	}
+}
+
+const acmeFramework = `framework: acme
+checks:
+  - id: ACME-S3-1
+    title: Production buckets are versioned
+    severity: HIGH
+    category: Storage
+    evidence: s3.buckets
+    where: startswith(item.Name, "prod-")
+    expression: item.Versioning.Status == "Enabled"
+    resource: "bucket/{{ item.Name }}"
+    remediation: aws s3api put-bucket-versioning --status Enabled
+  - id: ACME-IAM-1
+    title: Root account has MFA
+    severity: CRITICAL
+    technical: [iam-root-mfa]
+  - id: ACME-NET-1
+    title: VPC inventory collected
+    expression: len(evidence["ec2.vpcs"]) > 0
+`
+
+func TestCustomFramework(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "acme.yaml"), []byte(acmeFramework), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	a := compliance.NewAuditor()
+	a.LoadAll()
+	checks, err := a.LoadFramework(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checks) != 3 || checks[0].Framework != "ACME" {
+		t.Fatalf("expected 3 ACME checks, got %+v", checks)
+	}
+
+	ev := compliance.NewEvidence()
+	ev.Set(compliance.EvidenceBuckets, []interface{}{
+		map[string]interface{}{"Name": "prod-a", "Versioning": map[string]interface{}{"Status": "Suspended"}},
+		map[string]interface{}{"Name": "prod-b", "Versioning": map[string]interface{}{"Status": "Enabled"}},
+		map[string]interface{}{"Name": "scratch"},
+	}, "test")
+	a.SetCollector(staticCollector{ev})
+	report := a.RunAudit("ACME")
+
+	if r := resultByID(report, "ACME-S3-1"); r.Status != compliance.StatusFail || failing(r) != "bucket/prod-a" || len(r.Resources) != 2 || r.Category != "Storage" {
+		t.Errorf("expected only bucket/prod-a failing of two prod buckets, got %+v", r)
+	}
+	if r := resultByID(report, "ACME-IAM-1"); r.Status != compliance.StatusSkip {
+		t.Errorf("expected the technical check skipped without a credential report, got %s", r.Status)
+	}
+	if r := resultByID(report, "ACME-NET-1"); r.Status != compliance.StatusFail {
+		t.Errorf("expected the evidence expression to fail without VPCs, got %s", r.Status)
+	}
+
+	// Reloading collides; invalid files report every problem and register nothing.
+	if _, err := a.LoadFramework(dir); err == nil || !strings.Contains(err.Error(), "duplicate check 'ACME-S3-1'") {
+		t.Errorf("expected duplicate checks to be rejected, got %v", err)
+	}
+	invalid := `framework: other
+checks:
+  - id: X-1
+    title: Broken
+    severity: URGENT
+    expression: item.Name ==
+    evidence: s3.buckets
+  - id: X-2
+    title: Unknown check
+    technical: [no-such-check]
+    owner: security
+`
+	path := filepath.Join(t.TempDir(), "other.yaml")
+	if err := os.WriteFile(path, []byte(invalid), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, err = a.LoadFramework(path)
+	for _, want := range []string{"unknown severity 'URGENT'", "check 'X-1': expression:", "unknown field 'owner'"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	}
+	if _, err := compliance.ParseFramework("cis.yaml", []byte("framework: CIS\nchecks:\n  - id: CIS-9\n    title: x\n    expression: true\n")); err == nil {
+		t.Error("expected built-in frameworks to be protected")
+	}
+	for _, fw := range a.ListFrameworks() {
+		if fw == "OTHER" {
+			t.Error("invalid framework should not be registered")
+		}
+	}
+}
+
+func TestRemediationPlan(t *testing.T) {
+	a := compliance.NewAuditor()
+	a.LoadAll()
+	ev := compliance.NewEvidence()
+	ev.Set(compliance.EvidenceBuckets, []interface{}{
+		map[string]interface{}{"Name": "raw"},
+		map[string]interface{}{"Name": "logs", "Versioning": map[string]interface{}{"Status": "Enabled"},
+			"Encryption": map[string]interface{}{"ServerSideEncryptionConfiguration": map[string]interface{}{"Rules": []interface{}{map[string]interface{}{}}}}},
+	}, "test")
+	a.SetCollector(staticCollector{ev})
+	set := a.AuditAll()
+	if r := resultByID(set.Reports[0], "CIS-4.2"); len(r.Actions) != 1 || r.Actions[0].Params["bucket_name"] != "raw" {
+		t.Fatalf("expected an encryption fix for bucket/raw, got %+v", r.Actions)
+	}
+
+	registry := skills.NewRegistry()
+	if err := registry.LoadBuiltins(); err != nil {
+		t.Fatal(err)
+	}
+	engine := planner.NewEngine(registry)
+	plan, err := compliance.RemediationPlan(engine, set.Reports...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// bucket/raw fails encryption in four frameworks but is fixed once.
+	if len(plan.Steps) != 2 || plan.Steps[0].SkillName != "aws.s3.encrypt" || plan.Steps[1].SkillName != "aws.s3.versioning" {
+		t.Fatalf("expected encrypt and versioning steps, got %+v", plan.Steps)
+	}
+	if !strings.Contains(plan.Steps[0].Description, "CIS-4.2, SOC2-CC6.1, HIPAA-164.312e, PCI-3.5.1") {
+		t.Errorf("expected the step to name every control, got %q", plan.Steps[0].Description)
+	}
+	if plan.OverallRisk != core.RiskMedium || len(engine.StepsRequiringConfirmation(plan)) != 2 {
+		t.Errorf("expected confirmed MEDIUM steps, got %s", plan.OverallRisk)
+	}
+	if errs := engine.Validate(plan); len(errs) > 0 {
+		t.Errorf("unexpected validation errors %v", errs)
+	}
+	for _, c := range compliance.ManualRemediations(set.Reports...) {
+		if c.ID == "CIS-4.2" {
+			t.Error("CIS-4.2 is fully covered by its fix")
+		}
+		if c.ID == "CIS-4.1" {
+			return
+		}
+	}
+	t.Error("expected bucket logging to need manual remediation")
+}
+
+func TestComplianceWaivers(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	waivers := compliance.NewWaiverRegistry()
+	waivers.SetClock(func() time.Time { return now })
+	err := waivers.LoadConfig([]*config.ComplianceWaiver{
+		{ID: "WVR-1", Check: "CIS-4.2", Resources: []string{"bucket/public-*"}, Approver: "sec-lead", Justification: "public website assets", Expires: "2026-12-31"},
+		{ID: "WVR-2", Check: "CIS-1.2", Framework: "CIS", Environments: []string{"sandbox"}, Approver: "sec-lead", Justification: "break-glass users", Expires: "2026-12-31"},
+		{ID: "WVR-3", Check: "CIS-4.1", Approver: "sec-lead", Justification: "logging rollout", Expires: "2026-05-01"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := waivers.Add(&compliance.Waiver{Check: "CIS-4.2", Approver: "sec-lead"}); err == nil || !strings.Contains(err.Error(), "missing justification, expires") {
+		t.Errorf("expected missing fields to be rejected, got %v", err)
+	}
+
+	ev := compliance.NewEvidence()
+	ev.Set(compliance.EvidenceBuckets, []interface{}{
+		map[string]interface{}{"Name": "public-site"},
+		map[string]interface{}{"Name": "public-docs"},
+	}, "test")
+	ev.Set(compliance.EvidenceCredentialReport, []interface{}{
+		map[string]interface{}{"user": "bob", "password_enabled": "true", "mfa_active": "false"},
+	}, "test")
+	a := compliance.NewAuditor()
+	a.LoadCISBenchmarks()
+	a.SetCollector(staticCollector{ev})
+	a.SetWaivers(waivers)
+	a.SetEnvironment("sandbox")
+	report := a.RunAudit(compliance.FrameworkCIS)
+
+	encryption := resultByID(report, "CIS-4.2")
+	if encryption.Status != compliance.StatusWaived || len(encryption.Actions) != 0 || encryption.Resources[0].Waiver != "WVR-1" {
+		t.Errorf("expected both public buckets waived without fixes, got %+v", encryption)
+	}
+	if r := resultByID(report, "CIS-1.2"); r.Status != compliance.StatusWaived || len(r.Waivers) != 1 || r.Waivers[0].ID != "WVR-2" {
+		t.Errorf("expected the whole check waived in sandbox, got %+v", r)
+	}
+	if r := resultByID(report, "CIS-4.1"); r.Status != compliance.StatusFail || len(r.LapsedWaivers) != 1 {
+		t.Errorf("expected the expired waiver to no longer apply, got %+v", r)
+	}
+	if report.Waived != 2 || report.Failed != 1 {
+		t.Errorf("expected 2 waived and 1 failed, got %d and %d", report.Waived, report.Failed)
+	}
+	if out := report.Render(); !strings.Contains(out, "🔕 WAIVED (2)") || !strings.Contains(out, "⌛ Waiver WVR-3 expired") {
+		t.Errorf("expected a waived section and the lapsed waiver:\n%s", out)
+	}
+	if out := waivers.Render(waivers.List()); !strings.Contains(out, "expires: 2026-12-31") {
+		t.Errorf("expected the configured expiry date:\n%s", out)
+	}
+
+	// Outside the waiver's environment the check fails again.
+	a.SetEnvironment("production")
+	if r := resultByID(a.RunAudit(compliance.FrameworkCIS), "CIS-1.2"); r.Status != compliance.StatusFail {
+		t.Errorf("expected CIS-1.2 to fail in production, got %s", r.Status)
+	}
+	if got := waivers.Expiring(30 * 24 * time.Hour); len(got) != 0 {
+		t.Errorf("expected no waivers expiring within 30 days, got %d", len(got))
+	}
+
+	out, err := report.Export(compliance.FormatSARIF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), `"status": "accepted"`) {
+		t.Error("expected waived resources as accepted SARIF suppressions")
+	}
+	for _, format := range []compliance.Format{compliance.FormatSARIF, compliance.FormatJUnit, compliance.FormatHTML} {
+		out, err := report.Export(format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(out), "until 2026-12-31") || strings.Contains(string(out), "2027-01-01") {
+			t.Errorf("expected the configured expiry date in the %s export", format)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package compliance
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/parth14193/ownbot/pkg/config"
+)
+
+// ScoringFromConfig builds a scoring model from configuration. Weights not
+// configured keep their defaults.
+func ScoringFromConfig(sc *config.ComplianceScoringConfig) (ScoringModel, error) {
+	m := DefaultScoringModel()
+	if sc == nil {
+		return m, nil
+	}
+	for name, w := range sc.Weights {
+		severity := Severity(strings.ToUpper(name))
+		if _, known := m.Weights[severity]; !known {
+			return m, fmt.Errorf("compliance.scoring.weights: unknown severity '%s' (want CRITICAL, HIGH, MEDIUM or LOW)", name)
+		}
+		if w < 0 {
+			return m, fmt.Errorf("compliance.scoring.weights.%s: weight must not be negative", name)
+		}
+		m.Weights[severity] = w
+	}
+	if sc.WarnCredit != nil {
+		if *sc.WarnCredit < 0 || *sc.WarnCredit > 1 {
+			return m, fmt.Errorf("compliance.scoring.warn_credit: must be between 0 and 1")
+		}
+		m.WarnCredit = *sc.WarnCredit
+	}
+	for field, v := range map[string]float64{"min_score": sc.MinScore, "min_category_score": sc.MinCategoryScore} {
+		if v < 0 || v > 100 {
+			return m, fmt.Errorf("compliance.scoring.%s: must be a percentage between 0 and 100", field)
+		}
+	}
+	m.Thresholds = Thresholds{
+		MinScore:         sc.MinScore,
+		MinCategoryScore: sc.MinCategoryScore,
+		FailOnCritical:   sc.FailOnCritical,
+	}
+	return m, nil
+}
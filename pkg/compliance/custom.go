@@ -0,0 +1,342 @@
+package compliance
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/parth14193/ownbot/pkg/expr"
+)
+
+// Custom frameworks are YAML control catalogs:
+//
+//	framework: ACME-BASELINE
+//	checks:
+//	  - id: ACME-S3-1
+//	    title: Production buckets are versioned
+//	    severity: HIGH
+//	    category: Storage
+//	    evidence: s3.buckets
+//	    where: startswith(item.Name, "prod-")
+//	    expression: item.Versioning.Status == "Enabled"
+//	    resource: "bucket/{{ item.Name }}"
+//	    remediation: aws s3api put-bucket-versioning --status Enabled
+//	    fix:
+//	      skill: aws.s3.versioning
+//	      params: {bucket_name: "{{ id }}"}
+//	  - id: ACME-IAM-1
+//	    title: Console users have MFA
+//	    severity: CRITICAL
+//	    technical: [iam-console-mfa]
+//
+// A check with an evidence key evaluates its expression (see package expr)
+// once per collected object, bound to item, and fails for each object where
+// it is false; where filters the objects evaluated. Without an evidence key
+// the expression is evaluated once against all evidence, e.g.
+// len(evidence["ec2.vpcs"]) > 0. A check may instead reference built-in
+// technical checks. The framework defaults to CUSTOM. A fix names the skill
+// that remediates each failing resource (see Fix).
+
+// customFramework is the on-disk form of a custom framework.
+type customFramework struct {
+	Framework string      `yaml:"framework"`
+	Checks    []yaml.Node `yaml:"checks"`
+}
+
+// customCheck is the on-disk form of a custom check.
+type customCheck struct {
+	ID          string   `yaml:"id"`
+	Title       string   `yaml:"title"`
+	Description string   `yaml:"description"`
+	Severity    string   `yaml:"severity"`
+	Category    string   `yaml:"category"`
+	Evidence    string   `yaml:"evidence"`
+	Where       string   `yaml:"where"`
+	Expression  string   `yaml:"expression"`
+	Resource    string   `yaml:"resource"`
+	Message     string   `yaml:"message"`
+	Remediation string   `yaml:"remediation"`
+	Technical   []string `yaml:"technical"`
+	Fix         *Fix     `yaml:"fix"`
+}
+
+var customCheckFields = map[string]bool{
+	"id": true, "title": true, "description": true, "severity": true, "category": true, "evidence": true,
+	"where": true, "expression": true, "resource": true, "message": true, "remediation": true, "technical": true,
+	"fix": true,
+}
+
+// builtinFrameworks cannot be redefined by custom framework files.
+var builtinFrameworks = map[Framework]bool{FrameworkCIS: true, FrameworkSOC2: true, FrameworkHIPAA: true, FrameworkPCIDSS: true}
+
+// LoadFrameworkDir loads every .yaml/.yml custom framework file under dir and
+// registers its checks. Nothing is registered if any file fails validation;
+// the returned error lists every problem with its file and line.
+func (a *Auditor) LoadFrameworkDir(dir string) ([]*Check, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ext := filepath.Ext(path); !d.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read framework directory: %w", err)
+	}
+	sort.Strings(files)
+
+	var loaded []*Check
+	var errs []error
+	for _, path := range files {
+		checks, err := LoadFrameworkFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		loaded = append(loaded, checks...)
+	}
+	return a.registerCustom(loaded, errs)
+}
+
+// LoadFramework loads a custom framework file, or every file under a
+// directory, and registers its checks.
+func (a *Auditor) LoadFramework(path string) ([]*Check, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read custom framework: %w", err)
+	}
+	if info.IsDir() {
+		return a.LoadFrameworkDir(path)
+	}
+	checks, err := LoadFrameworkFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return a.registerCustom(checks, nil)
+}
+
+// registerCustom registers loaded checks, reporting IDs already taken in
+// their framework. Nothing is registered if errs is non-empty or any ID
+// collides.
+func (a *Auditor) registerCustom(loaded []*Check, errs []error) ([]*Check, error) {
+	seen := make(map[string]bool)
+	for fw, checks := range a.checks {
+		for _, c := range checks {
+			seen[string(fw)+"/"+c.ID] = true
+		}
+	}
+	for _, c := range loaded {
+		key := string(c.Framework) + "/" + c.ID
+		if seen[key] {
+			errs = append(errs, fmt.Errorf("duplicate check '%s' in framework %s", c.ID, c.Framework))
+		}
+		seen[key] = true
+		for _, id := range c.Technical {
+			if _, ok := a.technical[id]; !ok {
+				errs = append(errs, fmt.Errorf("check '%s': unknown technical check '%s'", c.ID, id))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	for _, c := range loaded {
+		a.Register(c)
+	}
+	return loaded, nil
+}
+
+// LoadFrameworkFile reads and validates a custom framework file.
+func LoadFrameworkFile(path string) ([]*Check, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read custom framework: %w", err)
+	}
+	return ParseFramework(path, data)
+}
+
+// ParseFramework parses custom framework checks from YAML. name is used in
+// error messages.
+func ParseFramework(name string, data []byte) ([]*Check, error) {
+	var doc customFramework
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	fw := Framework(strings.ToUpper(strings.TrimSpace(doc.Framework)))
+	if fw == "" {
+		fw = FrameworkCustom
+	}
+	if builtinFrameworks[fw] {
+		return nil, fmt.Errorf("%s: framework %s is built in; custom checks need their own framework name", name, fw)
+	}
+	if len(doc.Checks) == 0 {
+		return nil, fmt.Errorf("%s: no checks defined", name)
+	}
+
+	var checks []*Check
+	var errs []error
+	for i := range doc.Checks {
+		c, checkErrs := compileCheck(name, fw, &doc.Checks[i])
+		errs = append(errs, checkErrs...)
+		if c != nil {
+			checks = append(checks, c)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return checks, nil
+}
+
+func compileCheck(file string, fw Framework, node *yaml.Node) (*Check, []error) {
+	errorf := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%s:%d: %s", file, node.Line, fmt.Sprintf(format, args...))
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, []error{errorf("check must be a mapping")}
+	}
+
+	var errs []error
+	for i := 0; i < len(node.Content); i += 2 {
+		if key := node.Content[i]; !customCheckFields[key.Value] {
+			errs = append(errs, fmt.Errorf("%s:%d: unknown field '%s'", file, key.Line, key.Value))
+		}
+	}
+	var spec customCheck
+	if err := node.Decode(&spec); err != nil {
+		return nil, append(errs, fmt.Errorf("%s: %w", file, err))
+	}
+
+	if spec.ID == "" {
+		errs = append(errs, errorf("check id is required"))
+	}
+	if spec.Title == "" {
+		errs = append(errs, errorf("check '%s': title is required", spec.ID))
+	}
+	severity := Severity(strings.ToUpper(spec.Severity))
+	switch severity {
+	case "":
+		severity = SeverityMedium
+	case SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical:
+	default:
+		errs = append(errs, errorf("check '%s': unknown severity '%s' (want CRITICAL, HIGH, MEDIUM or LOW)", spec.ID, spec.Severity))
+	}
+
+	check := &Check{
+		ID:          spec.ID,
+		Framework:   fw,
+		Title:       spec.Title,
+		Description: spec.Description,
+		Severity:    severity,
+		Category:    spec.Category,
+		Technical:   spec.Technical,
+	}
+	switch {
+	case len(spec.Technical) > 0:
+		if spec.Expression != "" || spec.Evidence != "" {
+			errs = append(errs, errorf("check '%s': technical cannot be combined with evidence or expression", spec.ID))
+		}
+	case strings.TrimSpace(spec.Expression) == "":
+		errs = append(errs, errorf("check '%s': expression or technical is required", spec.ID))
+	default:
+		exprs := make(map[string]*expr.Expr)
+		for field, src := range map[string]string{"expression": spec.Expression, "where": spec.Where} {
+			if strings.TrimSpace(src) == "" {
+				continue
+			}
+			e, err := expr.Parse(src)
+			if err != nil {
+				errs = append(errs, errorf("check '%s': %s: %v", spec.ID, field, err))
+				continue
+			}
+			exprs[field] = e
+		}
+		if spec.Evidence == "" && (spec.Where != "" || spec.Resource != "" || spec.Fix != nil) {
+			errs = append(errs, errorf("check '%s': where, resource and fix need an evidence key", spec.ID))
+		}
+		if spec.Fix != nil && spec.Fix.Skill == "" {
+			errs = append(errs, errorf("check '%s': fix.skill is required", spec.ID))
+		}
+		if len(errs) == 0 {
+			check.CheckFunc = expressionCheck(spec, exprs["expression"], exprs["where"])
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return check, nil
+}
+
+// evidenceEnv returns the expression environment holding all evidence.
+func evidenceEnv(ev *Evidence) map[string]interface{} {
+	values := make(map[string]interface{}, len(ev.values))
+	for k, v := range ev.values {
+		values[k] = v
+	}
+	return map[string]interface{}{"evidence": values, "collected_at": ev.CollectedAt}
+}
+
+// expressionCheck evaluates a custom check's expression against evidence.
+// Expressions that fail to evaluate fail the check or resource.
+func expressionCheck(spec customCheck, assertion, where *expr.Expr) CheckFunc {
+	return func(ev *Evidence) CheckResult {
+		vars := evidenceEnv(ev)
+		if spec.Evidence == "" {
+			ok, err := assertion.EvalBool(vars)
+			switch {
+			case err != nil:
+				return CheckResult{Status: StatusFail, Details: fmt.Sprintf("expression evaluation failed: %v", err), Remediation: spec.Remediation}
+			case !ok:
+				return CheckResult{Status: StatusFail, Details: message(spec, vars), Remediation: spec.Remediation}
+			}
+			return CheckResult{Status: StatusPass, Details: "expression holds"}
+		}
+
+		items, ok := ev.Objects(spec.Evidence)
+		if !ok {
+			return noEvidence(spec.Evidence)
+		}
+		var results []ResourceResult
+		for i, item := range items {
+			vars["item"] = item
+			if where != nil {
+				if in, err := where.EvalBool(vars); err == nil && !in {
+					continue
+				}
+			}
+			resource := fmt.Sprintf("%s[%d]", spec.Evidence, i)
+			if spec.Resource != "" {
+				resource = expr.Interpolate(spec.Resource, vars)
+			}
+			holds, err := assertion.EvalBool(vars)
+			switch {
+			case err != nil:
+				results = append(results, fail(resource, fmt.Sprintf("expression evaluation failed: %v", err)))
+			case !holds:
+				results = append(results, fail(resource, message(spec, vars)))
+			default:
+				results = append(results, pass(resource, ""))
+			}
+		}
+		result := evaluate(results, spec.Title, spec.Remediation)
+		result.Actions = spec.Fix.actions(spec.Title, result.Resources)
+		return result
+	}
+}
+
+// message is the failure message of a custom check.
+func message(spec customCheck, vars map[string]interface{}) string {
+	if spec.Message != "" {
+		return expr.Interpolate(spec.Message, vars)
+	}
+	return "expected " + spec.Expression
+}
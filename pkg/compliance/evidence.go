@@ -0,0 +1,354 @@
+package compliance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/parth14193/ownbot/pkg/core"
+	"github.com/parth14193/ownbot/pkg/executor"
+	"github.com/parth14193/ownbot/pkg/skills"
+)
+
+// Well-known evidence keys read by the built-in checks. Values are decoded
+// JSON: lists of objects shaped like the corresponding AWS CLI output.
+const (
+	EvidenceCredentialReport = "iam.credential_report" // credential report rows, CSV column names as keys
+	EvidenceTrails           = "cloudtrail.trails"     // describe-trails trailList
+	EvidenceVPCs             = "ec2.vpcs"              // describe-vpcs Vpcs
+	EvidenceFlowLogs         = "ec2.flow_logs"         // describe-flow-logs FlowLogs
+	EvidenceSecurityGroups   = "ec2.security_groups"   // describe-security-groups SecurityGroups
+	EvidenceBuckets          = "s3.buckets"            // {Name, Encryption, Logging, Versioning} per bucket
+	EvidenceVolumes          = "ec2.volumes"           // describe-volumes Volumes
+	EvidenceDBInstances      = "rds.instances"         // describe-db-instances DBInstances
+	EvidenceDrift            = "iac.drift"             // drift detections: {resource, type}
+	EvidenceListeners        = "elbv2.listeners"       // elbv2 describe-listeners Listeners
+	EvidenceLogGroups        = "logs.log_groups"       // logs describe-log-groups logGroups
+	EvidenceAccessReviews    = "iam.access_reviews"    // access review records: {scope, reviewer, reviewed_at}
+	EvidenceScans            = "trivy.scans"           // trivy --format json reports, one per artifact
+)
+
+// Evidence is the data an audit is evaluated against: collected inventory,
+// configuration snapshots and skill outputs, keyed by evidence key.
+type Evidence struct {
+	CollectedAt time.Time // reference time for age-based checks
+	values      map[string]interface{}
+	sources     map[string]string
+}
+
+// NewEvidence creates an empty evidence set collected now.
+func NewEvidence() *Evidence {
+	return &Evidence{
+		CollectedAt: time.Now().UTC(),
+		values:      make(map[string]interface{}),
+		sources:     make(map[string]string),
+	}
+}
+
+// Set stores a value under key, recording where it came from.
+func (e *Evidence) Set(key string, value interface{}, source string) {
+	e.values[key] = value
+	e.sources[key] = source
+}
+
+// Get returns the value stored under key.
+func (e *Evidence) Get(key string) (interface{}, bool) {
+	v, ok := e.values[key]
+	return v, ok
+}
+
+// Source returns where the value under key came from.
+func (e *Evidence) Source(key string) string {
+	return e.sources[key]
+}
+
+// Keys returns the collected evidence keys, sorted.
+func (e *Evidence) Keys() []string {
+	keys := make([]string, 0, len(e.values))
+	for k := range e.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Append adds value under key. When the key is already set both values are
+// combined into one list (e.g. flow logs exported per region, or one scan
+// report per image).
+func (e *Evidence) Append(key string, value interface{}, source string) {
+	existing, ok := e.values[key]
+	if !ok {
+		e.Set(key, value, source)
+		return
+	}
+	e.values[key] = append(asList(existing), asList(value)...)
+	e.sources[key] += ", " + source
+}
+
+func asList(v interface{}) []interface{} {
+	if list, ok := v.([]interface{}); ok {
+		return list
+	}
+	return []interface{}{v}
+}
+
+// Merge copies every value of other into e, replacing existing keys.
+func (e *Evidence) Merge(other *Evidence) {
+	for k, v := range other.values {
+		e.Set(k, v, other.sources[k])
+	}
+}
+
+// Objects returns the value under key as a list of objects. A single object
+// is returned as a one-element list.
+func (e *Evidence) Objects(key string) ([]map[string]interface{}, bool) {
+	v, ok := e.values[key]
+	if !ok {
+		return nil, false
+	}
+	if m, isMap := v.(map[string]interface{}); isMap {
+		return []map[string]interface{}{m}, true
+	}
+	list, _ := v.([]interface{})
+	out := make([]map[string]interface{}, 0, len(list))
+	for _, item := range list {
+		if m, isMap := item.(map[string]interface{}); isMap {
+			out = append(out, m)
+		}
+	}
+	return out, true
+}
+
+// Collector gathers evidence for an audit.
+type Collector interface {
+	Collect(ctx context.Context) (*Evidence, error)
+}
+
+// ── File collector ─────────────────────────────────────────────
+
+// FileCollector reads evidence files for offline audits. A JSON file is
+// either raw AWS CLI output (see ParseAWSExport), an object mapping evidence
+// keys to values plus an optional "collected_at" timestamp, or — in a
+// directory, when its name is an evidence key such as ec2.vpcs.json — the
+// value for that key. CSV files are IAM credential reports, trivy JSON
+// reports are scan results, and files under an s3/ directory are per-bucket
+// exports (see bucketSet).
+type FileCollector struct {
+	path string
+}
+
+// NewFileCollector creates a collector reading path, a file or directory.
+func NewFileCollector(path string) *FileCollector {
+	return &FileCollector{path: path}
+}
+
+// Collect reads every evidence file.
+func (c *FileCollector) Collect(_ context.Context) (*Evidence, error) {
+	info, err := os.Stat(c.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read evidence: %w", err)
+	}
+	files := []string{c.path}
+	if info.IsDir() {
+		files = nil
+		err := filepath.WalkDir(c.path, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if ext := filepath.Ext(path); !d.IsDir() && (ext == ".json" || ext == ".csv") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read evidence directory: %w", err)
+		}
+		sort.Strings(files)
+	}
+
+	ev := NewEvidence()
+	buckets := newBucketSet()
+	var errs []error
+	for _, file := range files {
+		if err := loadEvidenceFile(ev, buckets, file, info.IsDir()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	buckets.apply(ev)
+	return ev, errors.Join(errs...)
+}
+
+func loadEvidenceFile(ev *Evidence, buckets *bucketSet, file string, inDir bool) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read evidence file: %w", err)
+	}
+	if filepath.Ext(file) == ".csv" {
+		rows, err := ParseCredentialReport(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		ev.Append(EvidenceCredentialReport, rows, file)
+		return nil
+	}
+	if inDir && filepath.Base(filepath.Dir(file)) == "s3" {
+		return buckets.addFile(file, data)
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	if list, ok := object(doc)["Buckets"].([]interface{}); ok {
+		buckets.addList(list)
+		return nil
+	}
+	if isTrivyReport(doc) {
+		ev.Append(EvidenceScans, doc, file)
+		return nil
+	}
+	key, value, ok, err := ParseAWSExport(doc)
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	if ok {
+		ev.Append(key, value, file)
+		return nil
+	}
+
+	key = strings.TrimSuffix(filepath.Base(file), ".json")
+	if inDir && strings.Contains(key, ".") {
+		ev.Set(key, doc, file)
+		return nil
+	}
+	obj, ok := doc.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s: evidence file must be an object of evidence keys", file)
+	}
+	for k, v := range obj {
+		if k == "collected_at" {
+			s, _ := v.(string)
+			at, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				return fmt.Errorf("%s: invalid collected_at '%v'", file, v)
+			}
+			ev.CollectedAt = at.UTC()
+			continue
+		}
+		ev.Set(k, v, file)
+	}
+	return nil
+}
+
+// ── Skill collector ────────────────────────────────────────────
+
+// SkillSource maps the JSON output of a read-only skill to an evidence key.
+// Output that is a recognised AWS export (see ParseAWSExport) is converted
+// unless Field selects part of it.
+type SkillSource struct {
+	Key    string
+	Skill  string
+	Params map[string]interface{}
+	Field  string // top-level field of the output to keep, e.g. "SecurityGroups"; empty keeps all
+}
+
+// ScanSource returns a source scanning image with trivy.scan for the
+// vulnerability scanning controls.
+func ScanSource(image string) SkillSource {
+	return SkillSource{Key: EvidenceScans, Skill: "trivy.scan", Params: map[string]interface{}{"image": image, "format": "json"}}
+}
+
+// DefaultSkillSources returns the skills that feed the built-in checks.
+func DefaultSkillSources() []SkillSource {
+	return []SkillSource{
+		{Key: EvidenceSecurityGroups, Skill: "aws.sg.audit"},
+		{Key: EvidenceBuckets, Skill: "aws.s3.audit"},
+		{Key: EvidenceCredentialReport, Skill: "aws.iam.audit"},
+	}
+}
+
+// SkillCollector gathers evidence by executing read-only skills and decoding
+// their JSON output. Read-only skills are not gated by environment tiers, so
+// collection needs no confirmation even in production.
+type SkillCollector struct {
+	registry *skills.Registry
+	exec     executor.Executor
+	env      string
+	sources  []SkillSource
+}
+
+// NewSkillCollector creates a collector that runs skills from registry with
+// exec in env.
+func NewSkillCollector(registry *skills.Registry, exec executor.Executor, env string, sources ...SkillSource) *SkillCollector {
+	return &SkillCollector{registry: registry, exec: exec, env: env, sources: sources}
+}
+
+// Collect runs every source skill. Sources that fail are reported in the
+// error and left out, so their checks are skipped.
+func (c *SkillCollector) Collect(ctx context.Context) (*Evidence, error) {
+	ev := NewEvidence()
+	var errs []error
+	for _, src := range c.sources {
+		v, err := c.run(ctx, src)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", src.Key, err))
+			continue
+		}
+		ev.Append(src.Key, v, "skill:"+src.Skill)
+	}
+	return ev, errors.Join(errs...)
+}
+
+func (c *SkillCollector) run(ctx context.Context, src SkillSource) (interface{}, error) {
+	skill, err := c.registry.Get(src.Skill)
+	if err != nil {
+		return nil, err
+	}
+	if !skill.ReadOnly {
+		return nil, fmt.Errorf("%s is not a read-only skill; evidence is only collected with read-only skills", src.Skill)
+	}
+	params := make(map[string]interface{}, len(skill.Inputs))
+	for _, in := range skill.Inputs {
+		if in.Default != "" {
+			params[in.Name] = in.Default
+		}
+	}
+	for k, v := range src.Params {
+		params[k] = v
+	}
+	result := c.exec.Execute(ctx, skill, params, c.env)
+	if result.Status != core.StatusSuccess {
+		return nil, fmt.Errorf("%s did not succeed (%s): %s", src.Skill, result.Status, result.Message)
+	}
+	stdout, _ := result.Output["stdout"].(string)
+	var v interface{}
+	if err := json.Unmarshal([]byte(stdout), &v); err != nil {
+		return nil, fmt.Errorf("%s output is not JSON: %w", src.Skill, err)
+	}
+	if src.Field != "" {
+		obj, _ := v.(map[string]interface{})
+		field, ok := obj[src.Field]
+		if !ok {
+			return nil, fmt.Errorf("%s output has no field %s", src.Skill, src.Field)
+		}
+		return field, nil
+	}
+	key, value, ok, err := ParseAWSExport(v)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", src.Skill, err)
+	}
+	if ok {
+		if key != src.Key {
+			return nil, fmt.Errorf("%s output provides %s, not %s", src.Skill, key, src.Key)
+		}
+		return value, nil
+	}
+	return v, nil
+}
@@ -0,0 +1,428 @@
+package compliance
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/parth14193/ownbot/pkg/policy"
+)
+
+// Format is a report export format.
+type Format string
+
+const (
+	FormatText  Format = "text"
+	FormatJSON  Format = "json"
+	FormatJUnit Format = "junit"
+	FormatSARIF Format = "sarif"
+	FormatHTML  Format = "html"
+)
+
+// ParseFormat parses a --format value; empty means text.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case "":
+		return FormatText, nil
+	case FormatText, FormatJSON, FormatJUnit, FormatSARIF, FormatHTML:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown report format '%s' (text, json, junit, sarif, html)", s)
+}
+
+// Export encodes the report in format.
+func (r *Report) Export(format Format) ([]byte, error) {
+	switch format {
+	case FormatText, "":
+		return []byte(r.Render()), nil
+	case FormatJSON:
+		return marshalJSON(r)
+	}
+	return export(format, []*Report{r}, nil)
+}
+
+// Export encodes every report of the set, and the control matrix, in format.
+func (s *AuditSet) Export(format Format) ([]byte, error) {
+	switch format {
+	case FormatText, "":
+		return []byte(s.Render()), nil
+	case FormatJSON:
+		return marshalJSON(s)
+	}
+	return export(format, s.Reports, s.Matrix)
+}
+
+func export(format Format, reports []*Report, matrix *Matrix) ([]byte, error) {
+	switch format {
+	case FormatJUnit:
+		return exportJUnit(reports)
+	case FormatSARIF:
+		return exportSARIF(reports)
+	case FormatHTML:
+		return exportHTML(reports, matrix)
+	}
+	return nil, fmt.Errorf("unknown report format '%s'", format)
+}
+
+func marshalJSON(v interface{}) ([]byte, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal report: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// failureText describes a failed or warned check: its details, failing
+// resources and remediation.
+func failureText(c CheckResult) string {
+	var b strings.Builder
+	b.WriteString(c.Details)
+	for _, res := range filterResources(c.Resources, StatusFail) {
+		b.WriteString(fmt.Sprintf("\n%s: %s", res.Resource, res.Details))
+	}
+	if c.Remediation != "" {
+		b.WriteString("\nRemediation: " + c.Remediation)
+	}
+	return b.String()
+}
+
+// waiverText describes the waivers a check's failures were accepted under.
+func waiverText(c CheckResult) string {
+	var lines []string
+	for _, w := range c.Waivers {
+		lines = append(lines, fmt.Sprintf("%s approved by %s until %s: %s", w.ID, w.Approver, policy.FormatExpiry(w.Expires), w.Justification))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ── JUnit XML ──────────────────────────────────────────────────
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Skipped   int         `xml:"skipped,attr"`
+	Timestamp string      `xml:"timestamp,attr"`
+	Cases     []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// exportJUnit writes one test suite per framework and one test case per
+// check. FAIL is a failure, SKIP and WAIVED are skipped and WARN passes with
+// the warning on system-out.
+func exportJUnit(reports []*Report) ([]byte, error) {
+	suites := junitSuites{Name: "infracore compliance"}
+	for _, r := range reports {
+		suite := junitSuite{
+			Name:      string(r.Framework),
+			Tests:     r.TotalChecks,
+			Failures:  r.Failed,
+			Skipped:   r.Skipped + r.Waived,
+			Timestamp: r.Timestamp.UTC().Format(time.RFC3339),
+		}
+		for _, c := range r.Results {
+			tc := junitCase{ClassName: string(r.Framework), Name: c.ID + " " + c.Title}
+			if c.Category != "" {
+				tc.ClassName += "." + c.Category
+			}
+			switch c.Status {
+			case StatusFail:
+				tc.Failure = &junitMessage{Message: c.Details, Type: string(c.Severity), Text: failureText(c)}
+			case StatusSkip:
+				tc.Skipped = &junitMessage{Message: c.Details}
+			case StatusWaived:
+				tc.Skipped = &junitMessage{Message: "waived", Text: waiverText(c)}
+			case StatusWarn:
+				tc.SystemOut = "WARN: " + failureText(c)
+			}
+			suite.Cases = append(suite.Cases, tc)
+		}
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Skipped += suite.Skipped
+		suites.Suites = append(suites.Suites, suite)
+	}
+	data, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JUnit report: %w", err)
+	}
+	return append(append([]byte(xml.Header), data...), '\n'), nil
+}
+
+// ── SARIF ──────────────────────────────────────────────────────
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifText struct {
+	Text string `json:"text"`
+}
+
+type sarifRule struct {
+	ID               string                 `json:"id"`
+	Name             string                 `json:"name"`
+	ShortDescription sarifText              `json:"shortDescription"`
+	Help             *sarifText             `json:"help,omitempty"`
+	Properties       map[string]interface{} `json:"properties"`
+}
+
+type sarifResult struct {
+	RuleID              string             `json:"ruleId"`
+	Level               string             `json:"level"`
+	Message             sarifText          `json:"message"`
+	Locations           []sarifLocation    `json:"locations"`
+	PartialFingerprints map[string]string  `json:"partialFingerprints"`
+	Suppressions        []sarifSuppression `json:"suppressions,omitempty"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Status        string `json:"status"`
+	Justification string `json:"justification"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysical  `json:"physicalLocation"`
+	LogicalLocations []sarifLogical `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysical struct {
+	ArtifactLocation struct {
+		URI string `json:"uri"`
+	} `json:"artifactLocation"`
+}
+
+type sarifLogical struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+}
+
+// securitySeverity is the CVSS-style score code-scanning UIs rank by.
+var securitySeverity = map[Severity]string{
+	SeverityCritical: "9.5", SeverityHigh: "8.0", SeverityMedium: "5.5", SeverityLow: "3.0",
+}
+
+func sarifLevel(c CheckResult) string {
+	if c.Status == StatusWarn {
+		return "warning"
+	}
+	switch c.Severity {
+	case SeverityCritical, SeverityHigh:
+		return "error"
+	case SeverityMedium:
+		return "warning"
+	}
+	return "note"
+}
+
+// exportSARIF writes a SARIF 2.1.0 log with a rule per check and a result
+// per failing resource of every FAIL or WARN check; waived resources are
+// reported as accepted external suppressions. Cloud resources have no
+// source file, so results point at a per-framework pseudo-path and name the
+// resource as a logical location.
+func exportSARIF(reports []*Report) ([]byte, error) {
+	run := sarifRun{Tool: sarifTool{Driver: sarifDriver{Name: "infracore-compliance", Rules: []sarifRule{}}}, Results: []sarifResult{}}
+	for _, r := range reports {
+		for _, c := range r.Results {
+			rule := sarifRule{
+				ID:               c.ID,
+				Name:             c.Title,
+				ShortDescription: sarifText{Text: c.Title},
+				Properties: map[string]interface{}{
+					"tags":              []string{"compliance", string(r.Framework), c.Category},
+					"security-severity": securitySeverity[c.Severity],
+				},
+			}
+			if c.Remediation != "" {
+				rule.Help = &sarifText{Text: c.Remediation}
+			}
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+
+			if c.Status != StatusFail && c.Status != StatusWarn && c.Status != StatusWaived {
+				continue
+			}
+			resources := filterResources(c.Resources, StatusFail)
+			resources = append(resources, filterResources(c.Resources, StatusWaived)...)
+			if len(resources) == 0 {
+				resources = []ResourceResult{{Resource: string(r.Framework), Status: c.Status, Details: c.Details}}
+			}
+			for _, res := range resources {
+				loc := sarifLocation{LogicalLocations: []sarifLogical{{Name: res.Resource, Kind: "resource"}}}
+				loc.PhysicalLocation.ArtifactLocation.URI = "compliance/" + string(r.Framework)
+				sum := sha256.Sum256([]byte(c.ID + "|" + res.Resource))
+				result := sarifResult{
+					RuleID:              c.ID,
+					Level:               sarifLevel(c),
+					Message:             sarifText{Text: fmt.Sprintf("%s: %s — %s", res.Resource, res.Details, c.Title)},
+					Locations:           []sarifLocation{loc},
+					PartialFingerprints: map[string]string{"infracoreResource/v1": hex.EncodeToString(sum[:8])},
+				}
+				if res.Status == StatusWaived {
+					for _, w := range c.Waivers {
+						if res.Waiver == "" || res.Waiver == w.ID {
+							result.Suppressions = append(result.Suppressions, sarifSuppression{Kind: "external", Status: "accepted",
+								Justification: fmt.Sprintf("%s (%s, until %s): %s", w.ID, w.Approver, policy.FormatExpiry(w.Expires), w.Justification)})
+						}
+					}
+				}
+				run.Results = append(run.Results, result)
+			}
+		}
+	}
+	return marshalJSON(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
+
+// ── HTML ───────────────────────────────────────────────────────
+
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"failing": func(rs []ResourceResult) []ResourceResult { return filterResources(rs, StatusFail) },
+	"byResource": func(rs []ResourceResult, s string) []ResourceResult {
+		return filterResources(rs, CheckStatus(s))
+	},
+	"waived": waivedResults,
+	"date":   func(t time.Time) string { return policy.FormatExpiry(t.UTC()) },
+	"byStatus": func(rs []CheckResult, s string) []CheckResult {
+		return filterByStatus(rs, CheckStatus(s))
+	},
+	"controls": func(row *MatrixRow, fw Framework) string { return strings.Join(row.Controls[fw], ", ") },
+	"lower":    func(v interface{}) string { return strings.ToLower(fmt.Sprint(v)) },
+	"time":     func(t time.Time) string { return t.UTC().Format(time.RFC3339) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>InfraCore compliance report</title>
+<style>
+body{font-family:-apple-system,Segoe UI,Helvetica,Arial,sans-serif;margin:2rem auto;max-width:1100px;color:#1f2328;padding:0 1rem}
+h1{font-size:1.6rem}h2{border-bottom:1px solid #d0d7de;padding-bottom:.3rem;margin-top:2.5rem}
+.summary span{display:inline-block;margin-right:1rem;padding:.2rem .6rem;border-radius:4px;background:#f6f8fa}
+.score{font-size:1.3rem;font-weight:600}
+.check{border:1px solid #d0d7de;border-left-width:6px;border-radius:6px;padding:.6rem 1rem;margin:.8rem 0}
+.fail{border-left-color:#cf222e}.waived{border-left-color:#8250df}.warn{border-left-color:#bf8700}.skip{border-left-color:#8c959f}.pass{border-left-color:#1a7f37}
+.sev{font-size:.75rem;font-weight:600;padding:.1rem .4rem;border-radius:3px;background:#eaeef2;margin-right:.4rem}
+.sev.critical{background:#cf222e;color:#fff}.sev.high{background:#fd8c73}.sev.medium{background:#fae17d}
+.fix{background:#dafbe1;border-radius:4px;padding:.4rem .6rem;margin-top:.5rem}
+ul.resources{margin:.4rem 0 0 1rem;padding:0;font-family:ui-monospace,Menlo,monospace;font-size:.85rem}
+table{border-collapse:collapse;width:100%;font-size:.85rem}th,td{border:1px solid #d0d7de;padding:.3rem .5rem;text-align:left}
+th{background:#f6f8fa}.muted{color:#656d76}
+.breach{color:#cf222e;font-weight:600}table.categories{width:auto;margin-bottom:1rem}
+</style>
+</head>
+<body>
+<h1>InfraCore compliance report</h1>
+{{range .Reports}}
+<h2>{{.Framework}}</h2>
+<p class="muted">{{time .Timestamp}}{{if .Evidence}} · evidence: {{range $i, $e := .Evidence}}{{if $i}}, {{end}}{{$e}}{{end}}{{end}}</p>
+<p class="score">{{if .Unscored}}Score N/A — no evaluated checks ({{.Skipped}} skipped){{else}}Score {{printf "%.1f" .Score}}% weighted ({{.Passed}}/{{.TotalChecks}} passed){{end}}</p>
+<p class="summary"><span>✅ Passed {{.Passed}}</span><span>❌ Failed {{.Failed}}</span><span>⚠️ Warnings {{.Warnings}}</span><span>⏭️ Skipped {{.Skipped}}</span>{{if .Waived}}<span>🔕 Waived {{.Waived}}</span>{{end}}</p>
+{{range .Breaches}}<p class="breach">🚫 Threshold: {{.}}</p>{{end}}
+{{with .Categories}}<table class="categories"><tr><th>Category</th><th>Score</th><th>Evaluated</th><th>Failed</th></tr>
+{{range .}}<tr><td>{{.Category}}</td><td>{{printf "%.1f" .Score}}%</td><td>{{.Evaluated}}</td><td>{{.Failed}}</td></tr>{{end}}
+</table>{{end}}
+{{range .CollectionErrors}}<p class="muted">Collection: {{.}}</p>{{end}}
+{{range byStatus .Results "FAIL"}}
+<div class="check fail">
+<strong><span class="sev {{lower .Severity}}">{{.Severity}}</span>{{.ID}} — {{.Title}}</strong>
+<div>{{.Details}}</div>
+{{with failing .Resources}}<ul class="resources">{{range .}}<li>{{.Resource}} — {{.Details}}</li>{{end}}</ul>{{end}}
+{{if .Remediation}}<div class="fix"><strong>Remediation:</strong> {{.Remediation}}</div>{{end}}
+{{range .LapsedWaivers}}<div class="muted">⌛ Waiver {{.}} expired</div>{{end}}
+</div>
+{{end}}
+{{with waived .Results}}<h3>Waived ({{len .}})</h3>
+{{range .}}<div class="check waived">
+<strong><span class="sev {{lower .Severity}}">{{.Severity}}</span>{{.ID}} — {{.Title}}</strong>{{if ne .Status "WAIVED"}} <span class="muted">(partially)</span>{{end}}
+{{with byResource .Resources "WAIVED"}}<ul class="resources">{{range .}}<li>{{.Resource}} — {{.Details}} ({{.Waiver}})</li>{{end}}</ul>{{end}}
+{{range .Waivers}}<div>{{.ID}} approved by {{.Approver}} until {{date .Expires}}{{if .Ticket}} ({{.Ticket}}){{end}}: {{.Justification}}</div>{{end}}
+</div>{{end}}
+{{end}}
+{{range byStatus .Results "WARN"}}
+<div class="check warn"><strong><span class="sev {{lower .Severity}}">{{.Severity}}</span>{{.ID}} — {{.Title}}</strong><div>{{.Details}}</div>
+{{if .Remediation}}<div class="fix"><strong>Remediation:</strong> {{.Remediation}}</div>{{end}}</div>
+{{end}}
+{{with byStatus .Results "SKIP"}}<details><summary>Skipped ({{len .}})</summary>
+{{range .}}<div class="check skip">{{.ID}} — {{.Title}} <span class="muted">({{.Details}})</span></div>{{end}}
+</details>{{end}}
+{{with byStatus .Results "PASS"}}<details><summary>Passed ({{len .}})</summary>
+{{range .}}<div class="check pass">{{.ID}} — {{.Title}} <span class="muted">({{.Details}})</span></div>{{end}}
+</details>{{end}}
+{{if .BreakGlassIntegrity}}
+<h3>Break-glass activity since {{time .PeriodStart}} ({{len .BreakGlassEntries}} entries, ledger {{.BreakGlassIntegrity}})</h3>
+<table><tr><th>Time</th><th>Event</th><th>User</th><th>Incident</th><th>Justification</th></tr>
+{{range .BreakGlassEntries}}<tr><td>{{time .Timestamp}}</td><td>{{.Event}}</td><td>{{.User}}</td><td>{{.IncidentID}}</td><td>{{.Justification}}</td></tr>{{end}}
+</table>
+{{end}}
+{{end}}
+{{with .Matrix}}
+<h2>Control matrix</h2>
+<table><tr><th>Technical check</th><th>Status</th>{{range .Frameworks}}<th>{{.}}</th>{{end}}</tr>
+{{range $row := .Rows}}<tr><td>{{$row.Check}}<br><span class="muted">{{$row.Title}}</span></td><td>{{$row.Status}}</td>{{range $.Matrix.Frameworks}}<td>{{controls $row .}}</td>{{end}}</tr>
+{{end}}</table>
+{{end}}
+</body>
+</html>
+`))
+
+// exportHTML writes a self-contained HTML page (inline CSS, no scripts or
+// external assets) with remediation guidance for every failed check.
+func exportHTML(reports []*Report, matrix *Matrix) ([]byte, error) {
+	var buf bytes.Buffer
+	data := struct {
+		Reports []*Report
+		Matrix  *Matrix
+	}{reports, matrix}
+	if err := htmlReport.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	return buf.Bytes(), nil
+}
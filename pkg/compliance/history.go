@@ -0,0 +1,302 @@
+package compliance
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/parth14193/ownbot/pkg/core"
+	"github.com/parth14193/ownbot/pkg/notify"
+)
+
+// CheckSummary is the outcome of one check as kept in the audit history.
+type CheckSummary struct {
+	ID       string      `json:"id"`
+	Title    string      `json:"title"`
+	Severity Severity    `json:"severity"`
+	Status   CheckStatus `json:"status"`
+}
+
+// HistoryRecord summarises one audit report.
+type HistoryRecord struct {
+	Timestamp   time.Time      `json:"timestamp"`
+	Framework   Framework      `json:"framework"`
+	Environment string         `json:"environment,omitempty"`
+	Score       float64        `json:"score"`
+	Unscored    bool           `json:"unscored,omitempty"`
+	TotalChecks int            `json:"total_checks"`
+	Passed      int            `json:"passed"`
+	Failed      int            `json:"failed"`
+	Warnings    int            `json:"warnings"`
+	Skipped     int            `json:"skipped"`
+	Checks      []CheckSummary `json:"checks"`
+}
+
+func newHistoryRecord(r *Report) HistoryRecord {
+	rec := HistoryRecord{
+		Timestamp:   r.Timestamp.UTC(),
+		Framework:   r.Framework,
+		Environment: r.Environment,
+		Score:       r.Score,
+		Unscored:    r.Unscored,
+		TotalChecks: r.TotalChecks,
+		Passed:      r.Passed,
+		Failed:      r.Failed,
+		Warnings:    r.Warnings,
+		Skipped:     r.Skipped,
+	}
+	for _, c := range r.Results {
+		rec.Checks = append(rec.Checks, CheckSummary{ID: c.ID, Title: c.Title, Severity: c.Severity, Status: c.Status})
+	}
+	return rec
+}
+
+// History is an append-only store of audit reports keyed by framework and
+// environment. When a path is set, records are persisted as JSON lines.
+type History struct {
+	mu         sync.RWMutex
+	path       string
+	records    []HistoryRecord
+	dispatcher *notify.Dispatcher
+}
+
+// NewHistory creates an in-memory audit history.
+func NewHistory() *History {
+	return &History{}
+}
+
+// OpenHistory loads a persisted audit history from path, creating it on
+// first record.
+func OpenHistory(path string) (*History, error) {
+	h := &History{path: path}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open compliance history: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var rec HistoryRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		h.records = append(h.records, rec)
+	}
+	return h, scanner.Err()
+}
+
+// SetDispatcher sets the dispatcher regressions are alerted through.
+func (h *History) SetDispatcher(d *notify.Dispatcher) { h.dispatcher = d }
+
+// Record appends a report and returns its trend against earlier audits of
+// the same framework and environment. A regression is alerted through the
+// dispatcher.
+func (h *History) Record(r *Report) (*Trend, error) {
+	rec := newHistoryRecord(r)
+
+	h.mu.Lock()
+	if h.path != "" {
+		if err := h.persist(rec); err != nil {
+			h.mu.Unlock()
+			return nil, err
+		}
+	}
+	h.records = append(h.records, rec)
+	h.mu.Unlock()
+
+	trend := h.Trend(r.Framework, r.Environment)
+	if trend.Regressed() {
+		h.alert(trend)
+	}
+	return trend, nil
+}
+
+func (h *History) persist(rec HistoryRecord) error {
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create compliance history directory: %w", err)
+	}
+	f, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open compliance history: %w", err)
+	}
+	defer f.Close()
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to marshal history record: %w", err)
+	}
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// Records returns the audits of framework in env, oldest first.
+func (h *History) Records(framework Framework, env string) []HistoryRecord {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	var out []HistoryRecord
+	for _, rec := range h.records {
+		if rec.Framework == framework && rec.Environment == env {
+			out = append(out, rec)
+		}
+	}
+	return out
+}
+
+// TrendPoint is the score of one audit.
+type TrendPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Score     float64   `json:"score"`
+	Unscored  bool      `json:"unscored,omitempty"`
+	Failed    int       `json:"failed"`
+}
+
+// Trend is the score history of a framework in one environment and the
+// checks that changed between the two most recent audits.
+type Trend struct {
+	Framework    Framework      `json:"framework"`
+	Environment  string         `json:"environment,omitempty"`
+	Points       []TrendPoint   `json:"points"`
+	ScoreDelta   float64        `json:"score_delta"`
+	NewlyFailing []CheckSummary `json:"newly_failing,omitempty"`
+	NewlyFixed   []CheckSummary `json:"newly_fixed,omitempty"`
+}
+
+// Trend computes the trend of framework in env.
+func (h *History) Trend(framework Framework, env string) *Trend {
+	records := h.Records(framework, env)
+	t := &Trend{Framework: framework, Environment: env}
+	for _, rec := range records {
+		t.Points = append(t.Points, TrendPoint{Timestamp: rec.Timestamp, Score: rec.Score, Unscored: rec.Unscored, Failed: rec.Failed})
+	}
+	if len(records) < 2 {
+		return t
+	}
+	// An unscored audit has no score to compare.
+	prev, cur := records[len(records)-2], records[len(records)-1]
+	if !prev.Unscored && !cur.Unscored {
+		t.ScoreDelta = cur.Score - prev.Score
+	}
+
+	before := make(map[string]CheckStatus, len(prev.Checks))
+	for _, c := range prev.Checks {
+		before[c.ID] = c.Status
+	}
+	for _, c := range cur.Checks {
+		was := before[c.ID]
+		switch {
+		case c.Status == StatusFail && was != StatusFail:
+			t.NewlyFailing = append(t.NewlyFailing, c)
+		// A check skipped for lack of evidence has not been fixed.
+		case was == StatusFail && (c.Status == StatusPass || c.Status == StatusWarn):
+			t.NewlyFixed = append(t.NewlyFixed, c)
+		}
+	}
+	return t
+}
+
+// Regressed reports whether the latest audit fails checks the previous one
+// did not, or scored lower.
+func (t *Trend) Regressed() bool {
+	return len(t.NewlyFailing) > 0 || t.ScoreDelta < 0
+}
+
+// alert sends a regression to the dispatcher, at the risk level of the most
+// severe newly failing check.
+func (h *History) alert(t *Trend) {
+	if h.dispatcher == nil {
+		return
+	}
+	risk := core.RiskMedium
+	var ids []string
+	for _, c := range t.NewlyFailing {
+		ids = append(ids, c.ID)
+		switch {
+		case c.Severity == SeverityCritical:
+			risk = core.RiskCritical
+		case c.Severity == SeverityHigh && risk < core.RiskHigh:
+			risk = core.RiskHigh
+		}
+	}
+	last := t.Points[len(t.Points)-1]
+	message := fmt.Sprintf("📉 %s compliance regressed to %.1f%% (%+.1f)", t.Framework, last.Score, t.ScoreDelta)
+	if len(ids) > 0 {
+		message += ": newly failing " + strings.Join(ids, ", ")
+	}
+	h.dispatcher.Dispatch(&notify.Event{
+		SkillName:   "compliance." + strings.ToLower(string(t.Framework)),
+		Status:      core.StatusFailed,
+		Environment: t.Environment,
+		RiskLevel:   risk,
+		Message:     message,
+		Timestamp:   last.Timestamp,
+		Details: map[string]interface{}{
+			"framework":     t.Framework,
+			"score":         last.Score,
+			"score_delta":   t.ScoreDelta,
+			"newly_failing": ids,
+		},
+	})
+}
+
+// Render formats the trend for display.
+func (t *Trend) Render() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("📈 COMPLIANCE TREND: %s", t.Framework))
+	if t.Environment != "" {
+		b.WriteString(" (" + t.Environment + ")")
+	}
+	b.WriteString("\n─────────────────────────────────────────\n")
+	if len(t.Points) == 0 {
+		b.WriteString("No audits recorded yet — run: infracore compliance audit " + string(t.Framework) + "\n")
+		return b.String()
+	}
+
+	for i, p := range t.Points {
+		if p.Unscored {
+			b.WriteString(fmt.Sprintf("  %s     N/A  %-20s no evaluated checks\n", p.Timestamp.Format("2006-01-02 15:04"), ""))
+			continue
+		}
+		delta := ""
+		if i > 0 && !t.Points[i-1].Unscored {
+			delta = fmt.Sprintf(" (%+.1f)", p.Score-t.Points[i-1].Score)
+		}
+		bar := strings.Repeat("█", int(p.Score/5))
+		b.WriteString(fmt.Sprintf("  %s  %5.1f%%  %-20s %d failed%s\n", p.Timestamp.Format("2006-01-02 15:04"), p.Score, bar, p.Failed, delta))
+	}
+	if len(t.Points) < 2 {
+		return b.String()
+	}
+
+	if len(t.NewlyFailing) > 0 {
+		b.WriteString(fmt.Sprintf("\n❌ NEWLY FAILING (%d)\n", len(t.NewlyFailing)))
+		for _, c := range t.NewlyFailing {
+			b.WriteString(fmt.Sprintf("  [%s] %s — %s\n", c.Severity, c.ID, c.Title))
+		}
+	}
+	if len(t.NewlyFixed) > 0 {
+		b.WriteString(fmt.Sprintf("\n✅ NEWLY FIXED (%d)\n", len(t.NewlyFixed)))
+		for _, c := range t.NewlyFixed {
+			b.WriteString(fmt.Sprintf("  [%s] %s — %s\n", c.Severity, c.ID, c.Title))
+		}
+	}
+	if t.Regressed() {
+		b.WriteString(fmt.Sprintf("\n📉 Regression since the previous audit (%+.1f)\n", t.ScoreDelta))
+	} else {
+		b.WriteString(fmt.Sprintf("\n✅ No regression since the previous audit (%+.1f)\n", t.ScoreDelta))
+	}
+	return b.String()
+}
@@ -0,0 +1,253 @@
+package compliance
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TechnicalResult is the outcome of one technical check a control maps to.
+type TechnicalResult struct {
+	ID      string      `json:"id"`
+	Status  CheckStatus `json:"status"`
+	Details string      `json:"details,omitempty"`
+}
+
+// auditRun evaluates controls against one evidence set, evaluating each
+// technical check at most once.
+type auditRun struct {
+	auditor *Auditor
+	ev      *Evidence
+	results map[string]CheckResult
+}
+
+func (a *Auditor) newRun(ev *Evidence) *auditRun {
+	return &auditRun{auditor: a, ev: ev, results: make(map[string]CheckResult)}
+}
+
+// technicalResult returns the result of a technical check, evaluating it on
+// first use.
+func (r *auditRun) technicalResult(id string) CheckResult {
+	if res, ok := r.results[id]; ok {
+		return res
+	}
+	var res CheckResult
+	if tc, ok := r.auditor.technical[id]; ok {
+		res = tc.Evaluate(r.ev)
+		res.Actions = tc.Fix.actions(tc.Title, res.Resources)
+	} else {
+		res = CheckResult{Status: StatusSkip, Details: "unknown technical check " + id}
+	}
+	r.results[id] = res
+	return res
+}
+
+// check evaluates a control: its own CheckFunc, or the combined results of
+// its technical checks.
+func (r *auditRun) check(c *Check) CheckResult {
+	if c.CheckFunc != nil {
+		return c.CheckFunc(r.ev)
+	}
+	if len(c.Technical) == 0 {
+		return CheckResult{Status: StatusSkip, Details: "no check function or technical checks"}
+	}
+	if len(c.Technical) == 1 {
+		res := r.technicalResult(c.Technical[0])
+		res.Technical = []TechnicalResult{{ID: c.Technical[0], Status: res.Status, Details: res.Details}}
+		return res
+	}
+
+	combined := CheckResult{Status: StatusSkip}
+	var details, remediation, unevaluated []string
+	for _, id := range c.Technical {
+		res := r.technicalResult(id)
+		if res.Status == StatusSkip {
+			unevaluated = append(unevaluated, id)
+		}
+		combined.Technical = append(combined.Technical, TechnicalResult{ID: id, Status: res.Status, Details: res.Details})
+		combined.Resources = append(combined.Resources, res.Resources...)
+		combined.Actions = append(combined.Actions, res.Actions...)
+		details = append(details, fmt.Sprintf("%s: %s", id, res.Details))
+		if res.Status == StatusFail && res.Remediation != "" {
+			remediation = append(remediation, res.Remediation)
+		}
+		if statusRank[res.Status] > statusRank[combined.Status] {
+			combined.Status = res.Status
+		}
+	}
+	// A control is only as good as its evidence: passing on some mapped
+	// checks while others were not evaluated is a partial result.
+	if len(unevaluated) > 0 && combined.Status != StatusSkip {
+		if combined.Status == StatusPass {
+			combined.Status = StatusWarn
+		}
+		details = append([]string{fmt.Sprintf("partial evidence, not evaluated: %s", strings.Join(unevaluated, ", "))}, details...)
+	}
+	combined.Details = strings.Join(details, "; ")
+	combined.Remediation = strings.Join(remediation, "; ")
+	return combined
+}
+
+// statusRank orders statuses when combining technical results: any failure
+// fails the control, and a control is skipped only when nothing was
+// evaluated. A pass with unevaluated checks is raised to a warning by check.
+var statusRank = map[CheckStatus]int{StatusSkip: 0, StatusPass: 1, StatusWarn: 2, StatusFail: 3}
+
+// frameworkOrder is the display order of the built-in frameworks; others
+// follow alphabetically.
+var frameworkOrder = map[Framework]int{FrameworkCIS: 1, FrameworkSOC2: 2, FrameworkHIPAA: 3, FrameworkPCIDSS: 4}
+
+func sortFrameworks(frameworks []Framework) {
+	sort.Slice(frameworks, func(i, j int) bool {
+		oi, oj := frameworkOrder[frameworks[i]], frameworkOrder[frameworks[j]]
+		if oi == 0 {
+			oi = len(frameworkOrder) + 1
+		}
+		if oj == 0 {
+			oj = len(frameworkOrder) + 1
+		}
+		if oi != oj {
+			return oi < oj
+		}
+		return frameworks[i] < frameworks[j]
+	})
+}
+
+// AuditSet holds the reports of auditing every framework against one
+// evidence collection.
+type AuditSet struct {
+	Timestamp time.Time `json:"timestamp"`
+	Reports   []*Report `json:"reports"`
+	Matrix    *Matrix   `json:"matrix"`
+	Evaluated int       `json:"technical_checks_evaluated"`
+	Controls  int       `json:"controls"`
+}
+
+// AuditAll audits every registered framework. Evidence is collected once and
+// each technical check is evaluated once, however many controls it satisfies.
+func (a *Auditor) AuditAll() *AuditSet {
+	set := &AuditSet{Timestamp: time.Now()}
+	var run *auditRun
+	for _, fw := range a.ListFrameworks() {
+		report := &Report{Framework: fw, Environment: a.env, Timestamp: set.Timestamp}
+		if run == nil {
+			run = a.newRun(a.collect(report))
+		} else {
+			first := set.Reports[0]
+			report.Evidence, report.CollectionErrors = first.Evidence, first.CollectionErrors
+		}
+		a.fillReport(report, a.checks[fw], run)
+		set.Reports = append(set.Reports, report)
+		set.Controls += report.TotalChecks
+	}
+	set.Matrix = a.Matrix()
+	if run != nil {
+		set.Evaluated = len(run.results)
+		for _, row := range set.Matrix.Rows {
+			if res, ok := run.results[row.Check]; ok {
+				row.Status = res.Status
+			}
+		}
+	}
+	return set
+}
+
+// Render formats every report followed by the control matrix.
+func (s *AuditSet) Render() string {
+	var b strings.Builder
+	for _, r := range s.Reports {
+		b.WriteString(r.Render())
+		b.WriteString("\n")
+	}
+	b.WriteString(fmt.Sprintf("🔁 %d technical checks evaluated once for %d controls across %d frameworks\n\n", s.Evaluated, s.Controls, len(s.Reports)))
+	b.WriteString(s.Matrix.Render())
+	return b.String()
+}
+
+// MatrixRow is one technical check and the framework controls it supports.
+type MatrixRow struct {
+	Check    string                 `json:"check"`
+	Title    string                 `json:"title"`
+	Status   CheckStatus            `json:"status,omitempty"` // set by AuditAll
+	Controls map[Framework][]string `json:"controls"`
+}
+
+// Matrix maps technical checks to framework controls.
+type Matrix struct {
+	Frameworks []Framework  `json:"frameworks"`
+	Rows       []*MatrixRow `json:"rows"`
+}
+
+// Matrix builds the control matrix of the registered checks: one row per
+// technical check referenced by at least one control.
+func (a *Auditor) Matrix() *Matrix {
+	m := &Matrix{Frameworks: a.ListFrameworks()}
+	rows := make(map[string]*MatrixRow)
+	for _, fw := range m.Frameworks {
+		for _, c := range a.checks[fw] {
+			for _, id := range c.Technical {
+				row, ok := rows[id]
+				if !ok {
+					row = &MatrixRow{Check: id, Controls: make(map[Framework][]string)}
+					if tc, known := a.technical[id]; known {
+						row.Title = tc.Title
+					}
+					rows[id] = row
+					m.Rows = append(m.Rows, row)
+				}
+				row.Controls[fw] = append(row.Controls[fw], c.ID)
+			}
+		}
+	}
+	sort.Slice(m.Rows, func(i, j int) bool { return m.Rows[i].Check < m.Rows[j].Check })
+	return m
+}
+
+// Render formats the matrix as a table.
+func (m *Matrix) Render() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("🧩 CONTROL MATRIX (%d technical checks)\n", len(m.Rows)))
+	b.WriteString("─────────────────────────────────────────\n")
+
+	checkWidth := len("CHECK")
+	widths := make([]int, len(m.Frameworks))
+	for i, fw := range m.Frameworks {
+		widths[i] = len(fw)
+	}
+	for _, row := range m.Rows {
+		checkWidth = max(checkWidth, len(row.Check))
+		for i, fw := range m.Frameworks {
+			widths[i] = max(widths[i], len(strings.Join(row.Controls[fw], ",")))
+		}
+	}
+
+	line := func(check, status string, cells []string) {
+		var l strings.Builder
+		l.WriteString(fmt.Sprintf("  %-*s  %-6s", checkWidth, check, status))
+		for i, cell := range cells {
+			l.WriteString(fmt.Sprintf("  %-*s", widths[i], cell))
+		}
+		b.WriteString(strings.TrimRight(l.String(), " ") + "\n")
+	}
+	header := make([]string, len(m.Frameworks))
+	for i, fw := range m.Frameworks {
+		header[i] = string(fw)
+	}
+	line("CHECK", "STATUS", header)
+	for _, row := range m.Rows {
+		status := string(row.Status)
+		if status == "" {
+			status = "-"
+		}
+		cells := make([]string, len(m.Frameworks))
+		for i, fw := range m.Frameworks {
+			cells[i] = strings.Join(row.Controls[fw], ",")
+			if cells[i] == "" {
+				cells[i] = "·"
+			}
+		}
+		line(row.Check, status, cells)
+	}
+	return b.String()
+}
@@ -0,0 +1,98 @@
+package compliance
+
+// PCIDSSControls returns PCI-DSS v4.0 checks for the technical requirements
+// covered by collected evidence.
+func PCIDSSControls() []*Check {
+	return []*Check{
+		{
+			ID:          "PCI-1.3.1",
+			Framework:   FrameworkPCIDSS,
+			Title:       "Inbound traffic to the CDE is restricted",
+			Description: "Network security controls only admit traffic that is necessary; internet ingress is limited to web ports.",
+			Severity:    SeverityCritical,
+			Category:    "Network Security Controls",
+			Technical:   []string{"sg-internet-ingress"},
+		},
+		{
+			ID:          "PCI-1.2.1",
+			Framework:   FrameworkPCIDSS,
+			Title:       "Default network security controls deny all traffic",
+			Description: "Default security groups must not carry rules, so new resources are segmented by default.",
+			Severity:    SeverityHigh,
+			Category:    "Network Security Controls",
+			Technical:   []string{"sg-default-closed"},
+		},
+		{
+			ID:          "PCI-3.5.1",
+			Framework:   FrameworkPCIDSS,
+			Title:       "Stored account data is encrypted at rest",
+			Description: "Storage that may hold PAN is encrypted with strong cryptography.",
+			Severity:    SeverityCritical,
+			Category:    "Protect Stored Account Data",
+			Technical:   []string{"s3-encryption", "ebs-encryption", "rds-encryption"},
+		},
+		{
+			ID:          "PCI-4.2.1",
+			Framework:   FrameworkPCIDSS,
+			Title:       "Strong cryptography protects data in transit",
+			Description: "Public endpoints only accept TLS 1.2 or later; plain HTTP is redirected.",
+			Severity:    SeverityCritical,
+			Category:    "Protect Data in Transit",
+			Technical:   []string{"elb-tls"},
+		},
+		{
+			ID:          "PCI-7.2.4",
+			Framework:   FrameworkPCIDSS,
+			Title:       "User accounts and access privileges are reviewed every six months",
+			Description: "Access reviews confirm access remains appropriate.",
+			Severity:    SeverityHigh,
+			Category:    "Restrict Access",
+			Technical:   []string{"access-reviews-180d"},
+		},
+		{
+			ID:          "PCI-8.2.6",
+			Framework:   FrameworkPCIDSS,
+			Title:       "Inactive user accounts are removed or disabled within 90 days",
+			Description: "Unused accounts and credentials increase attack surface.",
+			Severity:    SeverityHigh,
+			Category:    "Identify Users and Authenticate Access",
+			Technical:   []string{"iam-stale-credentials-90d"},
+		},
+		{
+			ID:          "PCI-8.4.1",
+			Framework:   FrameworkPCIDSS,
+			Title:       "MFA is implemented for all access into the CDE",
+			Description: "Console users and the root account authenticate with MFA.",
+			Severity:    SeverityCritical,
+			Category:    "Identify Users and Authenticate Access",
+			Technical:   []string{"iam-console-mfa", "iam-root-mfa"},
+		},
+		{
+			ID:          "PCI-10.2.1",
+			Framework:   FrameworkPCIDSS,
+			Title:       "Audit logs are enabled and active for all system components",
+			Description: "A multi-region trail with log file validation records all API activity.",
+			Severity:    SeverityHigh,
+			Category:    "Log and Monitor All Access",
+			Technical:   []string{"cloudtrail-multi-region", "cloudtrail-log-validation"},
+		},
+		{
+			ID:          "PCI-10.5.1",
+			Framework:   FrameworkPCIDSS,
+			Title:       "Audit log history is retained for at least 12 months",
+			Description: "Log groups keep audit logs for 365 days or longer.",
+			Severity:    SeverityMedium,
+			Category:    "Log and Monitor All Access",
+			Technical:   []string{"log-retention-365d"},
+		},
+		{
+			ID:          "PCI-11.3.1",
+			Framework:   FrameworkPCIDSS,
+			Title:       "High-risk and critical vulnerabilities are resolved",
+			Description: "Internal vulnerability scans (trivy.scan) show no HIGH or CRITICAL findings.",
+			Severity:    SeverityHigh,
+			Category:    "Test Security Regularly",
+			Technical:   []string{"vuln-scan-high"},
+		},
+	}
+}
@@ -0,0 +1,135 @@
+package compliance
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/parth14193/ownbot/pkg/core"
+	"github.com/parth14193/ownbot/pkg/expr"
+	"github.com/parth14193/ownbot/pkg/planner"
+)
+
+// Fix is a machine-actionable remediation: a skill to run for each failing
+// resource whose name starts with Match. Param values are templates (see
+// expr.Interpolate) over resource, the failing resource, and id, the resource
+// without its "kind/" prefix.
+type Fix struct {
+	Skill  string            `json:"skill" yaml:"skill"`
+	Match  string            `json:"match,omitempty" yaml:"match"`
+	Params map[string]string `json:"params,omitempty" yaml:"params"`
+}
+
+// Action is a fix resolved for one failing resource.
+type Action struct {
+	Resource string                 `json:"resource"`
+	Title    string                 `json:"title"` // of the check that failed
+	Skill    string                 `json:"skill"`
+	Params   map[string]interface{} `json:"params,omitempty"`
+}
+
+// actions resolves the fix for each failing resource it matches; title is
+// the title of the failing check.
+func (f *Fix) actions(title string, resources []ResourceResult) []Action {
+	if f == nil {
+		return nil
+	}
+	var out []Action
+	for _, res := range filterResources(resources, StatusFail) {
+		if !strings.HasPrefix(res.Resource, f.Match) {
+			continue
+		}
+		id := res.Resource
+		if _, after, found := strings.Cut(res.Resource, "/"); found {
+			id = after
+		}
+		vars := map[string]interface{}{"resource": res.Resource, "id": id}
+		params := make(map[string]interface{}, len(f.Params))
+		for name, tmpl := range f.Params {
+			params[name] = expr.Interpolate(tmpl, vars)
+		}
+		out = append(out, Action{Resource: res.Resource, Title: title, Skill: f.Skill, Params: params})
+	}
+	return out
+}
+
+// key identifies an action by skill and params, so a resource failing
+// several controls is fixed once.
+func (a Action) key() string {
+	names := make([]string, 0, len(a.Params))
+	for name := range a.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	b.WriteString(a.Skill)
+	for _, name := range names {
+		b.WriteString(fmt.Sprintf(" %s=%v", name, a.Params[name]))
+	}
+	return b.String()
+}
+
+// RemediationPlan turns the fix actions of failed checks into a plan for
+// review. Each resource is fixed by one step that names every control it
+// satisfies. Failed checks without actions need manual remediation (see
+// ManualRemediations).
+func RemediationPlan(engine *planner.Engine, reports ...*Report) (*core.Plan, error) {
+	type step struct {
+		action   Action
+		controls []string
+	}
+	var steps []*step
+	byKey := make(map[string]*step)
+	var frameworks []string
+	for _, r := range reports {
+		frameworks = append(frameworks, string(r.Framework))
+		for _, c := range filterByStatus(r.Results, StatusFail) {
+			for _, a := range c.Actions {
+				s, ok := byKey[a.key()]
+				if !ok {
+					s = &step{action: a}
+					byKey[a.key()] = s
+					steps = append(steps, s)
+				}
+				s.controls = append(s.controls, c.ID)
+			}
+		}
+	}
+
+	plan := engine.CreatePlan("Compliance remediation",
+		fmt.Sprintf("Fix %d failing resource(s) found by %s audits", len(steps), strings.Join(frameworks, ", ")))
+	var errs []error
+	for _, s := range steps {
+		description := fmt.Sprintf("%s — %s (%s)", s.action.Resource, s.action.Title, strings.Join(s.controls, ", "))
+		if err := engine.AddStep(plan, s.action.Skill, description, s.action.Params); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.action.Resource, err))
+		}
+	}
+	return plan, errors.Join(errs...)
+}
+
+// ManualRemediations returns the failed checks with resources no fix action
+// covers.
+func ManualRemediations(reports ...*Report) []CheckResult {
+	var out []CheckResult
+	for _, r := range reports {
+		for _, c := range filterByStatus(r.Results, StatusFail) {
+			covered := make(map[string]bool)
+			for _, a := range c.Actions {
+				covered[a.Resource] = true
+			}
+			failing := filterResources(c.Resources, StatusFail)
+			manual := len(failing) == 0 && len(c.Actions) == 0
+			for _, res := range failing {
+				if !covered[res.Resource] {
+					manual = true
+				}
+			}
+			if manual {
+				out = append(out, c)
+			}
+		}
+	}
+	return out
+}
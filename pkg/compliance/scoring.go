@@ -0,0 +1,173 @@
+package compliance
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ScoringModel weights check results by severity. SKIP results (no evidence,
+// not applicable) are excluded; FAIL earns nothing and WARN earns WarnCredit
+// of the check's weight.
+type ScoringModel struct {
+	Weights    map[Severity]float64 `json:"weights"`
+	WarnCredit float64              `json:"warn_credit"`
+	Thresholds Thresholds           `json:"thresholds"`
+}
+
+// Thresholds define when an audit breaches policy. Zero values disable a
+// threshold.
+type Thresholds struct {
+	MinScore         float64 `json:"min_score,omitempty"`          // weighted score, percent
+	MinCategoryScore float64 `json:"min_category_score,omitempty"` // any category subscore, percent
+	FailOnCritical   bool    `json:"fail_on_critical,omitempty"`   // any failed CRITICAL check
+}
+
+// DefaultScoringModel returns the default severity weights with no
+// thresholds.
+func DefaultScoringModel() ScoringModel {
+	return ScoringModel{
+		Weights: map[Severity]float64{
+			SeverityCritical: 10,
+			SeverityHigh:     5,
+			SeverityMedium:   3,
+			SeverityLow:      1,
+		},
+		WarnCredit: 0.5,
+	}
+}
+
+// weight returns the weight of severity; checks without a known severity
+// weigh as LOW.
+func (m ScoringModel) weight(severity Severity) float64 {
+	if w, ok := m.Weights[severity]; ok {
+		return w
+	}
+	if w, ok := m.Weights[SeverityLow]; ok {
+		return w
+	}
+	return 1
+}
+
+// credit returns the fraction of its weight a result earns, and whether the
+// result counts towards the score at all.
+func (m ScoringModel) credit(status CheckStatus) (float64, bool) {
+	switch status {
+	case StatusPass:
+		return 1, true
+	case StatusWarn:
+		return m.WarnCredit, true
+	case StatusFail:
+		return 0, true
+	}
+	return 0, false
+}
+
+// CategoryScore is the weighted score of the checks in one category.
+type CategoryScore struct {
+	Category  string  `json:"category"`
+	Score     float64 `json:"score"`
+	Evaluated int     `json:"evaluated"`
+	Failed    int     `json:"failed"`
+}
+
+type scoreSum struct{ earned, possible float64 }
+
+func (s scoreSum) percent() float64 {
+	if s.possible == 0 {
+		return 100
+	}
+	return s.earned / s.possible * 100
+}
+
+// score sets the report's weighted score, category subscores and threshold
+// breaches. A report without PASS, WARN or FAIL results is unscored. When any
+// threshold is set, an unscored report and evidence that failed to collect
+// are breaches too: a gate must not pass because nothing was measured.
+func (m ScoringModel) score(r *Report) {
+	var total scoreSum
+	sums := make(map[string]*scoreSum)
+	categories := make(map[string]*CategoryScore)
+	for _, c := range r.Results {
+		credit, counted := m.credit(c.Status)
+		if !counted {
+			continue
+		}
+		w := m.weight(c.Severity)
+		total.earned += w * credit
+		total.possible += w
+
+		category := c.Category
+		if category == "" {
+			category = "Uncategorised"
+		}
+		cs, ok := categories[category]
+		if !ok {
+			cs = &CategoryScore{Category: category}
+			categories[category] = cs
+			sums[category] = &scoreSum{}
+		}
+		sums[category].earned += w * credit
+		sums[category].possible += w
+		cs.Evaluated++
+		if c.Status == StatusFail {
+			cs.Failed++
+		}
+	}
+
+	r.Score = 0
+	r.Unscored = total.possible == 0
+	if !r.Unscored {
+		r.Score = total.percent()
+	}
+	r.Categories = nil
+	for name, cs := range categories {
+		cs.Score = sums[name].percent()
+		r.Categories = append(r.Categories, *cs)
+	}
+	sort.Slice(r.Categories, func(i, j int) bool { return r.Categories[i].Category < r.Categories[j].Category })
+	r.Breaches = nil
+	if !m.Thresholds.enabled() {
+		return
+	}
+	if r.Unscored {
+		r.Breaches = append(r.Breaches, "no check was evaluated, so the thresholds cannot be met")
+	} else {
+		r.Breaches = m.Thresholds.breaches(r)
+	}
+	if n := len(r.CollectionErrors); n > 0 {
+		r.Breaches = append(r.Breaches, fmt.Sprintf("evidence collection failed (%d error(s)), so the score is incomplete", n))
+	}
+}
+
+// enabled reports whether any threshold is set.
+func (t Thresholds) enabled() bool {
+	return t.MinScore > 0 || t.MinCategoryScore > 0 || t.FailOnCritical
+}
+
+// breaches lists the thresholds r violates.
+func (t Thresholds) breaches(r *Report) []string {
+	var out []string
+	if t.MinScore > 0 && r.Score < t.MinScore {
+		out = append(out, fmt.Sprintf("weighted score %.1f%% is below the minimum %.1f%%", r.Score, t.MinScore))
+	}
+	if t.MinCategoryScore > 0 {
+		for _, cs := range r.Categories {
+			if cs.Score < t.MinCategoryScore {
+				out = append(out, fmt.Sprintf("category %q scores %.1f%%, below the minimum %.1f%%", cs.Category, cs.Score, t.MinCategoryScore))
+			}
+		}
+	}
+	if t.FailOnCritical {
+		var ids []string
+		for _, c := range r.Results {
+			if c.Status == StatusFail && c.Severity == SeverityCritical {
+				ids = append(ids, c.ID)
+			}
+		}
+		if len(ids) > 0 {
+			out = append(out, "CRITICAL checks failed: "+strings.Join(ids, ", "))
+		}
+	}
+	return out
+}
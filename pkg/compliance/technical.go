@@ -0,0 +1,235 @@
+package compliance
+
+import "fmt"
+
+// TechnicalCheck is one evaluation of collected evidence. Framework controls
+// reference technical checks by ID (Check.Technical), so a check shared by
+// CIS, SOC2, HIPAA and PCI-DSS controls is evaluated once per audit and its
+// result satisfies every mapped control.
+type TechnicalCheck struct {
+	ID       string    `json:"id"`
+	Title    string    `json:"title"`
+	Evidence []string  `json:"evidence"` // evidence keys read
+	Evaluate CheckFunc `json:"-"`
+	Fix      *Fix      `json:"fix,omitempty"` // applied to failing resources
+}
+
+// TechnicalChecks returns the built-in technical checks.
+func TechnicalChecks() []*TechnicalCheck {
+	return []*TechnicalCheck{
+		// ── IAM ──────────────────────────────────────────────
+		{
+			ID: "iam-root-unused", Title: "Root account unused for 90 days",
+			Evidence: []string{EvidenceCredentialReport},
+			Evaluate: objectsCheck(EvidenceCredentialReport, func(rows []map[string]interface{}, ev *Evidence) CheckResult {
+				return evaluate(rootActivity(rows, ev.CollectedAt, 90), "root account unused for 90 days",
+					"Lock away root credentials, delete root access keys and use IAM roles for daily work")
+			}),
+		},
+		{
+			ID: "iam-root-access-keys", Title: "Root account has no active access keys",
+			Evidence: []string{EvidenceCredentialReport},
+			Evaluate: objectsCheck(EvidenceCredentialReport, func(rows []map[string]interface{}, _ *Evidence) CheckResult {
+				return evaluate(rootAccessKeys(rows), "no root access keys",
+					"Delete root access keys: IAM console → Security credentials (root)")
+			}),
+		},
+		{
+			ID: "iam-root-mfa", Title: "Root account has MFA",
+			Evidence: []string{EvidenceCredentialReport},
+			Evaluate: objectsCheck(EvidenceCredentialReport, func(rows []map[string]interface{}, _ *Evidence) CheckResult {
+				return evaluate(rootMFA(rows), "root account has MFA",
+					"Enable a hardware MFA device on the root account")
+			}),
+		},
+		{
+			ID: "iam-console-mfa", Title: "Console users have MFA",
+			Evidence: []string{EvidenceCredentialReport},
+			Evaluate: objectsCheck(EvidenceCredentialReport, func(rows []map[string]interface{}, _ *Evidence) CheckResult {
+				return evaluate(consoleMFA(rows), "all console users have MFA",
+					"aws iam enable-mfa-device for each listed user, or remove their console password")
+			}),
+		},
+		{
+			ID: "iam-stale-credentials-90d", Title: "No credentials unused for 90+ days",
+			Evidence: []string{EvidenceCredentialReport},
+			Evaluate: objectsCheck(EvidenceCredentialReport, func(rows []map[string]interface{}, ev *Evidence) CheckResult {
+				return evaluate(staleCredentials(rows, ev.CollectedAt, 90), "no credentials unused for 90+ days",
+					"aws iam update-access-key --status Inactive / aws iam delete-login-profile for each listed credential")
+			}),
+		},
+		{
+			ID: "iam-stale-credentials-30d", Title: "No credentials unused for 30+ days",
+			Evidence: []string{EvidenceCredentialReport},
+			Evaluate: objectsCheck(EvidenceCredentialReport, func(rows []map[string]interface{}, ev *Evidence) CheckResult {
+				return evaluate(staleCredentials(rows, ev.CollectedAt, 30), "no credentials unused for 30+ days",
+					"Implement automated deprovisioning via SSO integration")
+			}),
+		},
+		{
+			ID: "access-reviews-180d", Title: "Access reviewed within six months",
+			Evidence: []string{EvidenceAccessReviews},
+			Evaluate: objectsCheck(EvidenceAccessReviews, func(reviews []map[string]interface{}, ev *Evidence) CheckResult {
+				return evaluate(recentReviews(reviews, ev.CollectedAt, 180), "access reviewed within six months",
+					"Run and record an access review for each listed scope")
+			}),
+		},
+
+		// ── Logging ──────────────────────────────────────────
+		{
+			ID: "cloudtrail-multi-region", Title: "Multi-region CloudTrail is logging",
+			Evidence: []string{EvidenceTrails},
+			Evaluate: objectsCheck(EvidenceTrails, func(trails []map[string]interface{}, _ *Evidence) CheckResult {
+				return evaluate(multiRegionTrail(trails), "multi-region trail is logging",
+					"Enable multi-region CloudTrail: aws cloudtrail create-trail --is-multi-region-trail")
+			}),
+		},
+		{
+			ID: "cloudtrail-log-validation", Title: "CloudTrail log file validation",
+			Evidence: []string{EvidenceTrails},
+			Evaluate: objectsCheck(EvidenceTrails, func(trails []map[string]interface{}, _ *Evidence) CheckResult {
+				return evaluate(trailsWith(trails, "LogFileValidationEnabled", "log file validation"), "all trails validate log files",
+					"aws cloudtrail update-trail --enable-log-file-validation")
+			}),
+			Fix: &Fix{Skill: "aws.cloudtrail.validation", Match: "trail/", Params: map[string]string{"trail_name": "{{ id }}"}},
+		},
+		{
+			ID: "cloudtrail-kms", Title: "CloudTrail logs encrypted with KMS",
+			Evidence: []string{EvidenceTrails},
+			Evaluate: objectsCheck(EvidenceTrails, func(trails []map[string]interface{}, _ *Evidence) CheckResult {
+				return evaluate(trailsWith(trails, "KmsKeyId", "KMS encryption"), "all trails are KMS encrypted",
+					"aws cloudtrail update-trail --kms-key-id <KMS_KEY_ARN>")
+			}),
+		},
+		{
+			ID: "log-retention-365d", Title: "Logs retained for 12 months",
+			Evidence: []string{EvidenceLogGroups},
+			Evaluate: objectsCheck(EvidenceLogGroups, func(groups []map[string]interface{}, _ *Evidence) CheckResult {
+				return evaluate(logRetention(groups, 365), "logs retained for 12 months",
+					"aws logs put-retention-policy --log-group-name <GROUP> --retention-in-days 365")
+			}),
+			Fix: &Fix{Skill: "aws.logs.retention", Match: "log-group/", Params: map[string]string{"log_group": "{{ id }}", "retention_days": "365"}},
+		},
+
+		// ── Networking ───────────────────────────────────────
+		{
+			ID: "vpc-flow-logs", Title: "VPC flow logs enabled",
+			Evidence: []string{EvidenceVPCs, EvidenceFlowLogs},
+			Evaluate: func(ev *Evidence) CheckResult {
+				vpcs, ok := ev.Objects(EvidenceVPCs)
+				if !ok {
+					return noEvidence(EvidenceVPCs)
+				}
+				flowLogs, ok := ev.Objects(EvidenceFlowLogs)
+				if !ok {
+					return noEvidence(EvidenceFlowLogs)
+				}
+				return evaluate(vpcFlowLogs(vpcs, flowLogs), "all VPCs have active flow logs",
+					"aws ec2 create-flow-logs --resource-ids <VPC_ID> --traffic-type ALL")
+			},
+			Fix: &Fix{Skill: "aws.vpc.flowlogs", Match: "vpc-", Params: map[string]string{"vpc_id": "{{ id }}"}},
+		},
+		{
+			ID: "sg-default-closed", Title: "Default security groups restrict all traffic",
+			Evidence: []string{EvidenceSecurityGroups},
+			Evaluate: objectsCheck(EvidenceSecurityGroups, func(groups []map[string]interface{}, _ *Evidence) CheckResult {
+				return evaluate(defaultGroupsClosed(groups), "default security groups have no rules",
+					"Remove all rules from default SGs: aws ec2 revoke-security-group-ingress")
+			}),
+		},
+		{
+			ID: "sg-internet-ingress", Title: "Internet ingress limited to web ports",
+			Evidence: []string{EvidenceSecurityGroups},
+			Evaluate: objectsCheck(EvidenceSecurityGroups, func(groups []map[string]interface{}, _ *Evidence) CheckResult {
+				return evaluate(internetIngress(groups), "internet ingress limited to 80/443",
+					"Revoke internet ingress on other ports: aws ec2 revoke-security-group-ingress; reach admin ports through a bastion or SSM")
+			}),
+		},
+		{
+			ID: "elb-tls", Title: "Load balancer listeners enforce TLS 1.2+",
+			Evidence: []string{EvidenceListeners},
+			Evaluate: objectsCheck(EvidenceListeners, func(listeners []map[string]interface{}, _ *Evidence) CheckResult {
+				return evaluate(listenerTLS(listeners), "listeners enforce TLS 1.2+",
+					"aws elbv2 modify-listener --ssl-policy ELBSecurityPolicy-TLS13-1-2-2021-06; redirect HTTP listeners to HTTPS")
+			}),
+		},
+
+		// ── Storage ──────────────────────────────────────────
+		{
+			ID: "s3-access-logging", Title: "S3 access logging enabled",
+			Evidence: []string{EvidenceBuckets},
+			Evaluate: objectsCheck(EvidenceBuckets, func(buckets []map[string]interface{}, _ *Evidence) CheckResult {
+				return evaluate(bucketLogging(buckets), "all buckets have access logging",
+					"aws s3api put-bucket-logging --bucket <BUCKET> --bucket-logging-status ...")
+			}),
+		},
+		{
+			ID: "s3-encryption", Title: "S3 default encryption enabled",
+			Evidence: []string{EvidenceBuckets},
+			Evaluate: objectsCheck(EvidenceBuckets, func(buckets []map[string]interface{}, _ *Evidence) CheckResult {
+				return evaluate(bucketEncryption(buckets), "all buckets have default encryption",
+					"aws s3api put-bucket-encryption --bucket <BUCKET> --sse AES256")
+			}),
+			Fix: &Fix{Skill: "aws.s3.encrypt", Match: "bucket/", Params: map[string]string{"bucket_name": "{{ id }}"}},
+		},
+		{
+			ID: "s3-versioning", Title: "S3 versioning enabled",
+			Evidence: []string{EvidenceBuckets},
+			Evaluate: objectsCheck(EvidenceBuckets, func(buckets []map[string]interface{}, _ *Evidence) CheckResult {
+				return evaluate(bucketVersioning(buckets), "all buckets are versioned",
+					"Enable S3 versioning: aws s3api put-bucket-versioning --status Enabled")
+			}),
+			Fix: &Fix{Skill: "aws.s3.versioning", Match: "bucket/", Params: map[string]string{"bucket_name": "{{ id }}"}},
+		},
+		{
+			ID: "ebs-encryption", Title: "EBS volumes encrypted",
+			Evidence: []string{EvidenceVolumes},
+			Evaluate: objectsCheck(EvidenceVolumes, func(volumes []map[string]interface{}, _ *Evidence) CheckResult {
+				return evaluate(encrypted(volumes, "VolumeId", "Encrypted", "volume"), "all volumes are encrypted",
+					"aws ec2 enable-ebs-encryption-by-default; migrate listed volumes via encrypted snapshots")
+			}),
+		},
+		{
+			ID: "rds-encryption", Title: "RDS storage encrypted",
+			Evidence: []string{EvidenceDBInstances},
+			Evaluate: objectsCheck(EvidenceDBInstances, func(dbs []map[string]interface{}, _ *Evidence) CheckResult {
+				return evaluate(encrypted(dbs, "DBInstanceIdentifier", "StorageEncrypted", "db"), "all databases are encrypted",
+					"Restore listed databases from an encrypted snapshot copy")
+			}),
+		},
+
+		// ── Change management and vulnerabilities ────────────
+		{
+			ID: "iac-drift", Title: "No changes made outside IaC",
+			Evidence: []string{EvidenceDrift},
+			Evaluate: objectsCheck(EvidenceDrift, func(drifted []map[string]interface{}, _ *Evidence) CheckResult {
+				var results []ResourceResult
+				for _, d := range drifted {
+					results = append(results, fail(str(d, "resource"), fmt.Sprintf("changed outside IaC (%s)", str(d, "type"))))
+				}
+				return evaluate(results, "no changes made outside IaC",
+					"Enforce Terraform/Pulumi for all infra changes with PR reviews")
+			}),
+		},
+		{
+			ID: "vuln-scan-high", Title: "No HIGH/CRITICAL vulnerabilities in scanned images",
+			Evidence: []string{EvidenceScans},
+			Evaluate: objectsCheck(EvidenceScans, func(reports []map[string]interface{}, _ *Evidence) CheckResult {
+				return evaluate(scanFindings(reports, "CRITICAL", "HIGH"), "no HIGH or CRITICAL vulnerabilities",
+					"Patch or rebuild the listed images and rescan: infracore run trivy.scan image=<IMAGE>")
+			}),
+		},
+	}
+}
+
+// objectsCheck adapts an evaluation of the objects under key, skipping when
+// the key was not collected.
+func objectsCheck(key string, fn func(objs []map[string]interface{}, ev *Evidence) CheckResult) CheckFunc {
+	return func(ev *Evidence) CheckResult {
+		objs, ok := ev.Objects(key)
+		if !ok {
+			return noEvidence(key)
+		}
+		return fn(objs, ev)
+	}
+}
@@ -0,0 +1,293 @@
+package compliance
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/parth14193/ownbot/pkg/config"
+	"github.com/parth14193/ownbot/pkg/environment"
+	"github.com/parth14193/ownbot/pkg/policy"
+)
+
+// Waiver accepts the risk of a failing check within a scope until it
+// expires. A waiver without resources waives the whole check; otherwise it
+// waives only the failing resources it matches.
+type Waiver struct {
+	ID            string    `json:"id"`
+	Check         string    `json:"check"`
+	Framework     Framework `json:"framework,omitempty"`    // empty matches any
+	Resources     []string  `json:"resources,omitempty"`    // glob allowed
+	Environments  []string  `json:"environments,omitempty"` // environments or tiers; empty matches any
+	Approver      string    `json:"approver"`
+	Justification string    `json:"justification"`
+	Ticket        string    `json:"ticket,omitempty"`
+	Expires       time.Time `json:"expires"`
+}
+
+// Expired reports whether the waiver no longer applies at the given time.
+func (w *Waiver) Expired(at time.Time) bool {
+	return !at.Before(w.Expires)
+}
+
+// WaiverRegistry holds compliance waivers.
+type WaiverRegistry struct {
+	waivers    []*Waiver
+	classifier *environment.Classifier
+	now        func() time.Time
+}
+
+// NewWaiverRegistry creates an empty registry.
+func NewWaiverRegistry() *WaiverRegistry {
+	return &WaiverRegistry{
+		classifier: environment.DefaultClassifier(),
+		now:        time.Now,
+	}
+}
+
+// SetClassifier replaces the environment tier classifier used for matching.
+func (r *WaiverRegistry) SetClassifier(classifier *environment.Classifier) {
+	r.classifier = classifier
+}
+
+// SetClock overrides the time source (for testing).
+func (r *WaiverRegistry) SetClock(now func() time.Time) {
+	r.now = now
+}
+
+// Add validates and registers a waiver. Check, approver, justification and
+// expiry are required; a missing ID is generated.
+func (r *WaiverRegistry) Add(w *Waiver) error {
+	var missing []string
+	if w.Check == "" {
+		missing = append(missing, "check")
+	}
+	if w.Approver == "" {
+		missing = append(missing, "approver")
+	}
+	if strings.TrimSpace(w.Justification) == "" {
+		missing = append(missing, "justification")
+	}
+	if w.Expires.IsZero() {
+		missing = append(missing, "expires")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("waiver for '%s': missing %s", w.Check, strings.Join(missing, ", "))
+	}
+	for _, pattern := range w.Resources {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("waiver for '%s': invalid resource pattern '%s': %w", w.Check, pattern, err)
+		}
+	}
+	if w.ID == "" {
+		w.ID = fmt.Sprintf("WVR-%03d", len(r.waivers)+1)
+	}
+	for _, existing := range r.waivers {
+		if existing.ID == w.ID {
+			return fmt.Errorf("duplicate waiver id '%s'", w.ID)
+		}
+	}
+	r.waivers = append(r.waivers, w)
+	return nil
+}
+
+// List returns all waivers ordered by expiry.
+func (r *WaiverRegistry) List() []*Waiver {
+	out := append([]*Waiver(nil), r.waivers...)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Expires.Before(out[j].Expires) })
+	return out
+}
+
+// Expiring returns unexpired waivers that expire within the given window,
+// ordered by expiry.
+func (r *WaiverRegistry) Expiring(within time.Duration) []*Waiver {
+	now := r.now()
+	var out []*Waiver
+	for _, w := range r.List() {
+		if !w.Expired(now) && w.Expires.Before(now.Add(within)) {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// scopes reports whether the waiver covers the check in fw and env. With
+// resource empty it matches only whole-check waivers.
+func (r *WaiverRegistry) scopes(w *Waiver, check string, fw Framework, env, resource string) bool {
+	if w.Check != check || (w.Framework != "" && w.Framework != fw) {
+		return false
+	}
+	if len(w.Environments) > 0 && !r.classifier.MatchesAny(env, w.Environments) {
+		return false
+	}
+	if len(w.Resources) == 0 {
+		return resource == ""
+	}
+	for _, pattern := range w.Resources {
+		if ok, _ := path.Match(pattern, resource); ok && resource != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// find returns the active waiver in scope, or nil, recording expired
+// waivers that would have applied in lapsed.
+func (r *WaiverRegistry) find(check string, fw Framework, env, resource string, lapsed map[string]bool) *Waiver {
+	now := r.now()
+	for _, w := range r.waivers {
+		if !r.scopes(w, check, fw, env, resource) {
+			continue
+		}
+		if w.Expired(now) {
+			lapsed[w.ID] = true
+			continue
+		}
+		return w
+	}
+	return nil
+}
+
+// apply waives a failed result: every failing resource when a whole-check
+// waiver is in scope, otherwise the failing resources a waiver matches. The
+// result is WAIVED once no failing resource remains. Fix actions for waived
+// resources are dropped. A result that still fails records the waivers that
+// expired.
+func (r *WaiverRegistry) apply(res *CheckResult, fw Framework, env string) {
+	if res.Status != StatusFail {
+		return
+	}
+	lapsed := make(map[string]bool)
+	used := make(map[string]bool)
+	waive := func(w *Waiver) {
+		if !used[w.ID] {
+			used[w.ID] = true
+			res.Waivers = append(res.Waivers, w)
+		}
+	}
+
+	whole := r.find(res.ID, fw, env, "", lapsed)
+	failing := 0
+	waived := make(map[string]bool)
+	for i := range res.Resources {
+		rr := &res.Resources[i]
+		if rr.Status != StatusFail {
+			continue
+		}
+		w := whole
+		if w == nil {
+			w = r.find(res.ID, fw, env, rr.Resource, lapsed)
+		}
+		if w == nil {
+			failing++
+			continue
+		}
+		waive(w)
+		rr.Status = StatusWaived
+		rr.Waiver = w.ID
+		waived[rr.Resource] = true
+	}
+	if whole != nil {
+		waive(whole)
+	}
+
+	if len(waived) > 0 {
+		var actions []Action
+		for _, a := range res.Actions {
+			if !waived[a.Resource] {
+				actions = append(actions, a)
+			}
+		}
+		res.Actions = actions
+	}
+	if whole != nil || (len(waived) > 0 && failing == 0) {
+		res.Status = StatusWaived
+		return
+	}
+	for id := range lapsed {
+		res.LapsedWaivers = append(res.LapsedWaivers, id)
+	}
+	sort.Strings(res.LapsedWaivers)
+}
+
+// Render formats waivers for display, flagging those already expired.
+func (r *WaiverRegistry) Render(waivers []*Waiver) string {
+	var b strings.Builder
+	now := r.now()
+	b.WriteString(fmt.Sprintf("🔕 COMPLIANCE WAIVERS (%d)\n", len(waivers)))
+	b.WriteString("─────────────────────────────────────────\n")
+	if len(waivers) == 0 {
+		b.WriteString("  (none)\n")
+	}
+	for _, w := range waivers {
+		status := fmt.Sprintf("expires in %s", formatDays(w.Expires.Sub(now)))
+		if w.Expired(now) {
+			status = "EXPIRED"
+		}
+		b.WriteString(fmt.Sprintf("  %s  %s [%s]\n", w.ID, w.Check, status))
+		b.WriteString(fmt.Sprintf("      scope: %s | approver: %s | expires: %s\n", w.scope(), w.Approver, policy.FormatExpiry(w.Expires)))
+		if w.Ticket != "" {
+			b.WriteString(fmt.Sprintf("      ticket: %s | justification: %s\n", w.Ticket, w.Justification))
+		} else {
+			b.WriteString(fmt.Sprintf("      justification: %s\n", w.Justification))
+		}
+	}
+	return b.String()
+}
+
+// scope describes where the waiver applies.
+func (w *Waiver) scope() string {
+	var parts []string
+	if w.Framework != "" {
+		parts = append(parts, string(w.Framework))
+	}
+	if len(w.Resources) > 0 {
+		parts = append(parts, strings.Join(w.Resources, ", "))
+	} else {
+		parts = append(parts, "all resources")
+	}
+	if len(w.Environments) > 0 {
+		parts = append(parts, "in "+strings.Join(w.Environments, ", "))
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatDays(d time.Duration) string {
+	days := int(d.Hours() / 24)
+	if days < 1 {
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", days)
+}
+
+// LoadConfig registers waivers from configuration.
+func (r *WaiverRegistry) LoadConfig(waivers []*config.ComplianceWaiver) error {
+	for i, wc := range waivers {
+		if wc == nil {
+			continue
+		}
+		w := &Waiver{
+			ID:            wc.ID,
+			Check:         wc.Check,
+			Framework:     Framework(strings.ToUpper(wc.Framework)),
+			Resources:     wc.Resources,
+			Environments:  wc.Environments,
+			Approver:      wc.Approver,
+			Justification: wc.Justification,
+			Ticket:        wc.Ticket,
+		}
+		if wc.Expires != "" {
+			t, err := policy.ParseExpiry(wc.Expires)
+			if err != nil {
+				return fmt.Errorf("waivers[%d]: %w", i, err)
+			}
+			w.Expires = t
+		}
+		if err := r.Add(w); err != nil {
+			return fmt.Errorf("waivers[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -3,13 +3,18 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
 
 	"github.com/parth14193/ownbot/pkg/core"
+	"github.com/parth14193/ownbot/pkg/environment"
 )
 
 // Config is the top-level InfraCore configuration.
@@ -22,6 +27,13 @@ type Config struct {
 	Notifications *NotificationConfig  `yaml:"notifications,omitempty" json:"notifications,omitempty"`
 	Policies     *PolicyConfig         `yaml:"policies,omitempty" json:"policies,omitempty"`
 	RBAC         *RBACConfig           `yaml:"rbac,omitempty" json:"rbac,omitempty"`
+	EnvironmentTiers []*EnvironmentTierConfig `yaml:"environment_tiers,omitempty" json:"environment_tiers,omitempty"`
+	Calendars        []*CalendarConfig        `yaml:"calendars,omitempty" json:"calendars,omitempty"`
+	BreakGlass       *BreakGlassConfig        `yaml:"break_glass,omitempty" json:"break_glass,omitempty"`
+	HealthProbes     []*ProbeConfig           `yaml:"health_probes,omitempty" json:"health_probes,omitempty"`
+	Gates            []*GateConfig            `yaml:"gates,omitempty" json:"gates,omitempty"`
+	Compliance       *ComplianceConfig        `yaml:"compliance,omitempty" json:"compliance,omitempty"`
+	ResourceGraph    []*ResourceChainConfig   `yaml:"resource_graph,omitempty" json:"resource_graph,omitempty"`
 }
 
 // Profile represents an environment profile (dev, staging, production).
@@ -67,9 +79,61 @@ type ChannelConfig struct {
 
 // PolicyConfig holds policy engine settings.
 type PolicyConfig struct {
-	Enabled         bool     `yaml:"enabled" json:"enabled"`
+	Enabled         *bool    `yaml:"enabled,omitempty" json:"enabled,omitempty"` // default true
 	EnforcementMode string   `yaml:"enforcement_mode" json:"enforcement_mode"` // warn, deny
 	EnabledPolicies []string `yaml:"enabled_policies" json:"enabled_policies"`
+	Directories     []string `yaml:"directories,omitempty" json:"directories,omitempty"` // declarative policy files
+	EnvironmentModes map[string]string          `yaml:"environment_modes,omitempty" json:"environment_modes,omitempty"` // env or tier -> warn, deny
+	Overrides        map[string]*PolicyOverride `yaml:"overrides,omitempty" json:"overrides,omitempty"`                 // policy name -> override
+	Exemptions       []*PolicyExemption         `yaml:"exemptions,omitempty" json:"exemptions,omitempty"`
+	Exceptions       []*PolicyException         `yaml:"exceptions,omitempty" json:"exceptions,omitempty"`
+	DecisionLog      string                     `yaml:"decision_log,omitempty" json:"decision_log,omitempty"` // JSON lines, default ~/.infracore/policy-decisions.jsonl
+	Bundles          []*PolicyBundle            `yaml:"bundles,omitempty" json:"bundles,omitempty"`
+	TrustedKeys      []string                   `yaml:"trusted_keys,omitempty" json:"trusted_keys,omitempty"` // base64 ed25519 public keys
+}
+
+// IsEnabled reports whether policies are evaluated. Policies are enabled
+// unless explicitly disabled.
+func (p *PolicyConfig) IsEnabled() bool {
+	return p.Enabled == nil || *p.Enabled
+}
+
+// PolicyBundle is a signed policy bundle (directory or .tar.gz) to load,
+// optionally pinned to an exact version.
+type PolicyBundle struct {
+	Path    string `yaml:"path" json:"path"`
+	Version string `yaml:"version,omitempty" json:"version,omitempty"`
+}
+
+// PolicyException suppresses one policy for a specific resource until it expires.
+type PolicyException struct {
+	ID           string   `yaml:"id,omitempty" json:"id,omitempty"`
+	Policy       string   `yaml:"policy" json:"policy"`
+	Skill        string   `yaml:"skill,omitempty" json:"skill,omitempty"` // skill name pattern
+	Resource     string   `yaml:"resource" json:"resource"`               // resource identifier, glob allowed
+	Environments []string `yaml:"environments,omitempty" json:"environments,omitempty"`
+	Owner        string   `yaml:"owner" json:"owner"`
+	Reason       string   `yaml:"reason" json:"reason"`
+	Ticket       string   `yaml:"ticket,omitempty" json:"ticket,omitempty"`
+	Expires      string   `yaml:"expires" json:"expires"` // YYYY-MM-DD or RFC 3339
+}
+
+// PolicyExemption downgrades a policy to a warning in the listed environments
+// or tiers (all when empty). A reason is required.
+type PolicyExemption struct {
+	Policy       string   `yaml:"policy" json:"policy"`
+	Environments []string `yaml:"environments,omitempty" json:"environments,omitempty"`
+	Reason       string   `yaml:"reason" json:"reason"`
+}
+
+// PolicyOverride adjusts a single policy. Params configure built-in policies,
+// e.g. tags for require_tags, ports for no_wide_open_sg and threshold for
+// max_blast_radius. Lowering a DENY policy to warn or audit requires a reason.
+type PolicyOverride struct {
+	Enforcement string                 `yaml:"enforcement,omitempty" json:"enforcement,omitempty"` // warn, deny, audit
+	Severity    string                 `yaml:"severity,omitempty" json:"severity,omitempty"`       // INFO, WARNING, CRITICAL
+	Reason      string                 `yaml:"reason,omitempty" json:"reason,omitempty"`
+	Params      map[string]interface{} `yaml:"params,omitempty" json:"params,omitempty"`
 }
 
 // RBACConfig holds access control settings.
@@ -78,6 +142,117 @@ type RBACConfig struct {
 	Users   map[string]string `yaml:"users" json:"users"` // username -> role
 }
 
+// BreakGlassConfig holds emergency override settings.
+type BreakGlassConfig struct {
+	Enabled     bool     `yaml:"enabled" json:"enabled"`
+	Users       []string `yaml:"users" json:"users"`               // users allowed to break glass
+	MaxDuration string   `yaml:"max_duration" json:"max_duration"` // Go duration, e.g. 2h
+	LedgerPath  string   `yaml:"ledger_path,omitempty" json:"ledger_path,omitempty"`
+	KeyFile     string   `yaml:"key_file,omitempty" json:"key_file,omitempty"` // HMAC key for the ledger chain
+}
+
+// ComplianceConfig holds compliance audit settings.
+type ComplianceConfig struct {
+	HistoryPath string                   `yaml:"history_path,omitempty" json:"history_path,omitempty"` // JSON lines, default ~/.infracore/compliance-history.jsonl
+	Scoring     *ComplianceScoringConfig `yaml:"scoring,omitempty" json:"scoring,omitempty"`
+	Frameworks  []string                 `yaml:"frameworks,omitempty" json:"frameworks,omitempty"` // custom framework files or directories
+	Waivers     []*ComplianceWaiver      `yaml:"waivers,omitempty" json:"waivers,omitempty"`
+}
+
+// ComplianceWaiver accepts the risk of a failing compliance check within a
+// scope until it expires.
+type ComplianceWaiver struct {
+	ID            string   `yaml:"id,omitempty" json:"id,omitempty"`
+	Check         string   `yaml:"check" json:"check"`                             // check ID
+	Framework     string   `yaml:"framework,omitempty" json:"framework,omitempty"` // empty matches any
+	Resources     []string `yaml:"resources,omitempty" json:"resources,omitempty"` // glob allowed; empty waives the whole check
+	Environments  []string `yaml:"environments,omitempty" json:"environments,omitempty"`
+	Approver      string   `yaml:"approver" json:"approver"`
+	Justification string   `yaml:"justification" json:"justification"`
+	Ticket        string   `yaml:"ticket,omitempty" json:"ticket,omitempty"`
+	Expires       string   `yaml:"expires" json:"expires"` // YYYY-MM-DD or RFC 3339
+}
+
+// ComplianceScoringConfig overrides the severity weights of compliance
+// scoring and sets the thresholds that fail an audit.
+type ComplianceScoringConfig struct {
+	Weights          map[string]float64 `yaml:"weights,omitempty" json:"weights,omitempty"`         // severity -> weight
+	WarnCredit       *float64           `yaml:"warn_credit,omitempty" json:"warn_credit,omitempty"` // share of a WARN check's weight earned, default 0.5
+	MinScore         float64            `yaml:"min_score,omitempty" json:"min_score,omitempty"`
+	MinCategoryScore float64            `yaml:"min_category_score,omitempty" json:"min_category_score,omitempty"`
+	FailOnCritical   bool               `yaml:"fail_on_critical,omitempty" json:"fail_on_critical,omitempty"`
+}
+
+// ProbeConfig defines a health probe that gates and health checks can use.
+type ProbeConfig struct {
+	Name           string   `yaml:"name" json:"name"`
+	Type           string   `yaml:"type" json:"type"` // http, tcp, dns
+	Target         string   `yaml:"target" json:"target"`
+	Timeout        string   `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	ExpectedStatus int      `yaml:"expected_status,omitempty" json:"expected_status,omitempty"`
+	Tags           []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+}
+
+// GateConfig requires a health probe to be healthy before or after a skill runs.
+type GateConfig struct {
+	Skill         string `yaml:"skill" json:"skill"`
+	Probe         string `yaml:"probe" json:"probe"`
+	Phase         string `yaml:"phase" json:"phase"`                               // pre, post
+	Within        string `yaml:"within,omitempty" json:"within,omitempty"`         // post gates, e.g. 5m
+	Interval      string `yaml:"interval,omitempty" json:"interval,omitempty"`     // post gates polling interval
+	AllowDegraded bool   `yaml:"allow_degraded,omitempty" json:"allow_degraded,omitempty"`
+}
+
+// EnvironmentTierConfig classifies environments by name pattern into a tier
+// with shared escalation rules. Tiers are matched in the order they are listed.
+type EnvironmentTierConfig struct {
+	Name               string   `yaml:"name" json:"name"`
+	Patterns           []string `yaml:"patterns" json:"patterns"` // glob patterns, e.g. prod-*
+	Production         bool     `yaml:"production" json:"production"`
+	EscalationFloor    string   `yaml:"escalation_floor" json:"escalation_floor"` // LOW, MEDIUM, HIGH, CRITICAL
+	ConfirmationPhrase string   `yaml:"confirmation_phrase,omitempty" json:"confirmation_phrase,omitempty"`
+	MandatoryDryRun    bool     `yaml:"mandatory_dry_run" json:"mandatory_dry_run"`
+}
+
+// ResourceChainConfig is a dependency chain in the resource graph: a change
+// to each resource impacts the next, e.g. [ingress/web, service/web,
+// deployment/web]. Resources are written as kind/name.
+type ResourceChainConfig struct {
+	Chain []string `yaml:"chain" json:"chain"`
+}
+
+// CalendarConfig defines a change calendar for a set of environments and teams.
+type CalendarConfig struct {
+	Name          string                     `yaml:"name" json:"name"`
+	Environments  []string                   `yaml:"environments,omitempty" json:"environments,omitempty"` // environment or tier names
+	Teams         []string                   `yaml:"teams,omitempty" json:"teams,omitempty"`
+	RequireWindow bool                       `yaml:"require_window" json:"require_window"`
+	Freezes       []*FreezeConfig            `yaml:"freezes,omitempty" json:"freezes,omitempty"`
+	Windows       []*MaintenanceWindowConfig `yaml:"windows,omitempty" json:"windows,omitempty"`
+	ICalFiles     []string                   `yaml:"ical_files,omitempty" json:"ical_files,omitempty"`
+	ICalAction    string                     `yaml:"ical_action,omitempty" json:"ical_action,omitempty"` // block, escalate
+}
+
+// FreezeConfig defines a named freeze period. Start and End accept RFC 3339
+// timestamps or YYYY-MM-DD dates; a date-only End includes the whole day.
+type FreezeConfig struct {
+	Name     string `yaml:"name" json:"name"`
+	Start    string `yaml:"start" json:"start"`
+	End      string `yaml:"end" json:"end"`
+	TimeZone string `yaml:"time_zone,omitempty" json:"time_zone,omitempty"`
+	Reason   string `yaml:"reason,omitempty" json:"reason,omitempty"`
+	Action   string `yaml:"action,omitempty" json:"action,omitempty"` // block, escalate
+}
+
+// MaintenanceWindowConfig defines a recurring weekly maintenance window.
+type MaintenanceWindowConfig struct {
+	Name     string   `yaml:"name" json:"name"`
+	Days     []string `yaml:"days" json:"days"`         // mon, tue, ...
+	Start    string   `yaml:"start" json:"start"`       // HH:MM
+	Duration string   `yaml:"duration" json:"duration"` // Go duration, e.g. 4h
+	TimeZone string   `yaml:"time_zone,omitempty" json:"time_zone,omitempty"`
+}
+
 // DefaultConfig returns a default configuration.
 func DefaultConfig() *Config {
 	return &Config{
@@ -118,9 +293,11 @@ func DefaultConfig() *Config {
 			},
 		},
 		Policies: &PolicyConfig{
-			Enabled:         true,
 			EnforcementMode: "warn",
-			EnabledPolicies: []string{"no_public_s3", "require_tags", "no_wide_open_sg"},
+			EnabledPolicies: []string{
+				"no_public_s3", "require_tags", "no_wide_open_sg",
+				"k8s_no_privileged", "k8s_require_limits", "k8s_no_latest_tag", "k8s_no_host_path", "k8s_require_probes",
+			},
 		},
 		RBAC: &RBACConfig{
 			Enabled: false,
@@ -145,6 +322,89 @@ func (c *Config) GetCredential(name string) (*Credential, error) {
 	return nil, fmt.Errorf("credential not found: %s", name)
 }
 
+// Load reads a YAML configuration file.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	cfg := &Config{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// LoadOrDefault reads the config file at path, falling back to DefaultConfig
+// when the file does not exist.
+func LoadOrDefault(path string) (*Config, error) {
+	cfg, err := Load(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return DefaultConfig(), nil
+	}
+	return cfg, err
+}
+
+// Classifier builds the environment tier classifier from config. Configured
+// tiers are matched first; the built-in production/staging/dev tiers still
+// apply unless a configured tier reuses their name.
+func (c *Config) Classifier() (*environment.Classifier, error) {
+	if len(c.EnvironmentTiers) == 0 {
+		return environment.DefaultClassifier(), nil
+	}
+
+	tiers := make([]*environment.Tier, 0, len(c.EnvironmentTiers))
+	for _, tc := range c.EnvironmentTiers {
+		floor := core.RiskLow
+		if tc.EscalationFloor != "" {
+			level, err := core.ParseRiskLevel(strings.ToUpper(tc.EscalationFloor))
+			if err != nil {
+				return nil, fmt.Errorf("environment tier '%s': %w", tc.Name, err)
+			}
+			floor = level
+		}
+		tiers = append(tiers, &environment.Tier{
+			Name:               tc.Name,
+			Patterns:           tc.Patterns,
+			Production:         tc.Production,
+			EscalationFloor:    floor,
+			ConfirmationPhrase: tc.ConfirmationPhrase,
+			MandatoryDryRun:    tc.MandatoryDryRun,
+		})
+	}
+	return environment.WithDefaults(tiers...), nil
+}
+
+// SkillGates converts gate configuration into safety gates keyed by skill name.
+func (c *Config) SkillGates() (map[string][]core.SafetyGate, error) {
+	gates := make(map[string][]core.SafetyGate)
+	for i, gc := range c.Gates {
+		gate := core.SafetyGate{Probe: gc.Probe, Phase: core.GatePhase(strings.ToLower(gc.Phase)), AllowDegraded: gc.AllowDegraded}
+		if gate.Phase == "" {
+			gate.Phase = core.GatePre
+		}
+		if gate.Phase != core.GatePre && gate.Phase != core.GatePost {
+			return nil, fmt.Errorf("gates[%d]: unknown phase '%s'", i, gc.Phase)
+		}
+		if gc.Skill == "" || gc.Probe == "" {
+			return nil, fmt.Errorf("gates[%d]: skill and probe are required", i)
+		}
+		var err error
+		if gc.Within != "" {
+			if gate.Within, err = time.ParseDuration(gc.Within); err != nil {
+				return nil, fmt.Errorf("gates[%d]: within: %w", i, err)
+			}
+		}
+		if gc.Interval != "" {
+			if gate.Interval, err = time.ParseDuration(gc.Interval); err != nil {
+				return nil, fmt.Errorf("gates[%d]: interval: %w", i, err)
+			}
+		}
+		gates[gc.Skill] = append(gates[gc.Skill], gate)
+	}
+	return gates, nil
+}
+
 // DefaultConfigPath returns the default config file path.
 func DefaultConfigPath() string {
 	home := homeDir()
@@ -223,6 +483,52 @@ policies:
     - no_wide_open_sg
     - production_deploy_window
     - max_blast_radius
+    - k8s_no_privileged
+    - k8s_no_host_path
+    - k8s_allowed_registries
+  directories:  # declarative YAML policies
+    - ~/.infracore/policies
+  decision_log: ~/.infracore/policy-decisions.jsonl
+  # bundles:  # signed bundles from the platform team
+  #   - path: /etc/infracore/bundles/platform-guardrails.tgz
+  #     version: 1.4.0  # pin; older bundles are always refused
+  # trusted_keys:  # base64 ed25519 public keys (infracore policy bundle keygen)
+  #   - <base64 public key>
+  environment_modes:  # floor per environment or tier; can raise enforcement_mode, never lower it
+    production: deny
+    dev: warn
+  overrides:
+    require_tags:
+      enforcement: deny
+      params:
+        tags: [team, env, service, cost-center]
+    no_wide_open_sg:
+      params:
+        ports: [22, 3389, 3306, 5432, 6379]
+    max_blast_radius:
+      severity: WARNING
+      params:
+        threshold: 100
+    k8s_no_host_path:
+      enforcement: audit  # shadow mode: record would-be blocks, never enforce
+      reason: measuring impact on existing charts before enforcing
+    k8s_allowed_registries:
+      params:
+        registries: [123456789012.dkr.ecr.*.amazonaws.com, ghcr.io/acme]
+  exemptions:  # explicit downgrades to warn
+    - policy: no_public_s3
+      environments: [dev]
+      reason: static site preview buckets
+  exceptions:  # per-resource, owned and expiring
+    - id: EXC-001
+      policy: no_wide_open_sg
+      skill: aws.sg.*
+      resource: sg-bastion-*
+      environments: [production]
+      owner: netops@example.com
+      reason: bastion host accepts SSH from the internet
+      ticket: SEC-1234
+      expires: "2026-12-31"
 
 rbac:
   enabled: true
@@ -230,6 +536,88 @@ rbac:
     admin: superadmin
     deployer: operator
     viewer: viewer
+
+health_probes:
+  - name: api-health
+    type: http
+    target: https://api.example.com/healthz
+    timeout: 5s
+    tags: [api]
+
+gates:
+  - skill: k8s.deploy
+    probe: api-health
+    phase: pre
+  - skill: k8s.deploy
+    probe: api-health
+    phase: post
+    within: 5m
+    interval: 15s
+
+break_glass:
+  enabled: true
+  users: [admin]
+  max_duration: 2h
+  ledger_path: ~/.infracore/breakglass.jsonl
+  key_file: ~/.infracore/breakglass.key  # HMAC key for the ledger; generated if missing, keep it away from the ledger
+
+compliance:
+  history_path: ~/.infracore/compliance-history.jsonl
+  scoring:
+    weights: {CRITICAL: 10, HIGH: 5, MEDIUM: 3, LOW: 1}
+    min_score: 80           # weighted percent; audit exits non-zero below it
+    min_category_score: 60
+    fail_on_critical: true
+  frameworks:               # custom control catalogs (YAML files or directories)
+    - ~/.infracore/frameworks
+  waivers:                  # accepted risk: results become WAIVED until expiry
+    - id: WVR-001
+      check: CIS-3.1
+      framework: CIS
+      resources: ["vpc-0sandbox*"]
+      environments: [sandbox]
+      approver: security-lead
+      justification: Sandbox VPCs carry no customer data
+      ticket: RISK-88
+      expires: "2026-12-31"
+
+calendars:
+  - name: platform-changes
+    environments: [production]
+    require_window: true
+    freezes:
+      - name: year-end
+        start: "2026-12-20"
+        end: "2027-01-02"
+        reason: Year-end change freeze
+        action: block
+    windows:
+      - name: weekend-maintenance
+        days: [sat, sun]
+        start: "02:00"
+        duration: 4h
+        time_zone: Europe/Berlin
+
+# Tiers are matched in order; first match wins. The built-in production,
+# staging and dev tiers are matched after these unless redefined by name.
+environment_tiers:
+  - name: pci
+    patterns: ["pci", "pci-*"]
+    production: true
+    escalation_floor: CRITICAL
+    confirmation_phrase: CONFIRM PCI CHANGE
+    mandatory_dry_run: true
+  - name: production
+    patterns: ["production", "prod", "prd", "prod-*", "live"]
+    production: true
+    escalation_floor: HIGH
+  - name: staging
+    patterns: ["staging", "stage", "stg"]
+    escalation_floor: LOW
+
+resource_graph:  # a change to each resource impacts the next; shown as the impact tree
+  - chain: [ingress/web, service/web, deployment/web]
+  - chain: [vpc/vpc-0a1b2c3d, subnet/subnet-0a1b2c3d, instance/i-0a1b2c3d4e5f]
 `
 }
 
@@ -252,9 +640,120 @@ func (c *Config) Validate() []error {
 		}
 	}
 
+	for i, tier := range c.EnvironmentTiers {
+		if tier.Name == "" {
+			errs = append(errs, fmt.Errorf("environment_tiers[%d]: name is required", i))
+		}
+		if tier.EscalationFloor != "" {
+			if _, err := core.ParseRiskLevel(strings.ToUpper(tier.EscalationFloor)); err != nil {
+				errs = append(errs, fmt.Errorf("environment tier '%s': %w", tier.Name, err))
+			}
+		}
+	}
+
+	if p := c.Policies; p != nil {
+		if !validEnforcement(p.EnforcementMode) {
+			errs = append(errs, fmt.Errorf("policies.enforcement_mode: unknown mode '%s' (want warn or deny)", p.EnforcementMode))
+		}
+		for env, mode := range p.EnvironmentModes {
+			if mode == "" || !validEnforcement(mode) {
+				errs = append(errs, fmt.Errorf("policies.environment_modes.%s: unknown mode '%s' (want warn or deny)", env, mode))
+			}
+		}
+		for i, b := range p.Bundles {
+			if b == nil || b.Path == "" {
+				errs = append(errs, fmt.Errorf("policies.bundles[%d]: path is required", i))
+			}
+		}
+		if len(p.Bundles) > 0 && len(p.TrustedKeys) == 0 {
+			errs = append(errs, fmt.Errorf("policies.trusted_keys: at least one key is required to load bundles"))
+		}
+		for name, o := range p.Overrides {
+			if o == nil {
+				continue
+			}
+			if !validEnforcement(o.Enforcement) && !strings.EqualFold(o.Enforcement, "audit") {
+				errs = append(errs, fmt.Errorf("policies.overrides.%s: unknown enforcement '%s' (want warn, deny or audit)", name, o.Enforcement))
+			}
+			switch strings.ToUpper(o.Severity) {
+			case "", "INFO", "WARNING", "CRITICAL":
+			default:
+				errs = append(errs, fmt.Errorf("policies.overrides.%s: unknown severity '%s' (want INFO, WARNING or CRITICAL)", name, o.Severity))
+			}
+		}
+	}
+
+	if c.BreakGlass != nil && c.BreakGlass.MaxDuration != "" {
+		if _, err := time.ParseDuration(c.BreakGlass.MaxDuration); err != nil {
+			errs = append(errs, fmt.Errorf("break_glass.max_duration: %w", err))
+		}
+	}
+
 	return errs
 }
 
+func validEnforcement(mode string) bool {
+	switch strings.ToLower(mode) {
+	case "", "warn", "deny":
+		return true
+	}
+	return false
+}
+
+// BreakGlassLedgerPath returns the configured break-glass ledger path, or the
+// default under ~/.infracore.
+func (c *Config) BreakGlassLedgerPath() string {
+	if c.BreakGlass != nil && c.BreakGlass.LedgerPath != "" {
+		if strings.HasPrefix(c.BreakGlass.LedgerPath, "~/") {
+			return filepath.Join(homeDir(), c.BreakGlass.LedgerPath[2:])
+		}
+		return c.BreakGlass.LedgerPath
+	}
+	return filepath.Join(homeDir(), ".infracore", "breakglass.jsonl")
+}
+
+// BreakGlassKeyPath returns the configured break-glass ledger key file, or
+// the default under ~/.infracore.
+func (c *Config) BreakGlassKeyPath() string {
+	if c.BreakGlass != nil && c.BreakGlass.KeyFile != "" {
+		if strings.HasPrefix(c.BreakGlass.KeyFile, "~/") {
+			return filepath.Join(homeDir(), c.BreakGlass.KeyFile[2:])
+		}
+		return c.BreakGlass.KeyFile
+	}
+	return filepath.Join(homeDir(), ".infracore", "breakglass.key")
+}
+
+// PolicyDecisionLogPath returns the configured policy decision log path, or
+// the default under ~/.infracore.
+func (c *Config) PolicyDecisionLogPath() string {
+	if c.Policies != nil && c.Policies.DecisionLog != "" {
+		if strings.HasPrefix(c.Policies.DecisionLog, "~/") {
+			return filepath.Join(homeDir(), c.Policies.DecisionLog[2:])
+		}
+		return c.Policies.DecisionLog
+	}
+	return filepath.Join(homeDir(), ".infracore", "policy-decisions.jsonl")
+}
+
+// ComplianceHistoryPath returns the configured compliance history path, or
+// the default under ~/.infracore.
+func (c *Config) ComplianceHistoryPath() string {
+	if c.Compliance != nil && c.Compliance.HistoryPath != "" {
+		if strings.HasPrefix(c.Compliance.HistoryPath, "~/") {
+			return filepath.Join(homeDir(), c.Compliance.HistoryPath[2:])
+		}
+		return c.Compliance.HistoryPath
+	}
+	return filepath.Join(homeDir(), ".infracore", "compliance-history.jsonl")
+}
+
+// PolicyBundleLockPath returns where the newest accepted version of each
+// policy bundle is recorded.
+func (c *Config) PolicyBundleLockPath() string {
+	return filepath.Join(homeDir(), ".infracore", "policy-bundles.lock.json")
+}
+
 // Render returns a human-readable string of the configuration.
 func (c *Config) Render() string {
 	var b strings.Builder
@@ -278,12 +777,30 @@ func (c *Config) Render() string {
 		b.WriteString(fmt.Sprintf("\n🔔 NOTIFICATIONS: enabled=%t (%d channels)\n", c.Notifications.Enabled, len(c.Notifications.Channels)))
 	}
 	if c.Policies != nil {
-		b.WriteString(fmt.Sprintf("🛡️  POLICIES: enabled=%t mode=%s (%d active)\n", c.Policies.Enabled, c.Policies.EnforcementMode, len(c.Policies.EnabledPolicies)))
+		b.WriteString(fmt.Sprintf("🛡️  POLICIES: enabled=%t mode=%s (%d active, %d overrides)\n", c.Policies.IsEnabled(), c.Policies.EnforcementMode, len(c.Policies.EnabledPolicies), len(c.Policies.Overrides)))
 	}
 	if c.RBAC != nil {
 		b.WriteString(fmt.Sprintf("🔐 RBAC: enabled=%t (%d users)\n", c.RBAC.Enabled, len(c.RBAC.Users)))
 	}
 
+	if len(c.Gates) > 0 {
+		b.WriteString(fmt.Sprintf("🚦 GATES: %d configured (%d probes)\n", len(c.Gates), len(c.HealthProbes)))
+	}
+	if c.BreakGlass != nil {
+		b.WriteString(fmt.Sprintf("🔓 BREAK-GLASS: enabled=%t (%d designated users)\n", c.BreakGlass.Enabled, len(c.BreakGlass.Users)))
+	}
+
+	if len(c.Calendars) > 0 {
+		b.WriteString(fmt.Sprintf("📅 CALENDARS: %d configured\n", len(c.Calendars)))
+	}
+
+	if len(c.EnvironmentTiers) > 0 {
+		b.WriteString(fmt.Sprintf("\n🏷️  ENVIRONMENT TIERS (%d):\n", len(c.EnvironmentTiers)))
+		for _, t := range c.EnvironmentTiers {
+			b.WriteString(fmt.Sprintf("  • %s → %s (floor: %s, production=%t)\n", t.Name, strings.Join(t.Patterns, ", "), t.EscalationFloor, t.Production))
+		}
+	}
+
 	return b.String()
 }
 
@@ -3,6 +3,7 @@ package core
 
 import (
 	"fmt"
+	"regexp"
 	"time"
 )
 
@@ -120,10 +121,32 @@ type ExecutionConfig struct {
 	Timeout time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
 }
 
-// RollbackConfig defines how to undo a skill's action.
+// RollbackConfig defines how to undo a skill's action. Procedure is
+// guidance for operators; only Command is ever executed automatically. Its
+// {placeholders} must be listed in Params and are filled from the params of
+// the execution being rolled back.
 type RollbackConfig struct {
-	Supported bool   `json:"supported" yaml:"supported"`
-	Procedure string `json:"procedure" yaml:"procedure"`
+	Supported bool     `json:"supported" yaml:"supported"`
+	Procedure string   `json:"procedure" yaml:"procedure"`
+	Command   string   `json:"command,omitempty" yaml:"command,omitempty"`
+	Params    []string `json:"params,omitempty" yaml:"params,omitempty"`
+}
+
+// placeholderPattern matches {param} placeholders in command templates.
+var placeholderPattern = regexp.MustCompile(`\{([A-Za-z0-9_.-]+)\}`)
+
+// Validate checks that every placeholder in Command is a declared param.
+func (r RollbackConfig) Validate() error {
+	declared := make(map[string]bool, len(r.Params))
+	for _, p := range r.Params {
+		declared[p] = true
+	}
+	for _, m := range placeholderPattern.FindAllStringSubmatch(r.Command, -1) {
+		if !declared[m[1]] {
+			return fmt.Errorf("rollback command placeholder {%s} is not a declared param", m[1])
+		}
+	}
+	return nil
 }
 
 // Skill represents a modular capability unit in the InfraCore framework.
@@ -136,8 +159,29 @@ type Skill struct {
 	Outputs              []SkillOutput   `json:"outputs" yaml:"outputs"`
 	RiskLevel            RiskLevel       `json:"risk_level" yaml:"risk_level"`
 	RequiresConfirmation bool            `json:"requires_confirmation" yaml:"requires_confirmation"`
+	ReadOnly             bool            `json:"read_only,omitempty" yaml:"read_only,omitempty"` // never changes anything; not gated by environment tiers
 	Execution            ExecutionConfig `json:"execution" yaml:"execution"`
 	Rollback             RollbackConfig  `json:"rollback" yaml:"rollback"`
+	Gates                []SafetyGate    `json:"gates,omitempty" yaml:"gates,omitempty"`
+}
+
+// GatePhase determines when a safety gate is evaluated.
+type GatePhase string
+
+const (
+	GatePre  GatePhase = "pre"  // Probe must be healthy before execution
+	GatePost GatePhase = "post" // Probe must become healthy after execution, or roll back
+)
+
+// SafetyGate ties a mutation to a health probe. Pre gates block execution when
+// the probe is not healthy; post gates poll the probe for up to Within after a
+// successful execution and trigger rollback if it never becomes healthy.
+type SafetyGate struct {
+	Probe         string        `json:"probe" yaml:"probe"`
+	Phase         GatePhase     `json:"phase" yaml:"phase"`
+	Within        time.Duration `json:"within,omitempty" yaml:"within,omitempty"`
+	Interval      time.Duration `json:"interval,omitempty" yaml:"interval,omitempty"`
+	AllowDegraded bool          `json:"allow_degraded,omitempty" yaml:"allow_degraded,omitempty"`
 }
 
 // ExecutionStatus represents the outcome status of a skill execution.
@@ -182,6 +226,7 @@ type PlanStep struct {
 	ConditionExpr string                 `json:"condition_expr,omitempty"`
 	OnTrue        *PlanStep              `json:"on_true,omitempty"`
 	OnFalse       *PlanStep              `json:"on_false,omitempty"`
+	Gates         []SafetyGate           `json:"gates,omitempty"`
 }
 
 // Plan represents a multi-step execution plan.
@@ -233,8 +278,21 @@ type SafetyReport struct {
 	AffectedResources   []string  `json:"affected_resources"`
 	RequiresConfirmation bool     `json:"requires_confirmation"`
 	ConfirmationPrompt  string    `json:"confirmation_prompt"`
+	ConfirmationPhrase  string    `json:"confirmation_phrase,omitempty"` // exact text the operator must type
 	RollbackAvailable   bool      `json:"rollback_available"`
 	RollbackProcedure   string    `json:"rollback_procedure"`
 	DryRunRecommended   bool      `json:"dry_run_recommended"`
+	DryRunRequired      bool      `json:"dry_run_required,omitempty"`
 	EnvironmentWarning  string    `json:"environment_warning,omitempty"`
+	FreezeWarning       string    `json:"freeze_warning,omitempty"`
+	DownstreamResources []string      `json:"downstream_resources,omitempty"`
+	ImpactTree          []*ImpactNode `json:"impact_tree,omitempty"`
+}
+
+// ImpactNode is a resource in the downstream impact tree of an action.
+// Children are the resources affected when this resource changes.
+type ImpactNode struct {
+	Kind     string        `json:"kind"`
+	Name     string        `json:"name"`
+	Children []*ImpactNode `json:"children,omitempty"`
 }
@@ -0,0 +1,134 @@
+// Package environment classifies target environments into tiers that drive
+// risk escalation, confirmation phrases and dry-run requirements.
+package environment
+
+import (
+	"path"
+	"strings"
+
+	"github.com/parth14193/ownbot/pkg/core"
+)
+
+// Tier groups environments that share the same safety rules.
+type Tier struct {
+	Name               string         `json:"name"`
+	Patterns           []string       `json:"patterns"` // glob patterns, case-insensitive
+	Production         bool           `json:"production"`
+	EscalationFloor    core.RiskLevel `json:"escalation_floor"`
+	ConfirmationPhrase string         `json:"confirmation_phrase,omitempty"`
+	MandatoryDryRun    bool           `json:"mandatory_dry_run"`
+}
+
+// Matches reports whether env matches the tier name or one of its patterns.
+func (t *Tier) Matches(env string) bool {
+	env = strings.ToLower(env)
+	if strings.EqualFold(t.Name, env) {
+		return true
+	}
+	for _, pattern := range t.Patterns {
+		if ok, _ := path.Match(strings.ToLower(pattern), env); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Classifier maps environment names to tiers. Tiers are matched in order.
+type Classifier struct {
+	tiers []*Tier
+}
+
+// NewClassifier creates a classifier from an ordered list of tiers.
+func NewClassifier(tiers ...*Tier) *Classifier {
+	return &Classifier{tiers: tiers}
+}
+
+// DefaultClassifier returns the built-in tiers: production, staging and dev.
+func DefaultClassifier() *Classifier {
+	return NewClassifier(
+		&Tier{
+			Name:            "production",
+			Patterns:        []string{"production", "prod", "prd"},
+			Production:      true,
+			EscalationFloor: core.RiskHigh,
+		},
+		&Tier{
+			Name:            "staging",
+			Patterns:        []string{"staging", "stage", "stg"},
+			EscalationFloor: core.RiskLow,
+		},
+		&Tier{
+			Name:            "dev",
+			Patterns:        []string{"dev", "development", "local"},
+			EscalationFloor: core.RiskLow,
+		},
+	)
+}
+
+// WithDefaults returns a classifier that matches the given tiers first,
+// followed by the built-in tiers whose names they do not redefine. Custom
+// tiers therefore extend the defaults instead of silently dropping them.
+func WithDefaults(tiers ...*Tier) *Classifier {
+	merged := append([]*Tier(nil), tiers...)
+	for _, d := range DefaultClassifier().tiers {
+		redefined := false
+		for _, t := range tiers {
+			if strings.EqualFold(t.Name, d.Name) {
+				redefined = true
+				break
+			}
+		}
+		if !redefined {
+			merged = append(merged, d)
+		}
+	}
+	return NewClassifier(merged...)
+}
+
+// Classify returns the first tier matching env, or nil if none does.
+func (c *Classifier) Classify(env string) *Tier {
+	if c == nil {
+		return nil
+	}
+	for _, t := range c.tiers {
+		if t.Matches(env) {
+			return t
+		}
+	}
+	return nil
+}
+
+// IsProduction reports whether env belongs to a production tier.
+func (c *Classifier) IsProduction(env string) bool {
+	t := c.Classify(env)
+	return t != nil && t.Production
+}
+
+// EscalationFloor returns the minimum risk level for actions in env.
+func (c *Classifier) EscalationFloor(env string) core.RiskLevel {
+	if t := c.Classify(env); t != nil {
+		return t.EscalationFloor
+	}
+	return core.RiskLow
+}
+
+// MatchesAny reports whether env, or the name of its tier, appears in names.
+// This lets rules written against tier names ("production") cover every
+// environment classified into that tier ("prod-eu", "live").
+func (c *Classifier) MatchesAny(env string, names []string) bool {
+	tier := c.Classify(env)
+	for _, n := range names {
+		if strings.EqualFold(n, env) {
+			return true
+		}
+		if tier != nil && strings.EqualFold(n, tier.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Tiers returns all configured tiers in match order.
+func (c *Classifier) Tiers() []*Tier {
+	return c.tiers
+}
@@ -0,0 +1,66 @@
+package environment_test
+
+import (
+	"testing"
+
+	"github.com/parth14193/ownbot/pkg/core"
+	"github.com/parth14193/ownbot/pkg/environment"
+)
+
+func TestDefaultClassifier(t *testing.T) {
+	c := environment.DefaultClassifier()
+
+	for _, env := range []string{"production", "PROD", "prd"} {
+		if !c.IsProduction(env) {
+			t.Errorf("%s should be production", env)
+		}
+	}
+	for _, env := range []string{"staging", "dev", "unknown"} {
+		if c.IsProduction(env) {
+			t.Errorf("%s should not be production", env)
+		}
+	}
+	if c.Classify("unknown") != nil {
+		t.Error("unmatched environment should have no tier")
+	}
+}
+
+func TestCustomTierPatterns(t *testing.T) {
+	c := environment.NewClassifier(
+		&environment.Tier{Name: "pci", Patterns: []string{"pci", "pci-*"}, Production: true, EscalationFloor: core.RiskCritical},
+		&environment.Tier{Name: "production", Patterns: []string{"prod", "prod-*", "live"}, Production: true, EscalationFloor: core.RiskHigh},
+	)
+
+	if tier := c.Classify("prod-eu"); tier == nil || tier.Name != "production" {
+		t.Errorf("prod-eu should classify as production, got %+v", tier)
+	}
+	if c.EscalationFloor("pci-cardholder") != core.RiskCritical {
+		t.Error("pci-* should escalate to CRITICAL")
+	}
+	if !c.MatchesAny("live", []string{"staging", "production"}) {
+		t.Error("live should match rules written for the production tier")
+	}
+	if c.MatchesAny("qa", []string{"production"}) {
+		t.Error("qa should not match production rules")
+	}
+}
+
+func TestWithDefaults(t *testing.T) {
+	c := environment.WithDefaults(
+		&environment.Tier{Name: "pci", Patterns: []string{"pci-*"}, Production: true, EscalationFloor: core.RiskCritical},
+		&environment.Tier{Name: "staging", Patterns: []string{"qa"}, EscalationFloor: core.RiskMedium},
+	)
+
+	if !c.IsProduction("prd") {
+		t.Error("built-in production tier should still classify prd")
+	}
+	if tier := c.Classify("qa"); tier == nil || tier.Name != "staging" {
+		t.Errorf("qa should classify as the redefined staging tier, got %+v", tier)
+	}
+	if c.Classify("stg") != nil {
+		t.Error("a redefined tier should replace the built-in patterns")
+	}
+	if len(c.Tiers()) != 4 {
+		t.Errorf("expected pci, staging, production and dev, got %d tiers", len(c.Tiers()))
+	}
+}
@@ -5,9 +5,11 @@ package executor
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os/exec"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/parth14193/ownbot/pkg/core"
@@ -24,6 +26,9 @@ type CLIExecutor struct {
 	safetyLayer *safety.Layer
 	dryRun      bool
 	workDir     string
+
+	mu      sync.Mutex
+	dryRuns map[string]bool // invocations dry-run for a mandatory dry-run tier, by dryRunKey
 }
 
 // NewCLIExecutor creates a new CLIExecutor.
@@ -31,6 +36,7 @@ func NewCLIExecutor(safetyLayer *safety.Layer, dryRun bool) *CLIExecutor {
 	return &CLIExecutor{
 		safetyLayer: safetyLayer,
 		dryRun:      dryRun,
+		dryRuns:     make(map[string]bool),
 	}
 }
 
@@ -49,18 +55,25 @@ func (e *CLIExecutor) Execute(ctx context.Context, skill *core.Skill, params map
 	}
 
 	// Safety check
+	dryRun := false
 	if e.safetyLayer != nil {
 		report := e.safetyLayer.Evaluate(skill, params, env)
-		if report.RequiresConfirmation && !e.hasConfirmation(params) {
+		if report.RequiresConfirmation && !e.hasConfirmation(report, params) {
 			result.Status = core.StatusPending
 			result.Message = fmt.Sprintf("Action requires confirmation: %s", report.ConfirmationPrompt)
 			result.Duration = time.Since(start)
 			return result
 		}
+		if report.DryRunRequired && !e.takeDryRun(skill, params, env) {
+			dryRun = true
+		}
 	}
 
 	// Dry run mode
-	if e.dryRun || e.shouldDryRun(skill) {
+	if dryRun || e.dryRun || e.shouldDryRun(skill) {
+		if dryRun {
+			e.recordDryRun(skill, params, env)
+		}
 		result.Status = core.StatusDryRun
 		result.Message = fmt.Sprintf("[DRY RUN] Would execute: %s", e.interpolateCommand(skill.Execution.Command, params))
 		result.Output["command"] = e.interpolateCommand(skill.Execution.Command, params)
@@ -142,11 +155,17 @@ func (e *CLIExecutor) runCommand(ctx context.Context, command string) (string, s
 	return stdout.String(), stderr.String(), exitCode, err
 }
 
-// hasConfirmation checks if the params include a confirmation flag.
-func (e *CLIExecutor) hasConfirmation(params map[string]interface{}) bool {
+// hasConfirmation checks if the params confirm the action. When the
+// environment tier requires a confirmation phrase, the params must carry the
+// typed phrase; otherwise a confirmation flag is enough.
+func (e *CLIExecutor) hasConfirmation(report *core.SafetyReport, params map[string]interface{}) bool {
 	if params == nil {
 		return false
 	}
+	if report.ConfirmationPhrase != "" {
+		typed, ok := params["_confirmation"].(string)
+		return ok && strings.TrimSpace(typed) == report.ConfirmationPhrase
+	}
 	if confirm, ok := params["_confirmed"]; ok {
 		if b, ok := confirm.(bool); ok {
 			return b
@@ -155,6 +174,45 @@ func (e *CLIExecutor) hasConfirmation(params map[string]interface{}) bool {
 	return false
 }
 
+// dryRunKey identifies a skill invocation in an environment for dry-run
+// tracking: the skill, env and the JSON encoding (sorted keys) of its params,
+// leaving out control params such as _confirmation. Keying on params rather
+// than the command matters because most commands have no placeholders.
+func (e *CLIExecutor) dryRunKey(skill *core.Skill, params map[string]interface{}, env string) string {
+	target := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		if !strings.HasPrefix(k, "_") {
+			target[k] = v
+		}
+	}
+	encoded, err := json.Marshal(target)
+	if err != nil {
+		encoded = []byte(fmt.Sprintf("%v", target))
+	}
+	return skill.Name + "\x00" + env + "\x00" + string(encoded)
+}
+
+// recordDryRun marks the invocation as dry-run, as required by environment
+// tiers with mandatory dry-run.
+func (e *CLIExecutor) recordDryRun(skill *core.Skill, params map[string]interface{}, env string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.dryRuns[e.dryRunKey(skill, params, env)] = true
+}
+
+// takeDryRun reports whether this executor dry-ran the same invocation in env
+// and consumes the record, so every real run follows its own dry run.
+func (e *CLIExecutor) takeDryRun(skill *core.Skill, params map[string]interface{}, env string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	key := e.dryRunKey(skill, params, env)
+	if !e.dryRuns[key] {
+		return false
+	}
+	delete(e.dryRuns, key)
+	return true
+}
+
 // shouldDryRun checks if this skill type defaults to dry-run.
 func (e *CLIExecutor) shouldDryRun(skill *core.Skill) bool {
 	return skill.RiskLevel >= core.RiskHigh && !e.hasForce(nil)
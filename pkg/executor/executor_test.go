@@ -0,0 +1,236 @@
+package executor_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/parth14193/ownbot/pkg/core"
+	"github.com/parth14193/ownbot/pkg/environment"
+	"github.com/parth14193/ownbot/pkg/executor"
+	"github.com/parth14193/ownbot/pkg/health"
+	"github.com/parth14193/ownbot/pkg/safety"
+)
+
+// stubExecutor records calls and always succeeds.
+type stubExecutor struct{ calls []string }
+
+func (s *stubExecutor) Execute(_ context.Context, skill *core.Skill, _ map[string]interface{}, _ string) *core.ExecutionResult {
+	s.calls = append(s.calls, skill.Name)
+	return &core.ExecutionResult{SkillName: skill.Name, Status: core.StatusSuccess}
+}
+
+// probeServer returns a checker with an "app" HTTP probe whose health is controlled by healthy.
+func probeServer(t *testing.T, healthy *atomic.Bool) *health.Checker {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if healthy.Load() {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := health.NewChecker()
+	c.AddProbe(&health.Probe{Name: "app", Type: health.ProbeHTTP, Target: srv.URL, Timeout: time.Second})
+	return c
+}
+
+func deploySkill(gates ...core.SafetyGate) *core.Skill {
+	return &core.Skill{
+		Name:      "k8s.deploy",
+		RiskLevel: core.RiskHigh,
+		Rollback: core.RollbackConfig{Supported: true, Procedure: "kubectl rollout undo",
+			Command: "kubectl rollout undo deployment/{deployment}", Params: []string{"deployment"}},
+		Gates:     gates,
+	}
+}
+
+func TestPreGateBlocksExecution(t *testing.T) {
+	var healthy atomic.Bool
+	stub := &stubExecutor{}
+	g := executor.NewGatedExecutor(stub, probeServer(t, &healthy))
+
+	result := g.Execute(context.Background(), deploySkill(core.SafetyGate{Probe: "app", Phase: core.GatePre}), nil, "production")
+	if result.Status != core.StatusCancelled {
+		t.Errorf("expected cancelled, got %s", result.Status)
+	}
+	if len(stub.calls) != 0 {
+		t.Error("primary executor must not run when a pre-gate fails")
+	}
+
+	result = g.Execute(context.Background(), deploySkill(core.SafetyGate{Probe: "missing", Phase: core.GatePre}), nil, "production")
+	if result.Status != core.StatusCancelled || !strings.Contains(result.Message, "probe not found") {
+		t.Errorf("unknown probe should block, got %s: %s", result.Status, result.Message)
+	}
+
+	healthy.Store(true)
+	result = g.Execute(context.Background(), deploySkill(core.SafetyGate{Probe: "app", Phase: core.GatePre}), nil, "production")
+	if result.Status != core.StatusSuccess || len(stub.calls) != 1 {
+		t.Errorf("expected execution once pre-gate passes, got %s", result.Status)
+	}
+}
+
+func TestPostGateRollback(t *testing.T) {
+	var healthy atomic.Bool
+	stub := &stubExecutor{}
+	g := executor.NewGatedExecutor(stub, probeServer(t, &healthy))
+
+	post := core.SafetyGate{Probe: "app", Phase: core.GatePost, Within: 60 * time.Millisecond, Interval: 10 * time.Millisecond}
+	result := g.Execute(context.Background(), deploySkill(post), map[string]interface{}{"deployment": "web"}, "production")
+
+	if result.Status != core.StatusFailed {
+		t.Fatalf("expected failure when post-gate never becomes healthy, got %s", result.Status)
+	}
+	if len(stub.calls) != 2 || stub.calls[1] != "k8s.deploy.rollback" {
+		t.Errorf("expected rollback command to run, calls: %v", stub.calls)
+	}
+	if !strings.Contains(result.Message, "rolled back") {
+		t.Errorf("message should report rollback, got %q", result.Message)
+	}
+}
+
+func TestRollbackRefusesProse(t *testing.T) {
+	var healthy atomic.Bool
+	post := core.SafetyGate{Probe: "app", Phase: core.GatePost, Within: 30 * time.Millisecond, Interval: 10 * time.Millisecond}
+	skill := deploySkill(post)
+	skill.Rollback = core.RollbackConfig{Supported: true, Procedure: "Re-deploy the image that was active before"}
+
+	stub := &stubExecutor{}
+	result := executor.NewGatedExecutor(stub, probeServer(t, &healthy)).Execute(context.Background(), skill, nil, "production")
+	if len(stub.calls) != 1 || !strings.Contains(result.Message, "roll back manually") {
+		t.Errorf("a prose procedure must not be executed, calls %v: %s", stub.calls, result.Message)
+	}
+
+	// A declared param missing from the change refuses the rollback too.
+	stub = &stubExecutor{}
+	result = executor.NewGatedExecutor(stub, probeServer(t, &healthy)).Execute(context.Background(), deploySkill(post), nil, "production")
+	if len(stub.calls) != 1 || !strings.Contains(result.Message, "'deployment' was not set") {
+		t.Errorf("rollback without its params must be refused, calls %v: %s", stub.calls, result.Message)
+	}
+}
+
+func TestRollbackUnderPCITier(t *testing.T) {
+	var healthy atomic.Bool
+	layer := safety.NewLayer()
+	layer.SetClassifier(environment.NewClassifier(&environment.Tier{
+		Name: "pci", Patterns: []string{"pci-*"}, EscalationFloor: core.RiskMedium,
+		ConfirmationPhrase: "CONFIRM PCI CHANGE", MandatoryDryRun: true,
+	}))
+	g := executor.NewGatedExecutor(executor.NewCLIExecutor(layer, false), probeServer(t, &healthy))
+	skill := &core.Skill{Name: "app.deploy", RiskLevel: core.RiskMedium,
+		Execution: core.ExecutionConfig{Type: core.ExecCLI, Command: "echo deployed {deployment}"},
+		Rollback: core.RollbackConfig{Supported: true, Procedure: "Undo the rollout",
+			Command: "echo undone {deployment}", Params: []string{"deployment"}},
+		Gates: []core.SafetyGate{{Probe: "app", Phase: core.GatePost, Within: 30 * time.Millisecond, Interval: 10 * time.Millisecond}},
+	}
+	params := map[string]interface{}{"deployment": "web", "_confirmation": "CONFIRM PCI CHANGE"}
+
+	if r := g.Execute(context.Background(), skill, params, "pci-cardholder"); r.Status != core.StatusDryRun {
+		t.Fatalf("the change itself should dry-run first, got %s", r.Status)
+	}
+	result := g.Execute(context.Background(), skill, params, "pci-cardholder")
+	if result.Status != core.StatusFailed || !strings.Contains(result.Message, "rolled back") {
+		t.Fatalf("expected the failed post-gate to roll back, got %s: %s", result.Status, result.Message)
+	}
+	rb, _ := result.Output["rollback"].(*core.ExecutionResult)
+	if rb == nil || rb.Status != core.StatusSuccess || !strings.Contains(rb.Output["stdout"].(string), "undone web") {
+		t.Fatalf("expected the rollback command to execute, got %+v", rb)
+	}
+	if dry, _ := rb.Output["dry_run"].(*core.ExecutionResult); dry == nil || dry.Status != core.StatusDryRun {
+		t.Errorf("the rollback should follow its own dry run, got %+v", rb.Output["dry_run"])
+	}
+}
+
+func TestPostGateRecovers(t *testing.T) {
+	var healthy atomic.Bool
+	stub := &stubExecutor{}
+	g := executor.NewGatedExecutor(stub, probeServer(t, &healthy))
+	rolledBack := false
+	g.SetRollback(func(context.Context, *core.Skill, map[string]interface{}, string) *core.ExecutionResult {
+		rolledBack = true
+		return &core.ExecutionResult{Status: core.StatusSuccess}
+	})
+
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		healthy.Store(true)
+	}()
+
+	step := &core.PlanStep{StepNumber: 1, SkillName: "k8s.deploy", Gates: []core.SafetyGate{
+		{Probe: "app", Phase: core.GatePost, Within: 2 * time.Second, Interval: 10 * time.Millisecond},
+	}}
+	result := g.ExecuteStep(context.Background(), deploySkill(), step, "production")
+	if result.Status != core.StatusSuccess {
+		t.Errorf("expected success once probe recovers, got %s: %s", result.Status, result.Message)
+	}
+	if rolledBack {
+		t.Error("rollback should not run when the post-gate passes")
+	}
+	gates, _ := result.Output["post_gates"].([]executor.GateResult)
+	if len(gates) != 1 || gates[0].Attempts < 2 {
+		t.Errorf("expected the post-gate to be polled, got %+v", gates)
+	}
+}
+
+func TestTierConfirmationAndDryRun(t *testing.T) {
+	layer := safety.NewLayer()
+	layer.SetClassifier(environment.NewClassifier(&environment.Tier{
+		Name: "pci", Patterns: []string{"pci-*"}, EscalationFloor: core.RiskMedium,
+		ConfirmationPhrase: "CONFIRM PCI CHANGE", MandatoryDryRun: true,
+	}))
+	exec := executor.NewCLIExecutor(layer, false)
+	skill := &core.Skill{Name: "k8s.scale", RiskLevel: core.RiskMedium,
+		Execution: core.ExecutionConfig{Type: core.ExecCLI, Command: "echo scaled {replicas}"}}
+
+	run := func(params map[string]interface{}) *core.ExecutionResult {
+		return exec.Execute(context.Background(), skill, params, "pci-cardholder")
+	}
+	if r := run(map[string]interface{}{"replicas": 3, "_confirmed": true}); r.Status != core.StatusPending {
+		t.Errorf("a confirmation flag should not satisfy a tier phrase, got %s", r.Status)
+	}
+
+	params := map[string]interface{}{"replicas": 3, "_confirmation": "CONFIRM PCI CHANGE", "_dry_run_completed": true}
+	if r := run(params); r.Status != core.StatusDryRun {
+		t.Errorf("first run should be a dry run regardless of params, got %s", r.Status)
+	}
+	if r := run(map[string]interface{}{"replicas": 5, "_confirmation": "CONFIRM PCI CHANGE"}); r.Status != core.StatusDryRun {
+		t.Errorf("a different command needs its own dry run, got %s", r.Status)
+	}
+	if r := run(params); r.Status != core.StatusSuccess {
+		t.Errorf("expected execution after the dry run, got %s: %s", r.Status, r.Message)
+	}
+	if r := run(params); r.Status != core.StatusDryRun {
+		t.Errorf("each execution should follow its own dry run, got %s", r.Status)
+	}
+}
+
+func TestDryRunIsPerTarget(t *testing.T) {
+	layer := safety.NewLayer()
+	layer.SetClassifier(environment.NewClassifier(&environment.Tier{
+		Name: "pci", Patterns: []string{"pci-*"}, EscalationFloor: core.RiskMedium,
+		ConfirmationPhrase: "CONFIRM PCI CHANGE", MandatoryDryRun: true,
+	}))
+	exec := executor.NewCLIExecutor(layer, false)
+	// No placeholders: the command is the same for every bucket.
+	skill := &core.Skill{Name: "aws.s3.update_encryption", RiskLevel: core.RiskMedium,
+		Execution: core.ExecutionConfig{Type: core.ExecCLI, Command: "echo put-bucket-encryption"}}
+
+	run := func(bucket string) *core.ExecutionResult {
+		return exec.Execute(context.Background(), skill, map[string]interface{}{"bucket": bucket, "_confirmation": "CONFIRM PCI CHANGE"}, "pci-cardholder")
+	}
+	if r := run("bucket-a"); r.Status != core.StatusDryRun {
+		t.Fatalf("first run should be a dry run, got %s", r.Status)
+	}
+	if r := run("bucket-b"); r.Status != core.StatusDryRun {
+		t.Errorf("a dry run of bucket-a must not unlock bucket-b, got %s", r.Status)
+	}
+	if r := run("bucket-a"); r.Status != core.StatusSuccess {
+		t.Errorf("bucket-a should run after its dry run, got %s: %s", r.Status, r.Message)
+	}
+}
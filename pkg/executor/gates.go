@@ -0,0 +1,256 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/parth14193/ownbot/pkg/core"
+	"github.com/parth14193/ownbot/pkg/health"
+)
+
+// Default post-gate timings when a gate does not set them.
+const (
+	DefaultGateWithin   = 5 * time.Minute
+	DefaultGateInterval = 10 * time.Second
+)
+
+// GateResult is the outcome of evaluating a single safety gate.
+type GateResult struct {
+	Gate     core.SafetyGate    `json:"gate"`
+	Passed   bool               `json:"passed"`
+	Status   health.ProbeStatus `json:"status"`
+	Attempts int                `json:"attempts"`
+	Message  string             `json:"message"`
+}
+
+// RollbackFunc reverts a skill execution whose post gates failed.
+type RollbackFunc func(ctx context.Context, skill *core.Skill, params map[string]interface{}, env string) *core.ExecutionResult
+
+// GatedExecutor wraps an executor with pre- and post-condition health gates.
+// Pre gates must pass before the primary executor runs; post gates must pass
+// within their deadline after a successful run, otherwise the change is rolled back.
+type GatedExecutor struct {
+	primary  Executor
+	checker  *health.Checker
+	rollback RollbackFunc
+}
+
+// NewGatedExecutor creates a gated executor using checker's probes. By default,
+// rollback runs the skill's rollback command through the primary executor.
+func NewGatedExecutor(primary Executor, checker *health.Checker) *GatedExecutor {
+	g := &GatedExecutor{primary: primary, checker: checker}
+	g.rollback = g.commandRollback
+	return g
+}
+
+// SetRollback replaces the rollback handler invoked when post gates fail.
+func (g *GatedExecutor) SetRollback(fn RollbackFunc) {
+	g.rollback = fn
+}
+
+// Execute runs the skill guarded by the skill's own gates.
+func (g *GatedExecutor) Execute(ctx context.Context, skill *core.Skill, params map[string]interface{}, env string) *core.ExecutionResult {
+	return g.execute(ctx, skill, skill.Gates, params, env)
+}
+
+// ExecuteStep runs a plan step guarded by both the skill's gates and the step's gates.
+func (g *GatedExecutor) ExecuteStep(ctx context.Context, skill *core.Skill, step *core.PlanStep, env string) *core.ExecutionResult {
+	gates := append(append([]core.SafetyGate{}, skill.Gates...), step.Gates...)
+	return g.execute(ctx, skill, gates, step.Params, env)
+}
+
+func (g *GatedExecutor) execute(ctx context.Context, skill *core.Skill, gates []core.SafetyGate, params map[string]interface{}, env string) *core.ExecutionResult {
+	start := time.Now()
+
+	pre := g.CheckGates(ctx, filterGates(gates, core.GatePre))
+	if failed := firstFailed(pre); failed != nil {
+		return &core.ExecutionResult{
+			SkillName: skill.Name,
+			Status:    core.StatusCancelled,
+			Message:   fmt.Sprintf("Blocked by pre-gate '%s': %s", failed.Gate.Probe, failed.Message),
+			Error:     "pre-gate failed",
+			Output:    map[string]interface{}{"pre_gates": pre},
+			Duration:  time.Since(start),
+			Timestamp: start,
+		}
+	}
+
+	result := g.primary.Execute(ctx, skill, params, env)
+	if result.Output == nil {
+		result.Output = make(map[string]interface{})
+	}
+	if len(pre) > 0 {
+		result.Output["pre_gates"] = pre
+	}
+	if result.Status != core.StatusSuccess {
+		return result
+	}
+
+	post := g.CheckGates(ctx, filterGates(gates, core.GatePost))
+	if len(post) > 0 {
+		result.Output["post_gates"] = post
+	}
+	failed := firstFailed(post)
+	if failed == nil {
+		return result
+	}
+
+	result.Status = core.StatusFailed
+	result.Error = "post-gate failed"
+	result.Message = fmt.Sprintf("Post-gate '%s' not healthy within %s: %s", failed.Gate.Probe, withinOf(failed.Gate), failed.Message)
+	if g.rollback == nil {
+		result.Message += " — no rollback configured"
+		return result
+	}
+	rb := g.rollback(ctx, skill, params, env)
+	result.Output["rollback"] = rb
+	if rb.Status == core.StatusSuccess {
+		result.Message += " — rolled back"
+	} else {
+		result.Message += fmt.Sprintf(" — rollback %s: %s", rb.Status, rb.Message)
+	}
+	result.Duration = time.Since(start)
+	return result
+}
+
+// CheckGates evaluates gates in order. Post gates are polled until healthy or
+// their deadline passes; pre gates are checked once.
+func (g *GatedExecutor) CheckGates(ctx context.Context, gates []core.SafetyGate) []GateResult {
+	results := make([]GateResult, 0, len(gates))
+	for _, gate := range gates {
+		results = append(results, g.checkGate(ctx, gate))
+	}
+	return results
+}
+
+func (g *GatedExecutor) checkGate(ctx context.Context, gate core.SafetyGate) GateResult {
+	res := GateResult{Gate: gate, Status: health.StatusUnknown}
+	if g.checker == nil || !g.checker.HasProbe(gate.Probe) {
+		res.Message = fmt.Sprintf("probe not found: %s", gate.Probe)
+		return res
+	}
+
+	deadline := time.Now()
+	interval := gate.Interval
+	if gate.Phase == core.GatePost {
+		deadline = deadline.Add(withinOf(gate))
+		if interval <= 0 {
+			interval = DefaultGateInterval
+		}
+	}
+
+	for {
+		pr, _ := g.checker.RunProbe(gate.Probe)
+		res.Attempts++
+		res.Status = pr.Status
+		res.Message = pr.Message
+		if pr.Error != "" {
+			res.Message = pr.Error
+		}
+		if pr.Status == health.StatusHealthy || (gate.AllowDegraded && pr.Status == health.StatusDegraded) {
+			res.Passed = true
+			return res
+		}
+		if !time.Now().Add(interval).Before(deadline) {
+			return res
+		}
+		select {
+		case <-ctx.Done():
+			res.Message = ctx.Err().Error()
+			return res
+		case <-time.After(interval):
+		}
+	}
+}
+
+// commandRollback runs the skill's structured rollback command through the
+// primary executor. Free-text procedures are never executed. The rollback
+// carries the control params of the change it reverts (the tier's typed
+// confirmation, _confirmed, _force), and when the tier makes the rollback a
+// dry run it is run once more after that dry run, as for any other change.
+func (g *GatedExecutor) commandRollback(ctx context.Context, skill *core.Skill, params map[string]interface{}, env string) *core.ExecutionResult {
+	refuse := func(msg string) *core.ExecutionResult {
+		return &core.ExecutionResult{
+			SkillName: skill.Name + ".rollback",
+			Status:    core.StatusFailed,
+			Message:   msg,
+			Timestamp: time.Now(),
+		}
+	}
+	rb := skill.Rollback
+	if !rb.Supported || strings.TrimSpace(rb.Command) == "" {
+		if strings.TrimSpace(rb.Procedure) != "" {
+			return refuse("skill has no rollback command; roll back manually: " + rb.Procedure)
+		}
+		return refuse("skill does not support automatic rollback")
+	}
+	if err := rb.Validate(); err != nil {
+		return refuse(err.Error())
+	}
+
+	rbParams := make(map[string]interface{}, len(rb.Params)+4)
+	for _, name := range rb.Params {
+		v, ok := params[name]
+		if !ok || fmt.Sprintf("%v", v) == "" {
+			return refuse(fmt.Sprintf("rollback param '%s' was not set on the change", name))
+		}
+		rbParams[name] = v
+	}
+	for k, v := range params {
+		if strings.HasPrefix(k, "_") {
+			rbParams[k] = v
+		}
+	}
+	rbParams["_rollback"] = true
+
+	rollbackSkill := &core.Skill{
+		Name:      skill.Name + ".rollback",
+		Provider:  skill.Provider,
+		Category:  skill.Category,
+		RiskLevel: skill.RiskLevel,
+		Execution: core.ExecutionConfig{
+			Type:    core.ExecCLI,
+			Command: rb.Command,
+			Timeout: skill.Execution.Timeout,
+		},
+	}
+	result := g.primary.Execute(ctx, rollbackSkill, rbParams, env)
+	if result.Status != core.StatusDryRun {
+		return result
+	}
+	dryRun := result
+	result = g.primary.Execute(ctx, rollbackSkill, rbParams, env)
+	if result.Output == nil {
+		result.Output = make(map[string]interface{})
+	}
+	result.Output["dry_run"] = dryRun
+	return result
+}
+
+func filterGates(gates []core.SafetyGate, phase core.GatePhase) []core.SafetyGate {
+	var out []core.SafetyGate
+	for _, gate := range gates {
+		if gate.Phase == phase || (gate.Phase == "" && phase == core.GatePre) {
+			out = append(out, gate)
+		}
+	}
+	return out
+}
+
+func firstFailed(results []GateResult) *GateResult {
+	for i := range results {
+		if !results[i].Passed {
+			return &results[i]
+		}
+	}
+	return nil
+}
+
+func withinOf(gate core.SafetyGate) time.Duration {
+	if gate.Within > 0 {
+		return gate.Within
+	}
+	return DefaultGateWithin
+}
@@ -0,0 +1,375 @@
+package expr
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type node interface {
+	eval(env map[string]interface{}) (interface{}, error)
+}
+
+type literalNode struct{ val interface{} }
+
+func (n *literalNode) eval(map[string]interface{}) (interface{}, error) { return n.val, nil }
+
+type listNode struct{ items []node }
+
+func (n *listNode) eval(env map[string]interface{}) (interface{}, error) {
+	out := make([]interface{}, 0, len(n.items))
+	for _, item := range n.items {
+		v, err := item.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+type pathSegment struct {
+	key   string
+	index node
+}
+
+type pathNode struct{ segments []pathSegment }
+
+func (n *pathNode) eval(env map[string]interface{}) (interface{}, error) {
+	var cur interface{} = env
+	for _, seg := range n.segments {
+		key := interface{}(seg.key)
+		if seg.index != nil {
+			v, err := seg.index.eval(env)
+			if err != nil {
+				return nil, err
+			}
+			key = v
+		}
+		cur = lookup(cur, key)
+		if cur == nil {
+			return nil, nil
+		}
+	}
+	return cur, nil
+}
+
+// lookup indexes maps by key and slices by numeric index, returning nil when absent.
+func lookup(v interface{}, key interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		mv := rv.MapIndex(reflect.ValueOf(format(key)).Convert(rv.Type().Key()))
+		if !mv.IsValid() {
+			return nil
+		}
+		return mv.Interface()
+	case reflect.Slice, reflect.Array:
+		f, ok := toNumber(key)
+		if !ok {
+			return nil
+		}
+		i := int(f)
+		if i < 0 || i >= rv.Len() {
+			return nil
+		}
+		return rv.Index(i).Interface()
+	}
+	return nil
+}
+
+type notNode struct{ operand node }
+
+func (n *notNode) eval(env map[string]interface{}) (interface{}, error) {
+	v, err := n.operand.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	return !Truthy(v), nil
+}
+
+type logicalNode struct {
+	op          string
+	left, right node
+}
+
+func (n *logicalNode) eval(env map[string]interface{}) (interface{}, error) {
+	l, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "&&" && !Truthy(l) {
+		return false, nil
+	}
+	if n.op == "||" && Truthy(l) {
+		return true, nil
+	}
+	r, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	return Truthy(r), nil
+}
+
+type compareNode struct {
+	op          string
+	left, right node
+}
+
+func (n *compareNode) eval(env map[string]interface{}) (interface{}, error) {
+	l, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	r, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "==":
+		return Equal(l, r), nil
+	case "!=":
+		return !Equal(l, r), nil
+	}
+	if l == nil || r == nil {
+		return false, nil
+	}
+	lf, lok := toNumber(l)
+	rf, rok := toNumber(r)
+	var cmp int
+	switch {
+	case lok && rok:
+		cmp = compareFloat(lf, rf)
+	default:
+		cmp = strings.Compare(format(l), format(r))
+	}
+	switch n.op {
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	case ">=":
+		return cmp >= 0, nil
+	}
+	return nil, fmt.Errorf("unknown operator '%s'", n.op)
+}
+
+type inNode struct{ left, right node }
+
+func (n *inNode) eval(env map[string]interface{}) (interface{}, error) {
+	l, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	r, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	return contains(r, l), nil
+}
+
+type callNode struct {
+	name string
+	fn   func(args []interface{}) (interface{}, error)
+	args []node
+}
+
+func (n *callNode) eval(env map[string]interface{}) (interface{}, error) {
+	args := make([]interface{}, 0, len(n.args))
+	for _, a := range n.args {
+		v, err := a.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, v)
+	}
+	v, err := n.fn(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", n.name, err)
+	}
+	return v, nil
+}
+
+// functions are the built-ins callable from expressions.
+var functions = map[string]func(args []interface{}) (interface{}, error){
+	"len": func(args []interface{}) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("expected 1 argument")
+		}
+		if args[0] == nil {
+			return float64(0), nil
+		}
+		rv := reflect.ValueOf(args[0])
+		switch rv.Kind() {
+		case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+			return float64(rv.Len()), nil
+		}
+		return nil, fmt.Errorf("unsupported type %T", args[0])
+	},
+	"contains": func(args []interface{}) (interface{}, error) {
+		if len(args) != 2 {
+			return nil, fmt.Errorf("expected 2 arguments")
+		}
+		if s, ok := args[0].(string); ok {
+			return strings.Contains(s, format(args[1])), nil
+		}
+		return contains(args[0], args[1]), nil
+	},
+	"has": func(args []interface{}) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("expected 1 argument")
+		}
+		return args[0] != nil, nil
+	},
+	"lower": stringFunc(strings.ToLower),
+	"upper": stringFunc(strings.ToUpper),
+	"startswith": func(args []interface{}) (interface{}, error) {
+		if len(args) != 2 {
+			return nil, fmt.Errorf("expected 2 arguments")
+		}
+		return strings.HasPrefix(format(args[0]), format(args[1])), nil
+	},
+	"endswith": func(args []interface{}) (interface{}, error) {
+		if len(args) != 2 {
+			return nil, fmt.Errorf("expected 2 arguments")
+		}
+		return strings.HasSuffix(format(args[0]), format(args[1])), nil
+	},
+	"matches": func(args []interface{}) (interface{}, error) {
+		if len(args) != 2 {
+			return nil, fmt.Errorf("expected 2 arguments")
+		}
+		re, err := regexp.Compile(format(args[1]))
+		if err != nil {
+			return nil, err
+		}
+		return re.MatchString(format(args[0])), nil
+	},
+}
+
+func stringFunc(f func(string) string) func(args []interface{}) (interface{}, error) {
+	return func(args []interface{}) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("expected 1 argument")
+		}
+		return f(format(args[0])), nil
+	}
+}
+
+// Truthy reports whether v counts as true: non-nil, non-zero, non-empty.
+func Truthy(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return t
+	case string:
+		return t != "" && !strings.EqualFold(t, "false")
+	}
+	if f, ok := toNumber(v); ok {
+		return f != 0
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return rv.Len() > 0
+	}
+	return true
+}
+
+// Equal compares values loosely: numbers compare numerically (including
+// numeric strings, since CLI parameters arrive as strings), booleans compare
+// against "true"/"false" strings, and everything else compares by string form.
+func Equal(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if af, ok := toNumber(a); ok {
+		if bf, ok := toNumber(b); ok {
+			return af == bf
+		}
+	}
+	if ab, ok := a.(bool); ok {
+		return strconv.FormatBool(ab) == strings.ToLower(format(b))
+	}
+	if bb, ok := b.(bool); ok {
+		return strconv.FormatBool(bb) == strings.ToLower(format(a))
+	}
+	return format(a) == format(b)
+}
+
+func contains(collection, item interface{}) bool {
+	if collection == nil {
+		return false
+	}
+	if s, ok := collection.(string); ok {
+		return strings.Contains(s, format(item))
+	}
+	rv := reflect.ValueOf(collection)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if Equal(rv.Index(i).Interface(), item) {
+				return true
+			}
+		}
+	case reflect.Map:
+		return lookup(collection, item) != nil
+	}
+	return false
+}
+
+func toNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func format(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,446 @@
+// Package expr implements a small, side-effect free expression language used
+// by declarative policies and checks, e.g.
+//
+//	params.cidr == "0.0.0.0/0" && params.port in [22, 3389]
+//
+// Expressions support boolean operators (&&, ||, !), comparisons (==, !=, <,
+// <=, >, >=), membership (in, not in), list literals, dotted and indexed
+// paths into the evaluation environment, and a few built-in functions.
+// Paths that do not exist evaluate to null rather than failing.
+package expr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SyntaxError reports a parse failure at a byte offset within the source.
+type SyntaxError struct {
+	Offset int    // 0-based byte offset
+	Column int    // 1-based column
+	Msg    string // description
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Column, e.Msg)
+}
+
+// Expr is a compiled expression.
+type Expr struct {
+	src  string
+	root node
+}
+
+// Parse compiles an expression.
+func Parse(src string) (*Expr, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{src: src, toks: toks}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.errorf(t, "unexpected %s", t)
+	}
+	return &Expr{src: src, root: root}, nil
+}
+
+// MustParse is like Parse but panics on error. It is intended for built-in
+// expressions known to be valid.
+func MustParse(src string) *Expr {
+	e, err := Parse(src)
+	if err != nil {
+		panic(fmt.Sprintf("expr: %q: %v", src, err))
+	}
+	return e
+}
+
+// String returns the expression source.
+func (e *Expr) String() string { return e.src }
+
+// Eval evaluates the expression against env, whose keys are the top-level
+// identifiers available to paths.
+func (e *Expr) Eval(env map[string]interface{}) (interface{}, error) {
+	return e.root.eval(env)
+}
+
+// EvalBool evaluates the expression and reports its truthiness.
+func (e *Expr) EvalBool(env map[string]interface{}) (bool, error) {
+	v, err := e.Eval(env)
+	if err != nil {
+		return false, err
+	}
+	return Truthy(v), nil
+}
+
+// Interpolate replaces each {{ expression }} in template with its value
+// evaluated against env. Invalid expressions are left untouched.
+func Interpolate(template string, env map[string]interface{}) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(template, "{{")
+		if start < 0 {
+			b.WriteString(template)
+			return b.String()
+		}
+		end := strings.Index(template[start:], "}}")
+		if end < 0 {
+			b.WriteString(template)
+			return b.String()
+		}
+		end += start
+		b.WriteString(template[:start])
+		raw := template[start : end+2]
+		if e, err := Parse(strings.TrimSpace(template[start+2 : end])); err == nil {
+			if v, err := e.Eval(env); err == nil {
+				raw = format(v)
+			}
+		}
+		b.WriteString(raw)
+		template = template[end+2:]
+	}
+}
+
+// ── Lexer ──────────────────────────────────────────────────────
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokOp
+	tokPunct
+)
+
+type token struct {
+	kind tokKind
+	text string
+	val  interface{}
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokString:
+		return fmt.Sprintf("string %q", t.val)
+	default:
+		return fmt.Sprintf("'%s'", t.text)
+	}
+}
+
+func lex(src string) ([]token, error) {
+	var toks []token
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case isIdentStart(c):
+			start := i
+			for i < len(src) && isIdentPart(src[i]) {
+				i++
+			}
+			toks = append(toks, token{kind: tokIdent, text: src[start:i], pos: start})
+		case isDigit(c) || (c == '-' && i+1 < len(src) && isDigit(src[i+1]) && negativeAllowed(toks)):
+			start := i
+			i++
+			for i < len(src) && (isDigit(src[i]) || src[i] == '.') {
+				i++
+			}
+			var f float64
+			if _, err := fmt.Sscanf(src[start:i], "%g", &f); err != nil {
+				return nil, &SyntaxError{Offset: start, Column: start + 1, Msg: fmt.Sprintf("invalid number %q", src[start:i])}
+			}
+			toks = append(toks, token{kind: tokNumber, text: src[start:i], val: f, pos: start})
+		case c == '"' || c == '\'':
+			start := i
+			var sb strings.Builder
+			i++
+			for {
+				if i >= len(src) {
+					return nil, &SyntaxError{Offset: start, Column: start + 1, Msg: "unterminated string"}
+				}
+				if src[i] == '\\' && i+1 < len(src) {
+					sb.WriteByte(src[i+1])
+					i += 2
+					continue
+				}
+				if src[i] == c {
+					i++
+					break
+				}
+				sb.WriteByte(src[i])
+				i++
+			}
+			toks = append(toks, token{kind: tokString, text: src[start:i], val: sb.String(), pos: start})
+		default:
+			two := ""
+			if i+1 < len(src) {
+				two = src[i : i+2]
+			}
+			switch two {
+			case "&&", "||", "==", "!=", "<=", ">=":
+				toks = append(toks, token{kind: tokOp, text: two, pos: i})
+				i += 2
+				continue
+			}
+			switch c {
+			case '<', '>', '!':
+				toks = append(toks, token{kind: tokOp, text: string(c), pos: i})
+			case '(', ')', '[', ']', ',', '.':
+				toks = append(toks, token{kind: tokPunct, text: string(c), pos: i})
+			default:
+				return nil, &SyntaxError{Offset: i, Column: i + 1, Msg: fmt.Sprintf("unexpected character %q", c)}
+			}
+			i++
+		}
+	}
+	toks = append(toks, token{kind: tokEOF, pos: len(src)})
+	return toks, nil
+}
+
+// negativeAllowed reports whether a '-' at this point starts a number literal
+// (i.e. it does not follow a value).
+func negativeAllowed(toks []token) bool {
+	if len(toks) == 0 {
+		return true
+	}
+	last := toks[len(toks)-1]
+	return last.kind == tokOp || (last.kind == tokPunct && last.text != ")" && last.text != "]") ||
+		(last.kind == tokIdent && (last.text == "in" || last.text == "not"))
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool { return isIdentStart(c) || isDigit(c) || c == '-' }
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+// ── Parser ─────────────────────────────────────────────────────
+
+type parser struct {
+	src  string
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() token { return p.toks[p.pos] }
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) errorf(t token, format string, args ...interface{}) error {
+	return &SyntaxError{Offset: t.pos, Column: t.pos + 1, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) expect(kind tokKind, text string) (token, error) {
+	t := p.next()
+	if t.kind != kind || t.text != text {
+		return t, p.errorf(t, "expected '%s', found %s", text, t)
+	}
+	return t, nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOp && p.peek().text == "||" {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOp && p.peek().text == "&&" {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if t := p.peek(); t.kind == tokOp && t.text == "!" {
+		p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	switch {
+	case t.kind == tokOp && t.text != "&&" && t.text != "||" && t.text != "!":
+		p.next()
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		return &compareNode{op: t.text, left: left, right: right}, nil
+	case t.kind == tokIdent && t.text == "in":
+		p.next()
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		return &inNode{left: left, right: right}, nil
+	case t.kind == tokIdent && t.text == "not" && p.toks[p.pos+1].kind == tokIdent && p.toks[p.pos+1].text == "in":
+		p.next()
+		p.next()
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: &inNode{left: left, right: right}}, nil
+	}
+	return left, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber, tokString:
+		return &literalNode{val: t.val}, nil
+	case tokPunct:
+		switch t.text {
+		case "(":
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expect(tokPunct, ")"); err != nil {
+				return nil, err
+			}
+			return inner, nil
+		case "[":
+			list := &listNode{}
+			if p.peek().kind == tokPunct && p.peek().text == "]" {
+				p.next()
+				return list, nil
+			}
+			for {
+				item, err := p.parseOr()
+				if err != nil {
+					return nil, err
+				}
+				list.items = append(list.items, item)
+				sep := p.next()
+				if sep.kind == tokPunct && sep.text == "]" {
+					return list, nil
+				}
+				if sep.kind != tokPunct || sep.text != "," {
+					return nil, p.errorf(sep, "expected ',' or ']' in list, found %s", sep)
+				}
+			}
+		}
+	case tokIdent:
+		switch t.text {
+		case "true":
+			return &literalNode{val: true}, nil
+		case "false":
+			return &literalNode{val: false}, nil
+		case "null", "nil":
+			return &literalNode{val: nil}, nil
+		}
+		if p.peek().kind == tokPunct && p.peek().text == "(" {
+			return p.parseCall(t)
+		}
+		return p.parsePath(t)
+	}
+	return nil, p.errorf(t, "unexpected %s", t)
+}
+
+func (p *parser) parseCall(name token) (node, error) {
+	fn, ok := functions[name.text]
+	if !ok {
+		return nil, p.errorf(name, "unknown function '%s'", name.text)
+	}
+	p.next() // (
+	call := &callNode{name: name.text, fn: fn}
+	if p.peek().kind == tokPunct && p.peek().text == ")" {
+		p.next()
+		return call, nil
+	}
+	for {
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		call.args = append(call.args, arg)
+		sep := p.next()
+		if sep.kind == tokPunct && sep.text == ")" {
+			return call, nil
+		}
+		if sep.kind != tokPunct || sep.text != "," {
+			return nil, p.errorf(sep, "expected ',' or ')' in call to %s, found %s", name.text, sep)
+		}
+	}
+}
+
+func (p *parser) parsePath(first token) (node, error) {
+	path := &pathNode{segments: []pathSegment{{key: first.text}}}
+	for {
+		t := p.peek()
+		if t.kind != tokPunct {
+			return path, nil
+		}
+		switch t.text {
+		case ".":
+			p.next()
+			seg := p.next()
+			if seg.kind != tokIdent && seg.kind != tokNumber {
+				return nil, p.errorf(seg, "expected field name after '.', found %s", seg)
+			}
+			path.segments = append(path.segments, pathSegment{key: seg.text})
+		case "[":
+			p.next()
+			index, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expect(tokPunct, "]"); err != nil {
+				return nil, err
+			}
+			path.segments = append(path.segments, pathSegment{index: index})
+		default:
+			return path, nil
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package expr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/parth14193/ownbot/pkg/expr"
+)
+
+func TestEval(t *testing.T) {
+	env := map[string]interface{}{
+		"env": "production",
+		"params": map[string]interface{}{
+			"cidr": "0.0.0.0/0",
+			"port": "22",
+			"tags": map[string]interface{}{"team": "platform"},
+			"ids":  []interface{}{"a", "b"},
+		},
+		"skill": map[string]interface{}{"name": "aws.sg.modify", "risk_level": "HIGH"},
+	}
+
+	cases := []struct {
+		src  string
+		want bool
+	}{
+		{`params.cidr == "0.0.0.0/0" && params.port in [22, 3389]`, true},
+		{`params.port in [80, 443]`, false},
+		{`params.port not in [80, 443]`, true},
+		{`params.port >= 20 && params.port < 23`, true},
+		{`!(env == "production")`, false},
+		{`env == 'staging' || skill.risk_level == "HIGH"`, true},
+		{`params.missing.deeper == null`, true},
+		{`params.missing == "x"`, false},
+		{`has(params.tags.team) && !has(params.tags.service)`, true},
+		{`params.ids[1] == "b" && len(params.ids) == 2`, true},
+		{`"team" in params.tags`, true},
+		{`startswith(skill.name, "aws.") && matches(params.cidr, "^0\\.0\\.0\\.0")`, true},
+		{`contains(params.cidr, "/0")`, true},
+		{`params.tags.team`, true},
+		{`-1 < 0`, true},
+	}
+	for _, c := range cases {
+		e, err := expr.Parse(c.src)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", c.src, err)
+			continue
+		}
+		got, err := e.EvalBool(env)
+		if err != nil {
+			t.Errorf("Eval(%q): %v", c.src, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("Eval(%q) = %t, want %t", c.src, got, c.want)
+		}
+	}
+}
+
+func TestSyntaxErrors(t *testing.T) {
+	cases := map[string]int{
+		`params.port in [22, `: 21,
+		`params.cidr = "x"`:    13,
+		`env == "unterminated`: 8,
+		`unknown_fn(1)`:        1,
+		`(env == "prod"`:       15,
+		`env == "prod" extra`:  15,
+		`params. == 1`:         9,
+	}
+	for src, col := range cases {
+		_, err := expr.Parse(src)
+		var se *expr.SyntaxError
+		if !errors.As(err, &se) {
+			t.Errorf("Parse(%q): expected syntax error, got %v", src, err)
+			continue
+		}
+		if se.Column != col {
+			t.Errorf("Parse(%q): error at column %d, want %d (%v)", src, se.Column, col, err)
+		}
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	env := map[string]interface{}{"params": map[string]interface{}{"port": 22}}
+	got := expr.Interpolate("port {{ params.port }} is open, {{ bad( }} kept", env)
+	if got != "port 22 is open, {{ bad( }} kept" {
+		t.Errorf("unexpected interpolation: %q", got)
+	}
+}
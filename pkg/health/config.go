@@ -0,0 +1,35 @@
+package health
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/parth14193/ownbot/pkg/config"
+)
+
+// LoadConfig registers probes defined in configuration.
+func (c *Checker) LoadConfig(probes []*config.ProbeConfig) error {
+	for _, pc := range probes {
+		probe := &Probe{
+			Name:           pc.Name,
+			Type:           ProbeType(pc.Type),
+			Target:         pc.Target,
+			ExpectedStatus: pc.ExpectedStatus,
+			Tags:           pc.Tags,
+		}
+		switch probe.Type {
+		case ProbeHTTP, ProbeTCP, ProbeDNS:
+		default:
+			return fmt.Errorf("probe '%s': unsupported type '%s'", pc.Name, pc.Type)
+		}
+		if pc.Timeout != "" {
+			d, err := time.ParseDuration(pc.Timeout)
+			if err != nil {
+				return fmt.Errorf("probe '%s': timeout: %w", pc.Name, err)
+			}
+			probe.Timeout = d
+		}
+		c.AddProbe(probe)
+	}
+	return nil
+}
@@ -218,6 +218,26 @@ func (c *Checker) runDNS(probe *Probe) ProbeResult {
 	return result
 }
 
+// RunProbe executes the probe registered under name.
+func (c *Checker) RunProbe(name string) (ProbeResult, error) {
+	for _, probe := range c.probes {
+		if probe.Name == name {
+			return c.runProbe(probe), nil
+		}
+	}
+	return ProbeResult{}, fmt.Errorf("probe not found: %s", name)
+}
+
+// HasProbe reports whether a probe is registered under name.
+func (c *Checker) HasProbe(name string) bool {
+	for _, probe := range c.probes {
+		if probe.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 // ListProbes returns all probes.
 func (c *Checker) ListProbes() []*Probe { return c.probes }
 
@@ -80,6 +80,18 @@ func (d *Dispatcher) Dispatch(event *Event) []error {
 	return errs
 }
 
+// DispatchAll sends an event to every registered notifier regardless of
+// filters. It is used for alerts that must never be suppressed.
+func (d *Dispatcher) DispatchAll(event *Event) []error {
+	var errs []error
+	for _, n := range d.notifiers {
+		if err := n.Send(event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", n.Name(), err))
+		}
+	}
+	return errs
+}
+
 func (d *Dispatcher) shouldNotify(event *Event) bool {
 	if event.Status == core.StatusSuccess && d.onSuccess {
 		return true
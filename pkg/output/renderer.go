@@ -5,6 +5,7 @@ package output
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/parth14193/ownbot/pkg/core"
 )
@@ -87,6 +88,9 @@ func (r *Renderer) RenderPlan(plan *core.Plan) string {
 				marker = "  ← Requires confirmation"
 			}
 			b.WriteString(fmt.Sprintf("Step %d %s%s → %s: %s%s\n", step.StepNumber, riskTag, padding, step.SkillName, step.Description, marker))
+			for _, gate := range step.Gates {
+				b.WriteString(fmt.Sprintf("   🚦 %s\n", renderGate(gate)))
+			}
 		}
 	}
 
@@ -99,6 +103,18 @@ func (r *Renderer) RenderPlan(plan *core.Plan) string {
 	return b.String()
 }
 
+// renderGate describes a health gate in one line.
+func renderGate(gate core.SafetyGate) string {
+	if gate.Phase == core.GatePost {
+		within := gate.Within
+		if within == 0 {
+			within = 5 * time.Minute
+		}
+		return fmt.Sprintf("after: require '%s' healthy within %s or roll back", gate.Probe, within)
+	}
+	return fmt.Sprintf("before: require '%s' healthy", gate.Probe)
+}
+
 // RenderTable renders an ASCII table with headers and rows.
 func (r *Renderer) RenderTable(headers []string, rows [][]string) string {
 	if len(headers) == 0 {
@@ -188,14 +204,25 @@ func (r *Renderer) RenderSafetyReport(report *core.SafetyReport) string {
 		b.WriteString(fmt.Sprintf("   Procedure: %s\n", report.RollbackProcedure))
 	}
 
-	if report.DryRunRecommended {
+	if report.DryRunRequired {
+		b.WriteString("🧪 Dry Run:            REQUIRED by environment tier\n")
+	} else if report.DryRunRecommended {
 		b.WriteString("🧪 Dry Run:            Recommended\n")
 	}
 
+	if len(report.ImpactTree) > 0 {
+		b.WriteString("\n")
+		b.WriteString(r.RenderImpactTree(report))
+	}
+
 	if report.EnvironmentWarning != "" {
 		b.WriteString(fmt.Sprintf("\n%s\n", report.EnvironmentWarning))
 	}
 
+	if report.FreezeWarning != "" {
+		b.WriteString(fmt.Sprintf("\n%s\n", report.FreezeWarning))
+	}
+
 	if report.ConfirmationPrompt != "" {
 		b.WriteString(fmt.Sprintf("\n> %s\n", report.ConfirmationPrompt))
 	}
@@ -203,6 +230,30 @@ func (r *Renderer) RenderSafetyReport(report *core.SafetyReport) string {
 	return b.String()
 }
 
+// RenderImpactTree formats the downstream impact tree of a safety report.
+func (r *Renderer) RenderImpactTree(report *core.SafetyReport) string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("🌳 IMPACT TREE (%d downstream resources)\n", len(report.DownstreamResources)))
+	for _, root := range report.ImpactTree {
+		b.WriteString(fmt.Sprintf("  %s/%s\n", root.Kind, root.Name))
+		writeImpactChildren(&b, root.Children, "  ")
+	}
+
+	return b.String()
+}
+
+func writeImpactChildren(b *strings.Builder, children []*core.ImpactNode, indent string) {
+	for i, child := range children {
+		branch, next := "├─ ", "│  "
+		if i == len(children)-1 {
+			branch, next = "└─ ", "   "
+		}
+		b.WriteString(fmt.Sprintf("%s%s%s/%s\n", indent, branch, child.Kind, child.Name))
+		writeImpactChildren(b, child.Children, indent+next)
+	}
+}
+
 // RenderSkillInfo formats detailed information about a skill.
 func (r *Renderer) RenderSkillInfo(skill *core.Skill) string {
 	var b strings.Builder
@@ -244,6 +295,13 @@ func (r *Renderer) RenderSkillInfo(skill *core.Skill) string {
 	}
 	b.WriteString("\n")
 
+	if len(skill.Gates) > 0 {
+		b.WriteString("\n🚦 GATES:\n")
+		for _, gate := range skill.Gates {
+			b.WriteString(fmt.Sprintf("  • %s\n", renderGate(gate)))
+		}
+	}
+
 	return b.String()
 }
 
@@ -170,3 +170,26 @@ func TestRenderSafetyReport(t *testing.T) {
 		t.Error("should show blast radius")
 	}
 }
+
+func TestRenderImpactTree(t *testing.T) {
+	r := output.NewRenderer()
+	report := &core.SafetyReport{
+		SkillName:           "k8s.deploy",
+		RiskLevel:           core.RiskHigh,
+		DownstreamResources: []string{"pod/web-1", "pod/web-2"},
+		ImpactTree: []*core.ImpactNode{
+			{Kind: "deployment", Name: "web", Children: []*core.ImpactNode{
+				{Kind: "pod", Name: "web-1"},
+				{Kind: "pod", Name: "web-2"},
+			}},
+		},
+	}
+
+	result := r.RenderSafetyReport(report)
+	if !strings.Contains(result, "IMPACT TREE (2 downstream resources)") {
+		t.Error("safety report should include the impact tree")
+	}
+	if !strings.Contains(result, "├─ pod/web-1") || !strings.Contains(result, "└─ pod/web-2") {
+		t.Errorf("impact tree should draw branches, got:\n%s", result)
+	}
+}
@@ -97,6 +97,17 @@ func (e *Engine) AddConditionalStep(plan *core.Plan, conditionExpr string, onTru
 	return nil
 }
 
+// AddGate attaches a health gate to an existing step.
+func (e *Engine) AddGate(plan *core.Plan, stepNumber int, gate core.SafetyGate) error {
+	for i := range plan.Steps {
+		if plan.Steps[i].StepNumber == stepNumber {
+			plan.Steps[i].Gates = append(plan.Steps[i].Gates, gate)
+			return nil
+		}
+	}
+	return fmt.Errorf("step %d not found", stepNumber)
+}
+
 // Validate checks that all referenced skills exist and required inputs are satisfiable.
 func (e *Engine) Validate(plan *core.Plan) []error {
 	var errs []error
@@ -127,6 +138,15 @@ func (e *Engine) Validate(plan *core.Plan) []error {
 			continue
 		}
 
+		for _, gate := range step.Gates {
+			if gate.Probe == "" {
+				errs = append(errs, fmt.Errorf("step %d: gate has no probe", step.StepNumber))
+			}
+			if gate.Phase != "" && gate.Phase != core.GatePre && gate.Phase != core.GatePost {
+				errs = append(errs, fmt.Errorf("step %d: unknown gate phase '%s'", step.StepNumber, gate.Phase))
+			}
+		}
+
 		// Check required inputs
 		for _, input := range skill.Inputs {
 			if input.Required {
@@ -138,3 +138,24 @@ func TestStepsRequiringConfirmation(t *testing.T) {
 		t.Errorf("expected step 2 to require confirmation, got step %d", confirms[0])
 	}
 }
+
+func TestAddGate(t *testing.T) {
+	engine, _ := setupEngine()
+	plan := engine.CreatePlan("Gated", "deploy with gates")
+	_ = engine.AddStep(plan, "k8s.deploy", "Deploy", map[string]interface{}{"namespace": "default", "deployment": "app", "image": "app:v2"})
+
+	if err := engine.AddGate(plan, 1, core.SafetyGate{Probe: "api-health", Phase: core.GatePost}); err != nil {
+		t.Fatalf("AddGate: %v", err)
+	}
+	if err := engine.AddGate(plan, 9, core.SafetyGate{Probe: "api-health"}); err == nil {
+		t.Error("expected error for unknown step")
+	}
+	if errs := engine.Validate(plan); len(errs) != 0 {
+		t.Errorf("expected valid plan, got %v", errs)
+	}
+
+	_ = engine.AddGate(plan, 1, core.SafetyGate{Probe: "api-health", Phase: "during"})
+	if errs := engine.Validate(plan); len(errs) != 1 {
+		t.Errorf("expected unknown gate phase to be reported, got %v", errs)
+	}
+}
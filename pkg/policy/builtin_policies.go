@@ -2,35 +2,89 @@ package policy
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/parth14193/ownbot/pkg/core"
 )
 
+// BuiltinOptions parameterises the built-in policies.
+type BuiltinOptions struct {
+	RequiredTags      []string         // require_tags
+	SensitivePorts    []int            // no_wide_open_sg
+	MaxBlastRadius    int              // max_blast_radius
+	AllowedRegistries []string         // k8s_allowed_registries; empty allows any registry
+	Now               func() time.Time // production_deploy_window; nil uses the wall clock
+}
+
+// DefaultBuiltinOptions returns the parameters the built-in policies use by default.
+func DefaultBuiltinOptions() BuiltinOptions {
+	return BuiltinOptions{
+		RequiredTags:   []string{"team", "env", "service"},
+		SensitivePorts: []int{22, 3389, 3306, 5432},
+		MaxBlastRadius: 50,
+	}
+}
+
 // BuiltinPolicies returns all built-in infrastructure guardrail policies.
 func BuiltinPolicies() []*Policy {
+	return BuiltinPoliciesWith(DefaultBuiltinOptions())
+}
+
+// BuiltinPoliciesWith returns the built-in policies configured with opts.
+// Zero-valued options fall back to the defaults.
+func BuiltinPoliciesWith(opts BuiltinOptions) []*Policy {
+	defaults := DefaultBuiltinOptions()
+	if len(opts.RequiredTags) == 0 {
+		opts.RequiredTags = defaults.RequiredTags
+	}
+	if len(opts.SensitivePorts) == 0 {
+		opts.SensitivePorts = defaults.SensitivePorts
+	}
+	if opts.MaxBlastRadius <= 0 {
+		opts.MaxBlastRadius = defaults.MaxBlastRadius
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
 	return []*Policy{
 		noPublicS3Policy(),
-		requireTagsPolicy(),
-		noWideOpenSGPolicy(),
-		productionDeployWindowPolicy(),
+		requireTagsPolicy(opts.RequiredTags),
+		noWideOpenSGPolicy(opts.SensitivePorts),
+		productionDeployWindowPolicy(opts.Now),
 		requirePeerReviewPolicy(),
-		maxBlastRadiusPolicy(),
+		maxBlastRadiusPolicy(opts.MaxBlastRadius),
 		noDirectProdAccess(),
 		enforceEncryptionPolicy(),
+		k8sNoPrivilegedPolicy(),
+		k8sRequireLimitsPolicy(),
+		k8sNoLatestTagPolicy(),
+		k8sNoHostPathPolicy(),
+		k8sRequireProbesPolicy(),
+		k8sAllowedRegistriesPolicy(opts.AllowedRegistries),
+	}
+}
+
+// BuiltinNames returns the names of all built-in policies.
+func BuiltinNames() []string {
+	var names []string
+	for _, p := range BuiltinPolicies() {
+		names = append(names, p.Name)
 	}
+	return names
 }
 
 // ── Policy Implementations ─────────────────────────────────────
 
 func noPublicS3Policy() *Policy {
 	return &Policy{
-		Name:        "no_public_s3",
-		Description: "Deny S3 operations that could expose buckets publicly",
-		Enforcement: EnforcementDeny,
-		Severity:    SeverityCritical,
-		AppliesTo:   []string{"aws.s3.*"},
+		Name:          "no_public_s3",
+		Description:   "Deny S3 operations that could expose buckets publicly",
+		Enforcement:   EnforcementDeny,
+		Severity:      SeverityCritical,
+		AppliesTo:     []string{"aws.s3.*"},
+		ResourceCheck: publicS3Resource,
 		CheckFunc: func(skill *core.Skill, params map[string]interface{}, env string) (bool, string) {
 			if params == nil {
 				return false, ""
@@ -46,14 +100,14 @@ func noPublicS3Policy() *Policy {
 	}
 }
 
-func requireTagsPolicy() *Policy {
-	requiredTags := []string{"team", "env", "service"}
+func requireTagsPolicy(requiredTags []string) *Policy {
 	return &Policy{
-		Name:        "require_tags",
-		Description: "Resources must have required tags (team, env, service)",
-		Enforcement: EnforcementWarn,
-		Severity:    SeverityWarning,
-		AppliesTo:   []string{"aws.ec2.*", "aws.lambda.*", "gcp.gce.*", "azure.vm.*"},
+		Name:          "require_tags",
+		Description:   fmt.Sprintf("Resources must have required tags (%s)", strings.Join(requiredTags, ", ")),
+		Enforcement:   EnforcementWarn,
+		Severity:      SeverityWarning,
+		AppliesTo:     []string{"aws.ec2.*", "aws.lambda.*", "gcp.gce.*", "azure.vm.*"},
+		ResourceCheck: requiredTagsResource(requiredTags),
 		CheckFunc: func(skill *core.Skill, params map[string]interface{}, env string) (bool, string) {
 			if params == nil {
 				return true, fmt.Sprintf("No tags provided — required tags: %s", strings.Join(requiredTags, ", "))
@@ -84,13 +138,14 @@ func requireTagsPolicy() *Policy {
 	}
 }
 
-func noWideOpenSGPolicy() *Policy {
+func noWideOpenSGPolicy(sensitivePorts []int) *Policy {
 	return &Policy{
-		Name:        "no_wide_open_sg",
-		Description: "Deny security group rules allowing 0.0.0.0/0 on sensitive ports",
-		Enforcement: EnforcementDeny,
-		Severity:    SeverityCritical,
-		AppliesTo:   []string{"aws.sg.*"},
+		Name:          "no_wide_open_sg",
+		Description:   "Deny security group rules allowing 0.0.0.0/0 on sensitive ports",
+		Enforcement:   EnforcementDeny,
+		Severity:      SeverityCritical,
+		AppliesTo:     []string{"aws.sg.*"},
+		ResourceCheck: wideOpenSGResource(sensitivePorts),
 		CheckFunc: func(skill *core.Skill, params map[string]interface{}, env string) (bool, string) {
 			if params == nil {
 				return false, ""
@@ -101,7 +156,13 @@ func noWideOpenSGPolicy() *Policy {
 					port := params["port"]
 					if port != nil {
 						portStr := fmt.Sprintf("%v", port)
-						sensitivePort := portStr == "22" || portStr == "3389" || portStr == "3306" || portStr == "5432"
+						sensitivePort := false
+						for _, p := range sensitivePorts {
+							if portStr == strconv.Itoa(p) {
+								sensitivePort = true
+								break
+							}
+						}
 						if sensitivePort {
 							return true, fmt.Sprintf("Cannot open port %s to %s — use VPN or bastion host", portStr, cidrStr)
 						}
@@ -114,7 +175,7 @@ func noWideOpenSGPolicy() *Policy {
 	}
 }
 
-func productionDeployWindowPolicy() *Policy {
+func productionDeployWindowPolicy(clock func() time.Time) *Policy {
 	return &Policy{
 		Name:         "production_deploy_window",
 		Description:  "Production deployments only allowed during business hours (09:00-17:00 UTC, Mon-Fri)",
@@ -123,7 +184,7 @@ func productionDeployWindowPolicy() *Policy {
 		AppliesTo:    []string{"k8s.deploy", "helm.upgrade", "terraform.apply", "argocd.sync"},
 		Environments: []string{"production", "prod"},
 		CheckFunc: func(skill *core.Skill, params map[string]interface{}, env string) (bool, string) {
-			now := time.Now().UTC()
+			now := clock().UTC()
 			hour := now.Hour()
 			weekday := now.Weekday()
 
@@ -160,10 +221,10 @@ func requirePeerReviewPolicy() *Policy {
 	}
 }
 
-func maxBlastRadiusPolicy() *Policy {
+func maxBlastRadiusPolicy(threshold int) *Policy {
 	return &Policy{
 		Name:        "max_blast_radius",
-		Description: "Deny operations affecting more than 50 resources at once",
+		Description: fmt.Sprintf("Deny operations affecting more than %d resources at once", threshold),
 		Enforcement: EnforcementDeny,
 		Severity:    SeverityCritical,
 		AppliesTo:   []string{"*"},
@@ -172,8 +233,8 @@ func maxBlastRadiusPolicy() *Policy {
 				return false, ""
 			}
 			if count, ok := params["_resource_count"]; ok {
-				if c, ok := count.(int); ok && c > 50 {
-					return true, fmt.Sprintf("Operation affects %d resources (max: 50) — break into smaller batches", c)
+				if c, ok := count.(int); ok && c > threshold {
+					return true, fmt.Sprintf("Operation affects %d resources (max: %d) — break into smaller batches", c, threshold)
 				}
 			}
 			return false, ""
@@ -200,11 +261,12 @@ func noDirectProdAccess() *Policy {
 
 func enforceEncryptionPolicy() *Policy {
 	return &Policy{
-		Name:        "enforce_encryption",
-		Description: "Storage resources must have encryption enabled",
-		Enforcement: EnforcementDeny,
-		Severity:    SeverityCritical,
-		AppliesTo:   []string{"aws.s3.*", "gcp.gcs.*", "azure.blob.*"},
+		Name:          "enforce_encryption",
+		Description:   "Storage resources must have encryption enabled",
+		Enforcement:   EnforcementDeny,
+		Severity:      SeverityCritical,
+		AppliesTo:     []string{"aws.s3.*", "gcp.gcs.*", "azure.blob.*"},
+		ResourceCheck: encryptionResource,
 		CheckFunc: func(skill *core.Skill, params map[string]interface{}, env string) (bool, string) {
 			if params == nil {
 				return false, "" // Can't check without params
@@ -218,3 +280,94 @@ func enforceEncryptionPolicy() *Policy {
 		},
 	}
 }
+
+// ── Kubernetes manifest policies ───────────────────────────────
+//
+// These inspect the rendered manifests passed to k8s.deploy and helm.upgrade
+// (the manifest_file param). Only image checks can run on params alone.
+
+var k8sSkills = []string{"k8s.*", "helm.*"}
+
+func k8sNoPrivilegedPolicy() *Policy {
+	return &Policy{
+		Name:          "k8s_no_privileged",
+		Description:   "Containers must not run privileged",
+		Enforcement:   EnforcementDeny,
+		Severity:      SeverityCritical,
+		AppliesTo:     k8sSkills,
+		ManifestCheck: privilegedManifest,
+	}
+}
+
+func k8sRequireLimitsPolicy() *Policy {
+	return &Policy{
+		Name:          "k8s_require_limits",
+		Description:   "Containers must set CPU and memory limits",
+		Enforcement:   EnforcementWarn,
+		Severity:      SeverityWarning,
+		AppliesTo:     k8sSkills,
+		ManifestCheck: resourceLimitsManifest,
+	}
+}
+
+func k8sNoLatestTagPolicy() *Policy {
+	return &Policy{
+		Name:          "k8s_no_latest_tag",
+		Description:   "Container images must be pinned to a version, not :latest",
+		Enforcement:   EnforcementWarn,
+		Severity:      SeverityWarning,
+		AppliesTo:     k8sSkills,
+		ManifestCheck: latestTagManifest,
+		CheckFunc: func(skill *core.Skill, params map[string]interface{}, env string) (bool, string) {
+			image, _ := params["image"].(string)
+			if image != "" && usesLatestTag(image) {
+				return true, fmt.Sprintf("Image '%s' uses a mutable tag — pin a version or digest", image)
+			}
+			return false, ""
+		},
+	}
+}
+
+func k8sNoHostPathPolicy() *Policy {
+	return &Policy{
+		Name:          "k8s_no_host_path",
+		Description:   "Pods must not mount hostPath volumes",
+		Enforcement:   EnforcementDeny,
+		Severity:      SeverityCritical,
+		AppliesTo:     k8sSkills,
+		ManifestCheck: hostPathManifest,
+	}
+}
+
+func k8sRequireProbesPolicy() *Policy {
+	return &Policy{
+		Name:          "k8s_require_probes",
+		Description:   "Long-running containers must define liveness and readiness probes",
+		Enforcement:   EnforcementWarn,
+		Severity:      SeverityWarning,
+		AppliesTo:     k8sSkills,
+		ManifestCheck: probesManifest,
+	}
+}
+
+func k8sAllowedRegistriesPolicy(allowed []string) *Policy {
+	description := "Container images must come from allow-listed registries"
+	if len(allowed) > 0 {
+		description = fmt.Sprintf("%s (%s)", description, strings.Join(allowed, ", "))
+	}
+	return &Policy{
+		Name:          "k8s_allowed_registries",
+		Description:   description,
+		Enforcement:   EnforcementDeny,
+		Severity:      SeverityCritical,
+		AppliesTo:     k8sSkills,
+		ManifestCheck: allowedRegistriesManifest(allowed),
+		CheckFunc: func(skill *core.Skill, params map[string]interface{}, env string) (bool, string) {
+			image, _ := params["image"].(string)
+			if image != "" && !registryAllowed(image, allowed) {
+				return true, fmt.Sprintf("Image '%s' is not from an allowed registry (%s)", image, strings.Join(allowed, ", "))
+			}
+			return false, ""
+		},
+	}
+}
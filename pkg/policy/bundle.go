@@ -0,0 +1,461 @@
+package policy
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A policy bundle is a directory or .tar.gz archive of declarative policy
+// files (and Rego modules) shipped by a central team:
+//
+//	manifest.yaml   name, version and the SHA-256 of every other file
+//	manifest.sig    base64 ed25519 signature of manifest.yaml
+//	network.yaml    declarative policies
+//	sg.rego         Rego modules
+//
+// A bundle is only loaded when its signature verifies against a trusted key,
+// every file matches the manifest, its version satisfies the configured pin
+// and it is not older than the last version accepted.
+
+const (
+	bundleManifestFile  = "manifest.yaml"
+	bundleSignatureFile = "manifest.sig"
+)
+
+// BundleManifest describes a policy bundle.
+type BundleManifest struct {
+	Name        string            `yaml:"name" json:"name"`
+	Version     string            `yaml:"version" json:"version"`
+	Description string            `yaml:"description,omitempty" json:"description,omitempty"`
+	Files       map[string]string `yaml:"files" json:"files"` // path -> hex SHA-256
+}
+
+// Bundle is an opened policy bundle. Its contents are not trusted until
+// Verify succeeds.
+type Bundle struct {
+	Manifest  BundleManifest
+	Path      string
+	manifest  []byte
+	signature []byte
+	files     map[string][]byte
+}
+
+// OpenBundle reads a bundle from a directory or a .tar.gz/.tgz archive.
+func OpenBundle(p string) (*Bundle, error) {
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy bundle: %w", err)
+	}
+	var files map[string][]byte
+	if info.IsDir() {
+		files, err = readBundleDir(p)
+	} else {
+		files, err = readBundleArchive(p)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", p, err)
+	}
+
+	b := &Bundle{Path: p, files: files}
+	var ok bool
+	if b.manifest, ok = files[bundleManifestFile]; !ok {
+		return nil, fmt.Errorf("%s: missing %s", p, bundleManifestFile)
+	}
+	delete(files, bundleManifestFile)
+	if sig, ok := files[bundleSignatureFile]; ok {
+		b.signature = sig
+		delete(files, bundleSignatureFile)
+	}
+	if err := yaml.Unmarshal(b.manifest, &b.Manifest); err != nil {
+		return nil, fmt.Errorf("%s: invalid %s: %w", p, bundleManifestFile, err)
+	}
+	if b.Manifest.Name == "" || b.Manifest.Version == "" {
+		return nil, fmt.Errorf("%s: %s must set name and version", p, bundleManifestFile)
+	}
+	if _, err := parseVersion(b.Manifest.Version); err != nil {
+		return nil, fmt.Errorf("%s: %w", p, err)
+	}
+	return b, nil
+}
+
+func readBundleDir(dir string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = data
+		return nil
+	})
+	return files, err
+}
+
+// readBundleArchive reads a gzipped tarball. A single top-level directory
+// holding the manifest is stripped, as produced by `tar czf b.tgz bundle/`.
+func readBundleArchive(p string) (map[string][]byte, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(gz)
+	files := make(map[string][]byte)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Clean(strings.TrimPrefix(hdr.Name, "./"))
+		if strings.HasPrefix(name, "../") || path.IsAbs(name) {
+			return nil, fmt.Errorf("invalid path '%s' in archive", hdr.Name)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		files[name] = data
+	}
+
+	if _, ok := files[bundleManifestFile]; !ok {
+		for name := range files {
+			if dir, base := path.Split(name); base == bundleManifestFile && strings.Count(dir, "/") == 1 {
+				stripped := make(map[string][]byte)
+				for n, data := range files {
+					if rel, ok := strings.CutPrefix(n, dir); ok {
+						stripped[rel] = data
+					}
+				}
+				return stripped, nil
+			}
+		}
+	}
+	return files, nil
+}
+
+// Files returns the bundle's content file names, sorted.
+func (b *Bundle) Files() []string {
+	names := make([]string, 0, len(b.files))
+	for name := range b.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// File returns the contents of a bundle file.
+func (b *Bundle) File(name string) ([]byte, bool) {
+	data, ok := b.files[name]
+	return data, ok
+}
+
+// Verify checks the manifest signature against the trusted keys and every
+// file against its manifest digest. Unsigned bundles, files missing from the
+// manifest and manifest entries missing from the bundle are all refused.
+func (b *Bundle) Verify(keys []ed25519.PublicKey) error {
+	if len(b.signature) == 0 {
+		return fmt.Errorf("bundle '%s' is unsigned", b.Manifest.Name)
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(b.signature)))
+	if err != nil {
+		return fmt.Errorf("bundle '%s': invalid %s: %w", b.Manifest.Name, bundleSignatureFile, err)
+	}
+	trusted := false
+	for _, key := range keys {
+		if ed25519.Verify(key, b.manifest, sig) {
+			trusted = true
+			break
+		}
+	}
+	if !trusted {
+		return fmt.Errorf("bundle '%s': signature does not match any trusted key", b.Manifest.Name)
+	}
+
+	var errs []error
+	for _, name := range b.Files() {
+		want, ok := b.Manifest.Files[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("bundle '%s': %s is not listed in the manifest", b.Manifest.Name, name))
+			continue
+		}
+		if got := digest(b.files[name]); !strings.EqualFold(got, want) {
+			errs = append(errs, fmt.Errorf("bundle '%s': %s does not match its manifest digest", b.Manifest.Name, name))
+		}
+	}
+	for _, name := range sortedStringKeys(b.Manifest.Files) {
+		if _, ok := b.files[name]; !ok {
+			errs = append(errs, fmt.Errorf("bundle '%s': %s is listed in the manifest but missing", b.Manifest.Name, name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// SignBundle writes the file digests into dir's manifest.yaml, keeping its
+// name, version and description, and signs it with key.
+func SignBundle(dir string, key ed25519.PrivateKey) (*BundleManifest, error) {
+	b, err := OpenBundle(dir)
+	if err != nil {
+		return nil, err
+	}
+	m := b.Manifest
+	m.Files = make(map[string]string)
+	for _, name := range b.Files() {
+		m.Files[name] = digest(b.files[name])
+	}
+	data, err := yaml.Marshal(&m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal bundle manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, bundleManifestFile), data, 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write bundle manifest: %w", err)
+	}
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(key, data))
+	if err := os.WriteFile(filepath.Join(dir, bundleSignatureFile), []byte(sig+"\n"), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write bundle signature: %w", err)
+	}
+	return &m, nil
+}
+
+// ParsePublicKey decodes a base64 ed25519 public key.
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid ed25519 public key (want %d base64-encoded bytes)", ed25519.PublicKeySize)
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+// ParsePrivateKey decodes a base64 ed25519 private key.
+func ParsePrivateKey(s string) (ed25519.PrivateKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(raw) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid ed25519 private key (want %d base64-encoded bytes)", ed25519.PrivateKeySize)
+	}
+	return ed25519.PrivateKey(raw), nil
+}
+
+// ── Versions ───────────────────────────────────────────────────
+
+// parseVersion parses a dotted numeric version such as 1.4.2 or v2.0.
+func parseVersion(v string) ([]int, error) {
+	parts := strings.Split(strings.TrimPrefix(v, "v"), ".")
+	out := make([]int, len(parts))
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid bundle version '%s' (want dotted numbers, e.g. 1.4.2)", v)
+		}
+		out[i] = n
+	}
+	return out, nil
+}
+
+// CompareVersions returns -1, 0 or 1 as version a is older than, equal to or
+// newer than b. Missing components count as zero.
+func CompareVersions(a, b string) (int, error) {
+	va, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	for i := 0; i < len(va) || i < len(vb); i++ {
+		var x, y int
+		if i < len(va) {
+			x = va[i]
+		}
+		if i < len(vb) {
+			y = vb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1, nil
+			}
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// BundleLock records the newest version accepted for each bundle so older
+// bundles can be refused. When a path is set it is persisted as JSON.
+type BundleLock struct {
+	path     string
+	versions map[string]string
+}
+
+// NewBundleLock creates an in-memory lock.
+func NewBundleLock() *BundleLock {
+	return &BundleLock{versions: make(map[string]string)}
+}
+
+// OpenBundleLock loads a persisted lock from path, creating it on first accept.
+func OpenBundleLock(path string) (*BundleLock, error) {
+	l := &BundleLock{path: path, versions: make(map[string]string)}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle lock: %w", err)
+	}
+	if err := json.Unmarshal(data, &l.versions); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return l, nil
+}
+
+// Version returns the newest accepted version of a bundle, or "".
+func (l *BundleLock) Version(name string) string {
+	return l.versions[name]
+}
+
+// Accept refuses a version older than the newest accepted one and records
+// newer versions.
+func (l *BundleLock) Accept(name, version string) error {
+	if prev, ok := l.versions[name]; ok {
+		cmp, err := CompareVersions(version, prev)
+		if err != nil {
+			return err
+		}
+		if cmp < 0 {
+			return fmt.Errorf("bundle '%s' %s is older than the accepted %s — refusing downgrade", name, version, prev)
+		}
+		if cmp == 0 {
+			return nil
+		}
+	}
+	l.versions[name] = version
+	if l.path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create bundle lock directory: %w", err)
+	}
+	data, err := json.MarshalIndent(l.versions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal bundle lock: %w", err)
+	}
+	return os.WriteFile(l.path, append(data, '\n'), 0o600)
+}
+
+// ── Loading ────────────────────────────────────────────────────
+
+// BundleTrust is what a bundle must satisfy to be loaded.
+type BundleTrust struct {
+	Keys []ed25519.PublicKey
+	Pin  string      // required version; empty accepts any version
+	Lock *BundleLock // refuses downgrades; nil skips the check
+}
+
+// BundleCompiler builds a backend from the non-declarative files of a bundle,
+// such as Rego modules. It returns a nil Backend when there are none.
+type BundleCompiler func(b *Bundle) (Backend, error)
+
+// LoadBundle opens and verifies a bundle, then registers its declarative
+// policies and the backend compile builds from its other files (nil skips
+// them). Everything is parsed and validated before the version is accepted
+// and anything is registered, so a bundle loads completely or not at all.
+func (e *Engine) LoadBundle(p string, trust BundleTrust, compile BundleCompiler) (*Bundle, []*Policy, error) {
+	b, err := OpenBundle(p)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := b.Verify(trust.Keys); err != nil {
+		return nil, nil, err
+	}
+	if trust.Pin != "" {
+		cmp, err := CompareVersions(b.Manifest.Version, trust.Pin)
+		if err != nil {
+			return nil, nil, err
+		}
+		if cmp != 0 {
+			return nil, nil, fmt.Errorf("bundle '%s' is version %s, pinned to %s", b.Manifest.Name, b.Manifest.Version, trust.Pin)
+		}
+	}
+	if trust.Lock != nil {
+		if prev := trust.Lock.Version(b.Manifest.Name); prev != "" {
+			if cmp, err := CompareVersions(b.Manifest.Version, prev); err != nil || cmp < 0 {
+				return nil, nil, fmt.Errorf("bundle '%s' %s is older than the accepted %s — refusing downgrade", b.Manifest.Name, b.Manifest.Version, prev)
+			}
+		}
+	}
+
+	var loaded []*Policy
+	var errs []error
+	for _, name := range b.Files() {
+		if ext := path.Ext(name); (ext != ".yaml" && ext != ".yml") || IsFixtureFile(name) {
+			continue
+		}
+		policies, err := ParsePolicies(b.Manifest.Name+"@"+b.Manifest.Version+"/"+name, b.files[name])
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		loaded = append(loaded, policies...)
+	}
+	policies, err := e.validateLoaded(loaded, errs)
+	if err != nil {
+		return nil, nil, err
+	}
+	var backend Backend
+	if compile != nil {
+		if backend, err = compile(b); err != nil {
+			return nil, nil, err
+		}
+	}
+	if trust.Lock != nil {
+		if err := trust.Lock.Accept(b.Manifest.Name, b.Manifest.Version); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for _, p := range policies {
+		e.Register(p)
+	}
+	if backend != nil {
+		e.AddBackend(backend)
+	}
+	return b, policies, nil
+}
@@ -0,0 +1,242 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/parth14193/ownbot/pkg/config"
+	"github.com/parth14193/ownbot/pkg/environment"
+)
+
+// builtinParams lists the params each built-in policy accepts.
+var builtinParams = map[string][]string{
+	"require_tags":           {"tags"},
+	"no_wide_open_sg":        {"ports"},
+	"max_blast_radius":       {"threshold"},
+	"k8s_allowed_registries": {"registries"},
+}
+
+// NewEngineFromConfig builds an engine from the policies section of the
+// configuration: global and per-environment enforcement modes, the enabled
+// built-in policies (all of them when the list is empty), per-policy
+// enforcement/severity overrides, exemptions and built-in parameters. A nil cfg yields
+// the defaults: warn mode with every built-in enabled.
+func NewEngineFromConfig(cfg *config.PolicyConfig, classifier *environment.Classifier) (*Engine, error) {
+	if cfg == nil {
+		cfg = &config.PolicyConfig{}
+	}
+	var errs []error
+
+	mode, err := parseEnforcement(cfg.EnforcementMode, EnforcementWarn)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("enforcement_mode: %w", err))
+	}
+	e := NewEngine(mode)
+	if classifier != nil {
+		e.SetClassifier(classifier)
+	}
+	e.SetEnabled(cfg.IsEnabled())
+
+	for env, m := range cfg.EnvironmentModes {
+		envMode, err := parseEnforcement(m, "")
+		if err != nil || envMode == "" {
+			errs = append(errs, fmt.Errorf("environment_modes.%s: unknown mode '%s' (want warn or deny)", env, m))
+			continue
+		}
+		e.SetEnvironmentMode(env, envMode)
+	}
+
+	opts, err := builtinOptions(cfg.Overrides)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	names := sortedKeys(cfg.Overrides)
+	for _, name := range names {
+		o := cfg.Overrides[name]
+		if o == nil {
+			continue
+		}
+		enforcement, err := parseEnforcement(o.Enforcement, "")
+		if strings.EqualFold(o.Enforcement, string(EnforcementAudit)) {
+			enforcement, err = EnforcementAudit, nil
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("overrides.%s.enforcement: %w", name, err))
+		}
+		severity, err := parseSeverity(o.Severity)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("overrides.%s.severity: %w", name, err))
+		}
+		if enforcement != "" || severity != "" {
+			e.SetOverride(name, Override{Enforcement: enforcement, Severity: severity, Reason: o.Reason})
+		}
+	}
+
+	for i, x := range cfg.Exemptions {
+		if x == nil {
+			continue
+		}
+		if err := e.AddExemption(Exemption{Policy: x.Policy, Environments: x.Environments, Reason: x.Reason}); err != nil {
+			errs = append(errs, fmt.Errorf("exemptions[%d]: %w", i, err))
+		}
+	}
+
+	enabled := make(map[string]bool)
+	for _, name := range cfg.EnabledPolicies {
+		enabled[name] = true
+	}
+	known := make(map[string]bool)
+	opts.Now = e.Now
+	for _, p := range BuiltinPoliciesWith(opts) {
+		known[p.Name] = true
+		if err := e.checkOverride(p); err != nil {
+			errs = append(errs, err)
+		}
+		if len(enabled) == 0 || enabled[p.Name] {
+			e.Register(p)
+		}
+	}
+	for _, name := range cfg.EnabledPolicies {
+		if !known[name] {
+			errs = append(errs, fmt.Errorf("enabled_policies: unknown built-in policy '%s'", name))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return e, nil
+}
+
+func parseEnforcement(s string, fallback EnforcementLevel) (EnforcementLevel, error) {
+	switch EnforcementLevel(strings.ToLower(s)) {
+	case "":
+		return fallback, nil
+	case EnforcementWarn:
+		return EnforcementWarn, nil
+	case EnforcementDeny:
+		return EnforcementDeny, nil
+	}
+	return "", fmt.Errorf("unknown enforcement '%s' (want warn or deny)", s)
+}
+
+func parseSeverity(s string) (Severity, error) {
+	switch sev := Severity(strings.ToUpper(s)); sev {
+	case "", SeverityInfo, SeverityWarning, SeverityCritical:
+		return sev, nil
+	}
+	return "", fmt.Errorf("unknown severity '%s' (want INFO, WARNING or CRITICAL)", s)
+}
+
+// builtinOptions reads built-in policy params from overrides.
+func builtinOptions(overrides map[string]*config.PolicyOverride) (BuiltinOptions, error) {
+	var opts BuiltinOptions
+	var errs []error
+	for _, name := range sortedKeys(overrides) {
+		o := overrides[name]
+		if o == nil || len(o.Params) == 0 {
+			continue
+		}
+		allowed, ok := builtinParams[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("overrides.%s.params: policy takes no params", name))
+			continue
+		}
+		for key, raw := range o.Params {
+			if !containsString(allowed, key) {
+				errs = append(errs, fmt.Errorf("overrides.%s.params: unknown param '%s' (want %s)", name, key, strings.Join(allowed, ", ")))
+				continue
+			}
+			var err error
+			switch key {
+			case "tags":
+				opts.RequiredTags, err = stringList(raw)
+			case "ports":
+				opts.SensitivePorts, err = intList(raw)
+			case "registries":
+				opts.AllowedRegistries, err = stringList(raw)
+			case "threshold":
+				opts.MaxBlastRadius, err = toInt(raw)
+				if err == nil && opts.MaxBlastRadius <= 0 {
+					err = fmt.Errorf("must be positive")
+				}
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("overrides.%s.params.%s: %w", name, key, err))
+			}
+		}
+	}
+	return opts, errors.Join(errs...)
+}
+
+func stringList(raw interface{}) ([]string, error) {
+	items, ok := raw.([]interface{})
+	if !ok || len(items) == 0 {
+		return nil, fmt.Errorf("expected a non-empty list")
+	}
+	out := make([]string, len(items))
+	for i, item := range items {
+		s, ok := item.(string)
+		if !ok || s == "" {
+			return nil, fmt.Errorf("item %d: expected a string", i)
+		}
+		out[i] = s
+	}
+	return out, nil
+}
+
+func intList(raw interface{}) ([]int, error) {
+	items, ok := raw.([]interface{})
+	if !ok || len(items) == 0 {
+		return nil, fmt.Errorf("expected a non-empty list")
+	}
+	out := make([]int, len(items))
+	for i, item := range items {
+		n, err := toInt(item)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+		out[i] = n
+	}
+	return out, nil
+}
+
+func toInt(raw interface{}) (int, error) {
+	switch n := raw.(type) {
+	case int:
+		return n, nil
+	case int64:
+		return int(n), nil
+	case float64:
+		if n == float64(int(n)) {
+			return int(n), nil
+		}
+	case string:
+		if v, err := strconv.Atoi(n); err == nil {
+			return v, nil
+		}
+	}
+	return 0, fmt.Errorf("expected an integer, got %v", raw)
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]*config.PolicyOverride) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,296 @@
+package policy
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DecisionKind is the outcome recorded for a policy in the decision log.
+type DecisionKind string
+
+const (
+	DecisionAllow      DecisionKind = "allow"      // no policy objected (Policy is empty)
+	DecisionDeny       DecisionKind = "deny"       // blocked execution
+	DecisionWarn       DecisionKind = "warn"       // reported a warning
+	DecisionAudit      DecisionKind = "audit"      // recorded in audit mode, not enforced
+	DecisionSuppressed DecisionKind = "suppressed" // covered by an exception
+)
+
+// Decision is one decision log record. An evaluation writes one record per
+// violation, or a single allow record when nothing was violated; records of
+// the same evaluation share Timestamp and InputsHash.
+type Decision struct {
+	Timestamp   time.Time        `json:"timestamp"`
+	Policy      string           `json:"policy,omitempty"`
+	Decision    DecisionKind     `json:"decision"`
+	WouldBlock  bool             `json:"would_block,omitempty"` // audit decisions that deny outside audit mode
+	Skill       string           `json:"skill"`
+	Environment string           `json:"environment"`
+	User        string           `json:"user,omitempty"`
+	Resource    string           `json:"resource,omitempty"`
+	Reason      string           `json:"reason,omitempty"`
+	InputsHash  string           `json:"inputs_hash"`
+	Enforcement EnforcementLevel `json:"enforcement,omitempty"`
+}
+
+// DecisionLog is an append-only log of policy decisions. When a path is set,
+// decisions are persisted as JSON lines.
+type DecisionLog struct {
+	mu        sync.RWMutex
+	path      string
+	decisions []Decision
+	now       func() time.Time
+}
+
+// NewDecisionLog creates an in-memory decision log.
+func NewDecisionLog() *DecisionLog {
+	return &DecisionLog{now: time.Now}
+}
+
+// OpenDecisionLog loads a persisted decision log from path, creating it on
+// first record.
+func OpenDecisionLog(path string) (*DecisionLog, error) {
+	l := &DecisionLog{path: path, now: time.Now}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open policy decision log: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var d Decision
+		if err := json.Unmarshal(scanner.Bytes(), &d); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		l.decisions = append(l.decisions, d)
+	}
+	return l, scanner.Err()
+}
+
+// SetClock overrides the time source (for testing).
+func (l *DecisionLog) SetClock(now func() time.Time) {
+	l.now = now
+}
+
+// Record appends the decisions of one evaluation.
+func (l *DecisionLog) Record(in *Input, result *EvaluationResult) error {
+	base := Decision{
+		Timestamp:   l.now().UTC(),
+		Environment: in.Environment,
+		User:        in.User,
+		InputsHash:  InputsHash(in),
+	}
+	if in.Skill != nil {
+		base.Skill = in.Skill.Name
+	}
+
+	var records []Decision
+	add := func(kind DecisionKind, vs []Violation) {
+		for _, v := range vs {
+			d := base
+			d.Policy = v.PolicyName
+			d.Decision = kind
+			d.Resource = v.Resource
+			d.Reason = v.Reason
+			d.Enforcement = v.Enforcement
+			if kind == DecisionAudit {
+				d.WouldBlock = v.Shadow == EnforcementDeny
+				d.Enforcement = v.Shadow
+			}
+			records = append(records, d)
+		}
+	}
+	add(DecisionDeny, result.Violations)
+	add(DecisionWarn, result.Warnings)
+	add(DecisionAudit, result.Audited)
+	add(DecisionSuppressed, result.Suppressed)
+	if len(records) == 0 {
+		d := base
+		d.Decision = DecisionAllow
+		records = append(records, d)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.path != "" {
+		if err := l.persist(records); err != nil {
+			return err
+		}
+	}
+	l.decisions = append(l.decisions, records...)
+	return nil
+}
+
+func (l *DecisionLog) persist(records []Decision) error {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create decision log directory: %w", err)
+	}
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open policy decision log: %w", err)
+	}
+	defer f.Close()
+
+	var buf []byte
+	for _, d := range records {
+		data, err := json.Marshal(d)
+		if err != nil {
+			return fmt.Errorf("failed to marshal decision: %w", err)
+		}
+		buf = append(append(buf, data...), '\n')
+	}
+	_, err = f.Write(buf)
+	return err
+}
+
+// Decisions returns decisions recorded at or after since.
+func (l *DecisionLog) Decisions(since time.Time) []Decision {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	var out []Decision
+	for _, d := range l.decisions {
+		if !d.Timestamp.Before(since) {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// InputsHash fingerprints the skill, params, environment and user of an input
+// so repeated executions of the same action can be recognised in the log.
+func InputsHash(in *Input) string {
+	doc := map[string]interface{}{
+		"params":      in.Params,
+		"environment": in.Environment,
+		"user":        in.User,
+	}
+	if in.Skill != nil {
+		doc["skill"] = in.Skill.Name
+	}
+	data, err := json.Marshal(doc) // map keys are sorted, so this is canonical
+	if err != nil {
+		data = []byte(fmt.Sprintf("%v", doc))
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// PolicyStats counts the executions a policy affected in a report window.
+type PolicyStats struct {
+	Policy     string `json:"policy"`
+	Blocked    int    `json:"blocked"`
+	WouldBlock int    `json:"would_block"` // audit mode only
+	Warned     int    `json:"warned"`
+	Suppressed int    `json:"suppressed"`
+}
+
+// DecisionReport summarises the decision log over a window.
+type DecisionReport struct {
+	Since      time.Time      `json:"since"`
+	Executions int            `json:"executions"`
+	Blocked    int            `json:"blocked"`
+	WouldBlock int            `json:"would_block"` // executions audit-mode policies would have blocked
+	Policies   []*PolicyStats `json:"policies"`
+}
+
+// Report summarises decisions recorded at or after since. Counts are per
+// execution: a policy violated by several resources of one execution counts
+// once.
+func (l *DecisionLog) Report(since time.Time) *DecisionReport {
+	type key struct {
+		at   int64
+		hash string
+	}
+	report := &DecisionReport{Since: since}
+	executions := make(map[key]bool)
+	blocked := make(map[key]bool)
+	wouldBlock := make(map[key]bool)
+	seen := make(map[string]bool) // policy, decision, execution
+	stats := make(map[string]*PolicyStats)
+
+	for _, d := range l.Decisions(since) {
+		k := key{d.Timestamp.UnixNano(), d.InputsHash}
+		executions[k] = true
+		if d.Policy == "" {
+			continue
+		}
+		if d.Decision == DecisionDeny {
+			blocked[k] = true
+		}
+		if d.WouldBlock {
+			wouldBlock[k] = true
+		}
+		id := fmt.Sprintf("%s|%s|%t|%s|%s", d.Policy, d.Decision, d.WouldBlock, d.Timestamp.Format(time.RFC3339Nano), d.InputsHash)
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		s, ok := stats[d.Policy]
+		if !ok {
+			s = &PolicyStats{Policy: d.Policy}
+			stats[d.Policy] = s
+		}
+		switch d.Decision {
+		case DecisionDeny:
+			s.Blocked++
+		case DecisionWarn:
+			s.Warned++
+		case DecisionAudit:
+			if d.WouldBlock {
+				s.WouldBlock++
+			}
+		case DecisionSuppressed:
+			s.Suppressed++
+		}
+	}
+
+	report.Executions = len(executions)
+	report.Blocked = len(blocked)
+	report.WouldBlock = len(wouldBlock)
+	for _, s := range stats {
+		report.Policies = append(report.Policies, s)
+	}
+	sort.Slice(report.Policies, func(i, j int) bool {
+		a, b := report.Policies[i], report.Policies[j]
+		if a.Blocked+a.WouldBlock != b.Blocked+b.WouldBlock {
+			return a.Blocked+a.WouldBlock > b.Blocked+b.WouldBlock
+		}
+		return a.Policy < b.Policy
+	})
+	return report
+}
+
+// Render formats the report for display.
+func (r *DecisionReport) Render() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("📊 POLICY DECISIONS since %s\n", r.Since.Format("2006-01-02 15:04")))
+	b.WriteString("─────────────────────────────────────────\n")
+	b.WriteString(fmt.Sprintf("  Executions: %d | blocked: %d | audit mode would block: %d\n", r.Executions, r.Blocked, r.WouldBlock))
+	if len(r.Policies) == 0 {
+		b.WriteString("  (no violations recorded)\n")
+		return b.String()
+	}
+	b.WriteString(fmt.Sprintf("\n  %-28s %8s %12s %7s %10s\n", "POLICY", "BLOCKED", "WOULD BLOCK", "WARNED", "SUPPRESSED"))
+	for _, s := range r.Policies {
+		b.WriteString(fmt.Sprintf("  %-28s %8d %12d %7d %10d\n", s.Policy, s.Blocked, s.WouldBlock, s.Warned, s.Suppressed))
+	}
+	return b.String()
+}
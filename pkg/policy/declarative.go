@@ -0,0 +1,303 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/parth14193/ownbot/pkg/core"
+	"github.com/parth14193/ownbot/pkg/expr"
+)
+
+// Declarative policy files are YAML documents holding either a single policy
+// or a list under "policies":
+//
+//	policies:
+//	  - name: no_internet_ssh
+//	    description: SSH and RDP must not be reachable from the internet
+//	    enforcement: deny
+//	    severity: CRITICAL
+//	    applies_to: ["aws.sg.*"]
+//	    environments: [production]
+//	    rule: params.cidr == "0.0.0.0/0" && params.port in [22, 3389]
+//	    message: "port {{ params.port }} is open to the internet"
+//
+// Rules are expressions (see package expr) over skill, params and env. The
+// policy is violated when the rule evaluates to true.
+
+// policySpec is the on-disk form of a declarative policy.
+type policySpec struct {
+	Name         string   `yaml:"name"`
+	Description  string   `yaml:"description"`
+	Enforcement  string   `yaml:"enforcement"`
+	Severity     string   `yaml:"severity"`
+	AppliesTo    []string `yaml:"applies_to"`
+	Environments []string `yaml:"environments"`
+	Rule         string   `yaml:"rule"`
+	Message      string   `yaml:"message"`
+}
+
+var policySpecFields = map[string]bool{
+	"name": true, "description": true, "enforcement": true, "severity": true,
+	"applies_to": true, "environments": true, "rule": true, "message": true,
+}
+
+// FileError is a validation error located in a policy file.
+type FileError struct {
+	File string
+	Line int
+	Msg  string
+}
+
+func (e *FileError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Msg)
+}
+
+// RuleEnv builds the environment that policy rule expressions are evaluated against.
+func RuleEnv(skill *core.Skill, params map[string]interface{}, env string) map[string]interface{} {
+	return (&Input{Skill: skill, Params: params, Environment: env}).Document()
+}
+
+// LoadDir loads every .yaml/.yml policy file under dir, except *_test.yaml
+// fixtures, and registers the policies. Nothing is registered if any file fails validation; the returned
+// error lists every problem with its file and line.
+func (e *Engine) LoadDir(dir string) ([]*Policy, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ext := filepath.Ext(path); !d.IsDir() && (ext == ".yaml" || ext == ".yml") && !IsFixtureFile(path) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy directory: %w", err)
+	}
+	sort.Strings(files)
+
+	var loaded []*Policy
+	var errs []error
+	for _, path := range files {
+		policies, err := LoadFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		loaded = append(loaded, policies...)
+	}
+	return e.registerLoaded(loaded, errs)
+}
+
+// registerLoaded registers policies loaded from files, reporting names that
+// are already taken. Nothing is registered if errs is non-empty or any name
+// collides.
+func (e *Engine) registerLoaded(loaded []*Policy, errs []error) ([]*Policy, error) {
+	policies, err := e.validateLoaded(loaded, errs)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range policies {
+		e.Register(p)
+	}
+	return policies, nil
+}
+
+// validateLoaded checks parsed policies against the registered ones and
+// returns those that can be registered, or every problem found.
+func (e *Engine) validateLoaded(loaded []*Policy, errs []error) ([]*Policy, error) {
+	var policies []*Policy
+	seen := make(map[string]string)
+	for _, p := range e.policies {
+		seen[p.Name] = "registered"
+		if p.Source != "" {
+			seen[p.Name] = p.Source
+		}
+	}
+	for _, p := range loaded {
+		if prev, ok := seen[p.Name]; ok {
+			errs = append(errs, fmt.Errorf("%s: duplicate policy '%s' (already defined in %s)", p.Source, p.Name, prev))
+			continue
+		}
+		seen[p.Name] = p.Source
+		if err := e.checkOverride(p); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.Source, err))
+			continue
+		}
+		policies = append(policies, p)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return policies, nil
+}
+
+// LoadFile reads and validates a declarative policy file.
+func LoadFile(path string) ([]*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+	return ParsePolicies(path, data)
+}
+
+// ParsePolicies parses declarative policies from YAML. name is used in error
+// messages and as the policies' Source.
+func ParsePolicies(name string, data []byte) ([]*Policy, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, &FileError{File: name, Line: root.Line, Msg: "expected a policy mapping or a 'policies' list"}
+	}
+
+	items := []*yaml.Node{root}
+	if list := mappingValue(root, "policies"); list != nil {
+		if list.Kind != yaml.SequenceNode {
+			return nil, &FileError{File: name, Line: list.Line, Msg: "'policies' must be a list"}
+		}
+		items = list.Content
+	}
+
+	var policies []*Policy
+	var errs []error
+	for _, item := range items {
+		p, itemErrs := compileSpec(name, item)
+		errs = append(errs, itemErrs...)
+		if p != nil {
+			policies = append(policies, p)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return policies, nil
+}
+
+func compileSpec(file string, node *yaml.Node) (*Policy, []error) {
+	fail := func(n *yaml.Node, format string, args ...interface{}) error {
+		return &FileError{File: file, Line: n.Line, Msg: fmt.Sprintf(format, args...)}
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, []error{fail(node, "policy must be a mapping")}
+	}
+
+	var errs []error
+	for i := 0; i < len(node.Content); i += 2 {
+		if key := node.Content[i]; !policySpecFields[key.Value] {
+			errs = append(errs, fail(key, "unknown field '%s'", key.Value))
+		}
+	}
+
+	var spec policySpec
+	if err := node.Decode(&spec); err != nil {
+		return nil, append(errs, fmt.Errorf("%s: %w", file, err))
+	}
+
+	if spec.Name == "" {
+		errs = append(errs, fail(node, "policy name is required"))
+	}
+
+	enforcement := EnforcementLevel(strings.ToLower(spec.Enforcement))
+	switch enforcement {
+	case "":
+		enforcement = EnforcementWarn
+	case EnforcementWarn, EnforcementDeny:
+	default:
+		errs = append(errs, fail(mappingValue(node, "enforcement"), "unknown enforcement '%s' (want warn or deny)", spec.Enforcement))
+	}
+
+	severity := Severity(strings.ToUpper(spec.Severity))
+	switch severity {
+	case "":
+		severity = SeverityWarning
+	case SeverityInfo, SeverityWarning, SeverityCritical:
+	default:
+		errs = append(errs, fail(mappingValue(node, "severity"), "unknown severity '%s' (want INFO, WARNING or CRITICAL)", spec.Severity))
+	}
+
+	var rule *expr.Expr
+	if ruleNode := mappingValue(node, "rule"); ruleNode == nil || strings.TrimSpace(spec.Rule) == "" {
+		errs = append(errs, fail(node, "policy '%s': rule is required", spec.Name))
+	} else {
+		var err error
+		rule, err = expr.Parse(spec.Rule)
+		if err != nil {
+			errs = append(errs, &FileError{File: file, Line: ruleLine(ruleNode, spec.Rule, err), Msg: fmt.Sprintf("policy '%s': rule: %v", spec.Name, err)})
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	message := spec.Message
+	description := spec.Description
+	if description == "" {
+		description = spec.Rule
+	}
+	check := func(skill *core.Skill, params map[string]interface{}, env string) (bool, string, error) {
+		vars := RuleEnv(skill, params, env)
+		violated, err := rule.EvalBool(vars)
+		if err != nil || !violated {
+			return false, "", err
+		}
+		if message != "" {
+			return true, expr.Interpolate(message, vars), nil
+		}
+		return true, description, nil
+	}
+	return &Policy{
+		Name:         spec.Name,
+		Description:  description,
+		Enforcement:  enforcement,
+		Severity:     severity,
+		AppliesTo:    spec.AppliesTo,
+		Environments: spec.Environments,
+		Rule:         spec.Rule,
+		Source:       fmt.Sprintf("%s:%d", file, node.Line),
+		CheckFunc: func(skill *core.Skill, params map[string]interface{}, env string) (bool, string) {
+			violated, reason, err := check(skill, params, env)
+			if err != nil {
+				return true, fmt.Sprintf("rule evaluation failed: %v", err)
+			}
+			return violated, reason
+		},
+		ruleCheck: check,
+	}, nil
+}
+
+// ruleLine maps a syntax error in a rule back to its line in the file,
+// accounting for multi-line block scalars.
+func ruleLine(n *yaml.Node, rule string, err error) int {
+	var se *expr.SyntaxError
+	if !errors.As(err, &se) {
+		return n.Line
+	}
+	line := n.Line + strings.Count(rule[:se.Offset], "\n")
+	if n.Style == yaml.LiteralStyle || n.Style == yaml.FoldedStyle {
+		line++ // block content starts on the line after the indicator
+	}
+	return line
+}
+
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package policy
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/parth14193/ownbot/pkg/config"
+	"github.com/parth14193/ownbot/pkg/core"
+	"github.com/parth14193/ownbot/pkg/environment"
+)
+
+// Exception suppresses violations of one policy for a specific resource, for
+// a limited time. Unlike an Exemption, which downgrades a policy for whole
+// environments, an exception is owned, ticketed and expires.
+type Exception struct {
+	ID           string    `json:"id"`
+	Policy       string    `json:"policy"`
+	Skill        string    `json:"skill"`        // skill name pattern; empty matches any
+	Resource     string    `json:"resource"`     // resource identifier, glob allowed
+	Environments []string  `json:"environments"` // environments or tiers; empty matches any
+	Owner        string    `json:"owner"`
+	Reason       string    `json:"reason"`
+	Ticket       string    `json:"ticket,omitempty"`
+	Expires      time.Time `json:"expires"`
+}
+
+// Expired reports whether the exception no longer applies at the given time.
+func (x *Exception) Expired(at time.Time) bool {
+	return !at.Before(x.Expires)
+}
+
+// ExceptionRegistry holds policy exceptions.
+type ExceptionRegistry struct {
+	exceptions []*Exception
+	classifier *environment.Classifier
+	now        func() time.Time
+}
+
+// NewExceptionRegistry creates an empty registry.
+func NewExceptionRegistry() *ExceptionRegistry {
+	return &ExceptionRegistry{
+		classifier: environment.DefaultClassifier(),
+		now:        time.Now,
+	}
+}
+
+// SetClassifier replaces the environment tier classifier used for matching.
+func (r *ExceptionRegistry) SetClassifier(classifier *environment.Classifier) {
+	r.classifier = classifier
+}
+
+// SetClock overrides the time source (for testing).
+func (r *ExceptionRegistry) SetClock(now func() time.Time) {
+	r.now = now
+}
+
+// Add validates and registers an exception. Policy, resource, owner, reason
+// and expiry are required; a missing ID is generated.
+func (r *ExceptionRegistry) Add(x *Exception) error {
+	var missing []string
+	if x.Policy == "" {
+		missing = append(missing, "policy")
+	}
+	if x.Resource == "" {
+		missing = append(missing, "resource")
+	}
+	if x.Owner == "" {
+		missing = append(missing, "owner")
+	}
+	if strings.TrimSpace(x.Reason) == "" {
+		missing = append(missing, "reason")
+	}
+	if x.Expires.IsZero() {
+		missing = append(missing, "expires")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("exception for '%s': missing %s", x.Policy, strings.Join(missing, ", "))
+	}
+	if _, err := path.Match(x.Resource, ""); err != nil {
+		return fmt.Errorf("exception for '%s': invalid resource pattern '%s': %w", x.Policy, x.Resource, err)
+	}
+	if x.ID == "" {
+		x.ID = fmt.Sprintf("EXC-%03d", len(r.exceptions)+1)
+	}
+	for _, existing := range r.exceptions {
+		if existing.ID == x.ID {
+			return fmt.Errorf("duplicate exception id '%s'", x.ID)
+		}
+	}
+	r.exceptions = append(r.exceptions, x)
+	return nil
+}
+
+// List returns all exceptions ordered by expiry.
+func (r *ExceptionRegistry) List() []*Exception {
+	out := append([]*Exception(nil), r.exceptions...)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Expires.Before(out[j].Expires) })
+	return out
+}
+
+// Expiring returns unexpired exceptions that expire within the given window,
+// ordered by expiry.
+func (r *ExceptionRegistry) Expiring(within time.Duration) []*Exception {
+	now := r.now()
+	var out []*Exception
+	for _, x := range r.List() {
+		if !x.Expired(now) && x.Expires.Before(now.Add(within)) {
+			out = append(out, x)
+		}
+	}
+	return out
+}
+
+// Match returns the active exception covering a violation, or nil.
+func (r *ExceptionRegistry) Match(v Violation) *Exception {
+	now := r.now()
+	for _, x := range r.exceptions {
+		if x.Policy != v.PolicyName || x.Expired(now) {
+			continue
+		}
+		if x.Skill != "" && !matchSkillPattern(v.SkillName, x.Skill) {
+			continue
+		}
+		if ok, _ := path.Match(x.Resource, v.Resource); !ok || v.Resource == "" {
+			continue
+		}
+		if len(x.Environments) > 0 && !r.classifier.MatchesAny(v.Environment, x.Environments) {
+			continue
+		}
+		return x
+	}
+	return nil
+}
+
+// Render formats exceptions for display, flagging those already expired.
+func (r *ExceptionRegistry) Render(exceptions []*Exception) string {
+	var b strings.Builder
+	now := r.now()
+	b.WriteString(fmt.Sprintf("🔕 POLICY EXCEPTIONS (%d)\n", len(exceptions)))
+	b.WriteString("─────────────────────────────────────────\n")
+	if len(exceptions) == 0 {
+		b.WriteString("  (none)\n")
+	}
+	for _, x := range exceptions {
+		status := fmt.Sprintf("expires in %s", formatDays(x.Expires.Sub(now)))
+		if x.Expired(now) {
+			status = "EXPIRED"
+		}
+		b.WriteString(fmt.Sprintf("  %s  %s → %s [%s]\n", x.ID, x.Policy, x.Resource, status))
+		scope := x.Skill
+		if scope == "" {
+			scope = "*"
+		}
+		if len(x.Environments) > 0 {
+			scope += " in " + strings.Join(x.Environments, ", ")
+		}
+		b.WriteString(fmt.Sprintf("      scope: %s | owner: %s | expires: %s\n", scope, x.Owner, FormatExpiry(x.Expires)))
+		if x.Ticket != "" {
+			b.WriteString(fmt.Sprintf("      ticket: %s | reason: %s\n", x.Ticket, x.Reason))
+		} else {
+			b.WriteString(fmt.Sprintf("      reason: %s\n", x.Reason))
+		}
+	}
+	return b.String()
+}
+
+func formatDays(d time.Duration) string {
+	days := int(d.Hours() / 24)
+	if days < 1 {
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", days)
+}
+
+// FormatExpiry formats an expiry as the last date it is valid on, so a
+// date-only expiry prints as configured.
+func FormatExpiry(t time.Time) string {
+	return t.Add(-time.Nanosecond).Format("2006-01-02")
+}
+
+// ParseExpiry parses an exception expiry: a date (valid through the end of
+// that day, UTC) or an RFC 3339 timestamp.
+func ParseExpiry(s string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t.AddDate(0, 0, 1), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid expiry '%s' (want YYYY-MM-DD or RFC 3339)", s)
+	}
+	return t, nil
+}
+
+// LoadConfig registers exceptions from configuration.
+func (r *ExceptionRegistry) LoadConfig(exceptions []*config.PolicyException) error {
+	for i, ec := range exceptions {
+		if ec == nil {
+			continue
+		}
+		x := &Exception{
+			ID:           ec.ID,
+			Policy:       ec.Policy,
+			Skill:        ec.Skill,
+			Resource:     ec.Resource,
+			Environments: ec.Environments,
+			Owner:        ec.Owner,
+			Reason:       ec.Reason,
+			Ticket:       ec.Ticket,
+		}
+		if ec.Expires != "" {
+			t, err := ParseExpiry(ec.Expires)
+			if err != nil {
+				return fmt.Errorf("exceptions[%d]: %w", i, err)
+			}
+			x.Expires = t
+		}
+		if err := r.Add(x); err != nil {
+			return fmt.Errorf("exceptions[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// resourceKeys are the params that identify the resource an action targets,
+// in order of precedence.
+var resourceKeys = []string{
+	"resource_id", "arn", "bucket", "bucket_name", "group_id", "sg_id", "instance_id", "function_name",
+	"vpc_id", "trail_name", "log_group", "deployment", "vm_name", "name",
+}
+
+// ResourceID returns the identifier of the resource targeted by params, or ""
+// when none is given. Only target params count: the first of resourceKeys
+// the skill declares as an input, or, for skills without declared inputs,
+// the first present. Params the skill does not take cannot redirect an
+// exception to another resource.
+func ResourceID(skill *core.Skill, params map[string]interface{}) string {
+	declared := make(map[string]bool)
+	if skill != nil {
+		for _, in := range skill.Inputs {
+			declared[in.Name] = true
+		}
+	}
+	for _, key := range resourceKeys {
+		if len(declared) > 0 && !declared[key] {
+			continue
+		}
+		if v, ok := params[key]; ok && v != nil {
+			if s := fmt.Sprintf("%v", v); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,306 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/parth14193/ownbot/pkg/core"
+)
+
+// Policy fixtures are YAML files named *_test.yaml (or *_test.yml) that
+// describe actions and the policy outcome expected for each:
+//
+//	tests:
+//	  - name: internet SSH is denied in production
+//	    skill: aws.sg.modify
+//	    env: production
+//	    now: 2026-10-14T10:00:00Z
+//	    params: {cidr: 0.0.0.0/0, port: 22}
+//	    expect:
+//	      violations: [no_wide_open_sg]
+//	      warnings: [no_direct_prod_access]
+//	      reasons:
+//	        no_wide_open_sg: "port 22"
+//
+// Expected lists hold policy names and are compared order-insensitively; an
+// omitted list expects nothing. Suppressed and audited are only checked when
+// given. now (RFC 3339) fixes the evaluation time for time-based policies such
+// as production_deploy_window; without it the engine's clock is used.
+
+// Fixture is a single policy test case.
+type Fixture struct {
+	Name      string                 `yaml:"name"`
+	Skill     string                 `yaml:"skill"`
+	RiskLevel string                 `yaml:"risk_level"`
+	Env       string                 `yaml:"env"`
+	User      string                 `yaml:"user"`
+	Now       string                 `yaml:"now"` // RFC 3339 evaluation time
+	Params    map[string]interface{} `yaml:"params"`
+	Expect    Expectation            `yaml:"expect"`
+	Source    string                 `yaml:"-"` // file:line
+}
+
+// Expectation is the policy outcome a fixture expects.
+type Expectation struct {
+	Violations []string          `yaml:"violations"`
+	Warnings   []string          `yaml:"warnings"`
+	Suppressed []string          `yaml:"suppressed"`
+	Audited    []string          `yaml:"audited"`
+	Reasons    map[string]string `yaml:"reasons"` // policy -> expected reason substring
+}
+
+var fixtureFields = map[string]bool{
+	"name": true, "skill": true, "risk_level": true, "env": true, "user": true, "now": true, "params": true, "expect": true,
+}
+
+var expectationFields = map[string]bool{
+	"violations": true, "warnings": true, "suppressed": true, "audited": true, "reasons": true,
+}
+
+// FixtureResult is the outcome of running one fixture.
+type FixtureResult struct {
+	Fixture *Fixture
+	Passed  bool
+	Diff    []string // "- expected …" / "+ unexpected …" lines
+}
+
+// IsFixtureFile reports whether path names a policy fixture file.
+func IsFixtureFile(path string) bool {
+	base := filepath.Base(path)
+	return strings.HasSuffix(base, "_test.yaml") || strings.HasSuffix(base, "_test.yml")
+}
+
+// LoadFixtures reads every fixture file under dir.
+func LoadFixtures(dir string) ([]*Fixture, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && IsFixtureFile(path) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture directory: %w", err)
+	}
+	sort.Strings(files)
+
+	var fixtures []*Fixture
+	var errs []error
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read fixture file: %w", err))
+			continue
+		}
+		loaded, err := ParseFixtures(path, data)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		fixtures = append(fixtures, loaded...)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return fixtures, nil
+}
+
+// ParseFixtures parses fixtures from a YAML document with a "tests" list.
+func ParseFixtures(name string, data []byte) ([]*Fixture, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	root := doc.Content[0]
+	list := mappingValue(root, "tests")
+	if root.Kind != yaml.MappingNode || list == nil || list.Kind != yaml.SequenceNode {
+		return nil, &FileError{File: name, Line: root.Line, Msg: "expected a 'tests' list"}
+	}
+
+	var fixtures []*Fixture
+	var errs []error
+	for i, item := range list.Content {
+		fail := func(n *yaml.Node, format string, args ...interface{}) {
+			errs = append(errs, &FileError{File: name, Line: n.Line, Msg: fmt.Sprintf(format, args...)})
+		}
+		if item.Kind != yaml.MappingNode {
+			fail(item, "test must be a mapping")
+			continue
+		}
+		unknownKeys(item, fixtureFields, fail)
+		if expect := mappingValue(item, "expect"); expect != nil && expect.Kind == yaml.MappingNode {
+			unknownKeys(expect, expectationFields, fail)
+		}
+		var f Fixture
+		if err := item.Decode(&f); err != nil {
+			errs = append(errs, fmt.Errorf("%s:%d: %w", name, item.Line, err))
+			continue
+		}
+		if f.Name == "" {
+			f.Name = fmt.Sprintf("test %d", i+1)
+		}
+		if f.Skill == "" {
+			fail(item, "test '%s': skill is required", f.Name)
+		}
+		if f.RiskLevel != "" {
+			if _, err := core.ParseRiskLevel(strings.ToUpper(f.RiskLevel)); err != nil {
+				fail(item, "test '%s': %v", f.Name, err)
+			}
+		}
+		if f.Now != "" {
+			if _, err := time.Parse(time.RFC3339, f.Now); err != nil {
+				fail(item, "test '%s': now must be an RFC 3339 time, e.g. 2026-10-14T10:00:00Z", f.Name)
+			}
+		}
+		f.Source = fmt.Sprintf("%s:%d", name, item.Line)
+		fixtures = append(fixtures, &f)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return fixtures, nil
+}
+
+func unknownKeys(n *yaml.Node, known map[string]bool, fail func(*yaml.Node, string, ...interface{})) {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if key := n.Content[i]; !known[key.Value] {
+			fail(key, "unknown field '%s'", key.Value)
+		}
+	}
+}
+
+// RunFixtures evaluates each fixture against the engine. resolve maps a
+// fixture's skill name to a skill; unknown skills may be synthesised. A
+// fixture's now replaces the engine clock while it is evaluated.
+func (e *Engine) RunFixtures(fixtures []*Fixture, resolve func(name string) *core.Skill) []*FixtureResult {
+	clock := e.now
+	defer func() { e.now = clock }()
+
+	results := make([]*FixtureResult, 0, len(fixtures))
+	for _, f := range fixtures {
+		e.now = clock
+		if at, err := time.Parse(time.RFC3339, f.Now); err == nil {
+			e.now = func() time.Time { return at }
+		}
+		skill := resolve(f.Skill)
+		if f.RiskLevel != "" {
+			copied := *skill
+			copied.RiskLevel, _ = core.ParseRiskLevel(strings.ToUpper(f.RiskLevel))
+			skill = &copied
+		}
+		env := f.Env
+		if env == "" {
+			env = "staging"
+		}
+		got := e.EvaluateInput(&Input{Skill: skill, Params: f.Params, Environment: env, User: f.User})
+
+		var diff []string
+		diff = append(diff, diffNames("violation", f.Expect.Violations, got.Violations)...)
+		diff = append(diff, diffNames("warning", f.Expect.Warnings, got.Warnings)...)
+		if f.Expect.Suppressed != nil {
+			diff = append(diff, diffNames("suppressed", f.Expect.Suppressed, got.Suppressed)...)
+		}
+		if f.Expect.Audited != nil {
+			diff = append(diff, diffNames("audited", f.Expect.Audited, got.Audited)...)
+		}
+		for _, policyName := range sortedStringKeys(f.Expect.Reasons) {
+			want := f.Expect.Reasons[policyName]
+			var reasons []string
+			for _, v := range append(append(append(append([]Violation{}, got.Violations...), got.Warnings...), got.Suppressed...), got.Audited...) {
+				if v.PolicyName == policyName {
+					reasons = append(reasons, v.Reason)
+				}
+			}
+			matched := false
+			for _, r := range reasons {
+				if strings.Contains(r, want) {
+					matched = true
+				}
+			}
+			if !matched {
+				diff = append(diff, fmt.Sprintf("- reason for %s containing %q", policyName, want))
+				for _, r := range reasons {
+					diff = append(diff, fmt.Sprintf("+ reason for %s: %q", policyName, r))
+				}
+			}
+		}
+		results = append(results, &FixtureResult{Fixture: f, Passed: len(diff) == 0, Diff: diff})
+	}
+	return results
+}
+
+// diffNames compares expected policy names with actual violations, counting
+// duplicates.
+func diffNames(kind string, want []string, got []Violation) []string {
+	counts := make(map[string]int)
+	for _, name := range want {
+		counts[name]++
+	}
+	for _, v := range got {
+		counts[v.PolicyName]--
+	}
+	var diff []string
+	for _, name := range sortedIntKeys(counts) {
+		for n := counts[name]; n > 0; n-- {
+			diff = append(diff, fmt.Sprintf("- expected %s: %s", kind, name))
+		}
+		for n := counts[name]; n < 0; n++ {
+			diff = append(diff, fmt.Sprintf("+ unexpected %s: %s", kind, name))
+		}
+	}
+	return diff
+}
+
+func sortedIntKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedStringKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// RenderFixtureResults formats fixture results with diffs for failures.
+func RenderFixtureResults(results []*FixtureResult) string {
+	var b strings.Builder
+	passed := 0
+	b.WriteString("🧪 POLICY TESTS\n")
+	b.WriteString("─────────────────────────────────────────\n")
+	for _, r := range results {
+		if r.Passed {
+			passed++
+			b.WriteString(fmt.Sprintf("  ✅ %s\n", r.Fixture.Name))
+			continue
+		}
+		b.WriteString(fmt.Sprintf("  ❌ %s (%s)\n", r.Fixture.Name, r.Fixture.Source))
+		for _, line := range r.Diff {
+			b.WriteString(fmt.Sprintf("      %s\n", line))
+		}
+	}
+	b.WriteString("─────────────────────────────────────────\n")
+	b.WriteString(fmt.Sprintf("%d passed, %d failed\n", passed, len(results)-passed))
+	return b.String()
+}
@@ -0,0 +1,86 @@
+package policy
+
+import (
+	"github.com/parth14193/ownbot/pkg/core"
+)
+
+// Input is everything known about an action when policies are evaluated.
+type Input struct {
+	Skill       *core.Skill
+	Params      map[string]interface{}
+	Environment string
+	User        string
+	Safety      *core.SafetyReport // nil when not yet evaluated
+	Plan        *Plan              // Terraform plan, when the action applies one
+	Manifests   []*K8sObject       // rendered Kubernetes objects, when the action deploys them
+}
+
+// Backend evaluates policies that are not written as Go CheckFuncs, such as
+// Rego modules. Returned violations carry their own enforcement and severity.
+type Backend interface {
+	Name() string
+	Evaluate(in *Input) ([]Violation, error)
+}
+
+// Document returns the input as a generic document:
+//
+//	skill:       name, description, provider, category, risk_level, requires_confirmation, rollback_supported
+//	params:      skill parameters
+//	env, environment: target environment
+//	user:        acting user
+//	safety:      risk_level, blast_radius, affected_resources, downstream_resources,
+//	             requires_confirmation, rollback_available, dry_run_required,
+//	             environment_warning, freeze_warning (null if not evaluated)
+//	plan:        resource_changes[] with address, type, name, actions, after (null without a plan)
+//	manifests:   kind, name, namespace and the full object for each rendered Kubernetes object
+func (in *Input) Document() map[string]interface{} {
+	params := in.Params
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+	doc := map[string]interface{}{
+		"params":      params,
+		"env":         in.Environment,
+		"environment": in.Environment,
+		"user":        in.User,
+		"safety":      nil,
+		"plan":        nil,
+		"manifests":   manifestsDocument(in.Manifests),
+	}
+	if in.Plan != nil {
+		doc["plan"] = in.Plan.document()
+	}
+	if s := in.Skill; s != nil {
+		doc["skill"] = map[string]interface{}{
+			"name":                  s.Name,
+			"description":           s.Description,
+			"provider":              string(s.Provider),
+			"category":              string(s.Category),
+			"risk_level":            s.RiskLevel.String(),
+			"requires_confirmation": s.RequiresConfirmation,
+			"rollback_supported":    s.Rollback.Supported,
+		}
+	}
+	if r := in.Safety; r != nil {
+		doc["safety"] = map[string]interface{}{
+			"risk_level":            r.RiskLevel.String(),
+			"blast_radius":          r.BlastRadius,
+			"affected_resources":    stringsToList(r.AffectedResources),
+			"downstream_resources":  stringsToList(r.DownstreamResources),
+			"requires_confirmation": r.RequiresConfirmation,
+			"rollback_available":    r.RollbackAvailable,
+			"dry_run_required":      r.DryRunRequired,
+			"environment_warning":   r.EnvironmentWarning,
+			"freeze_warning":        r.FreezeWarning,
+		}
+	}
+	return doc
+}
+
+func stringsToList(items []string) []interface{} {
+	out := make([]interface{}, len(items))
+	for i, s := range items {
+		out[i] = s
+	}
+	return out
+}
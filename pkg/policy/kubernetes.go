@@ -0,0 +1,331 @@
+package policy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// K8sObject is one object from rendered Kubernetes YAML (kubectl manifests or
+// `helm template` output).
+type K8sObject struct {
+	Kind      string
+	Name      string
+	Namespace string
+	Object    map[string]interface{} // the full decoded object
+	Source    string                 // file:line
+}
+
+// Address identifies the object as Kind/namespace/name, or Kind/name when it
+// has no namespace. Violations use it as their resource.
+func (o *K8sObject) Address() string {
+	if o.Namespace == "" {
+		return o.Kind + "/" + o.Name
+	}
+	return o.Kind + "/" + o.Namespace + "/" + o.Name
+}
+
+// ManifestCheckFunc inspects one Kubernetes object and returns a reason for
+// every violation found.
+type ManifestCheckFunc func(obj *K8sObject) []string
+
+// ParseManifests parses multi-document Kubernetes YAML. Empty documents are
+// skipped and List objects are flattened into their items.
+func ParseManifests(name string, data []byte) ([]*K8sObject, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	var objects []*K8sObject
+	for {
+		var node yaml.Node
+		err := dec.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		var doc map[string]interface{}
+		if err := node.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", name, node.Line, err)
+		}
+		if doc == nil {
+			continue
+		}
+		source := fmt.Sprintf("%s:%d", name, node.Line)
+		if kind, _ := doc["kind"].(string); strings.HasSuffix(kind, "List") {
+			for _, item := range asList(doc["items"]) {
+				if m, ok := item.(map[string]interface{}); ok {
+					objects = append(objects, newK8sObject(m, source))
+				}
+			}
+			continue
+		}
+		obj := newK8sObject(doc, source)
+		if obj.Kind == "" {
+			return nil, &FileError{File: name, Line: node.Line, Msg: "object has no kind"}
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+func newK8sObject(m map[string]interface{}, source string) *K8sObject {
+	meta := asMap(m["metadata"])
+	kind, _ := m["kind"].(string)
+	name, _ := meta["name"].(string)
+	namespace, _ := meta["namespace"].(string)
+	return &K8sObject{Kind: kind, Name: name, Namespace: namespace, Object: m, Source: source}
+}
+
+// LoadManifests reads Kubernetes YAML from a file, or from every .yaml and
+// .yml file under a directory.
+func LoadManifests(p string) ([]*K8sObject, error) {
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifests: %w", err)
+	}
+	files := []string{p}
+	if info.IsDir() {
+		files = nil
+		err := filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if ext := filepath.Ext(path); !d.IsDir() && (ext == ".yaml" || ext == ".yml") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read manifest directory: %w", err)
+		}
+		sort.Strings(files)
+	}
+
+	var objects []*K8sObject
+	var errs []error
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read manifest file: %w", err))
+			continue
+		}
+		parsed, err := ParseManifests(file, data)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		objects = append(objects, parsed...)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return objects, nil
+}
+
+// manifestsDocument returns the objects in the shape policy rules and Rego
+// modules see under input.manifests.
+func manifestsDocument(objects []*K8sObject) []interface{} {
+	out := make([]interface{}, 0, len(objects))
+	for _, o := range objects {
+		out = append(out, map[string]interface{}{
+			"kind":      o.Kind,
+			"name":      o.Name,
+			"namespace": o.Namespace,
+			"object":    o.Object,
+		})
+	}
+	return out
+}
+
+// podSpec returns the pod spec of a workload object, or nil for objects that
+// do not run pods.
+func podSpec(o *K8sObject) map[string]interface{} {
+	spec := asMap(o.Object["spec"])
+	switch o.Kind {
+	case "Pod":
+		return spec
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "ReplicationController", "Job":
+		return asMap(asMap(spec["template"])["spec"])
+	case "CronJob":
+		job := asMap(asMap(spec["jobTemplate"])["spec"])
+		return asMap(asMap(job["template"])["spec"])
+	}
+	return nil
+}
+
+// container is a container of a pod spec; init is true for init containers.
+type container struct {
+	name string
+	init bool
+	spec map[string]interface{}
+}
+
+func containers(o *K8sObject) []container {
+	pod := podSpec(o)
+	if pod == nil {
+		return nil
+	}
+	var out []container
+	for _, key := range []string{"initContainers", "containers"} {
+		for _, c := range asList(pod[key]) {
+			m := asMap(c)
+			name, _ := m["name"].(string)
+			out = append(out, container{name: name, init: key == "initContainers", spec: m})
+		}
+	}
+	return out
+}
+
+// ── Manifest checks for built-in policies ──────────────────────
+
+func privilegedManifest(o *K8sObject) []string {
+	var reasons []string
+	for _, c := range containers(o) {
+		if v, _ := asMap(c.spec["securityContext"])["privileged"].(bool); v {
+			reasons = append(reasons, fmt.Sprintf("%s: container '%s' runs privileged", o.Address(), c.name))
+		}
+	}
+	return reasons
+}
+
+func resourceLimitsManifest(o *K8sObject) []string {
+	var reasons []string
+	for _, c := range containers(o) {
+		limits := asMap(asMap(c.spec["resources"])["limits"])
+		var missing []string
+		for _, r := range []string{"cpu", "memory"} {
+			if _, ok := limits[r]; !ok {
+				missing = append(missing, r)
+			}
+		}
+		if len(missing) > 0 {
+			reasons = append(reasons, fmt.Sprintf("%s: container '%s' has no %s limit", o.Address(), c.name, strings.Join(missing, "/")))
+		}
+	}
+	return reasons
+}
+
+func latestTagManifest(o *K8sObject) []string {
+	var reasons []string
+	for _, c := range containers(o) {
+		image, _ := c.spec["image"].(string)
+		if image != "" && usesLatestTag(image) {
+			reasons = append(reasons, fmt.Sprintf("%s: container '%s' uses mutable image '%s' — pin a version or digest", o.Address(), c.name, image))
+		}
+	}
+	return reasons
+}
+
+func hostPathManifest(o *K8sObject) []string {
+	pod := podSpec(o)
+	var reasons []string
+	for _, v := range asList(pod["volumes"]) {
+		vol := asMap(v)
+		if hp, ok := vol["hostPath"]; ok {
+			name, _ := vol["name"].(string)
+			hostPath, _ := asMap(hp)["path"].(string)
+			reasons = append(reasons, fmt.Sprintf("%s: volume '%s' mounts host path '%s'", o.Address(), name, hostPath))
+		}
+	}
+	return reasons
+}
+
+func probesManifest(o *K8sObject) []string {
+	if o.Kind == "Job" || o.Kind == "CronJob" {
+		return nil // run-to-completion pods are not probed
+	}
+	var reasons []string
+	for _, c := range containers(o) {
+		if c.init {
+			continue
+		}
+		var missing []string
+		for _, probe := range []string{"livenessProbe", "readinessProbe"} {
+			if _, ok := c.spec[probe]; !ok {
+				missing = append(missing, probe)
+			}
+		}
+		if len(missing) > 0 {
+			reasons = append(reasons, fmt.Sprintf("%s: container '%s' has no %s", o.Address(), c.name, strings.Join(missing, " or ")))
+		}
+	}
+	return reasons
+}
+
+func allowedRegistriesManifest(allowed []string) ManifestCheckFunc {
+	return func(o *K8sObject) []string {
+		var reasons []string
+		for _, c := range containers(o) {
+			image, _ := c.spec["image"].(string)
+			if image != "" && !registryAllowed(image, allowed) {
+				reasons = append(reasons, fmt.Sprintf("%s: container '%s' image '%s' is not from an allowed registry (%s)", o.Address(), c.name, image, strings.Join(allowed, ", ")))
+			}
+		}
+		return reasons
+	}
+}
+
+// splitImage splits an image reference into its registry host, its
+// repository including the host (e.g. docker.io/library/nginx), and its tag.
+// Images without a registry come from Docker Hub.
+func splitImage(image string) (registry, repository, tag string) {
+	ref := image
+	if i := strings.Index(ref, "@"); i >= 0 {
+		ref = ref[:i]
+	}
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		ref, tag = ref[:i], ref[i+1:]
+	}
+	first, _, found := strings.Cut(ref, "/")
+	if found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		return first, ref, tag
+	}
+	if !found {
+		ref = "library/" + ref
+	}
+	return "docker.io", "docker.io/" + ref, tag
+}
+
+// usesLatestTag reports whether an image is untagged or tagged latest and
+// not pinned by digest.
+func usesLatestTag(image string) bool {
+	if strings.Contains(image, "@") {
+		return false
+	}
+	_, _, tag := splitImage(image)
+	return tag == "" || tag == "latest"
+}
+
+// registryAllowed reports whether an image comes from an allowed registry.
+// Entries are registry hosts (glob allowed, e.g. *.dkr.ecr.*.amazonaws.com)
+// or repository prefixes (e.g. ghcr.io/acme). An empty list allows all.
+func registryAllowed(image string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	registry, repository, _ := splitImage(image)
+	for _, entry := range allowed {
+		entry = strings.TrimSuffix(entry, "/")
+		if ok, _ := path.Match(entry, registry); ok {
+			return true
+		}
+		if repository == entry || strings.HasPrefix(repository, entry+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func asMap(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/parth14193/ownbot/pkg/core"
+	"github.com/parth14193/ownbot/pkg/environment"
 )
 
 // EnforcementLevel determines what happens when a policy is violated.
@@ -16,6 +17,10 @@ type EnforcementLevel string
 const (
 	EnforcementWarn EnforcementLevel = "warn" // Log warning but allow execution
 	EnforcementDeny EnforcementLevel = "deny" // Block execution
+
+	// EnforcementAudit is a per-policy shadow mode: violations are recorded
+	// with the enforcement they would have had, but never enforced.
+	EnforcementAudit EnforcementLevel = "audit"
 )
 
 // Severity classifies the impact of a policy violation.
@@ -29,13 +34,21 @@ const (
 
 // Policy defines an infrastructure guardrail rule.
 type Policy struct {
-	Name            string           `json:"name" yaml:"name"`
-	Description     string           `json:"description" yaml:"description"`
-	Enforcement     EnforcementLevel `json:"enforcement" yaml:"enforcement"`
-	Severity        Severity         `json:"severity" yaml:"severity"`
-	AppliesTo       []string         `json:"applies_to" yaml:"applies_to"`       // skill name patterns
-	Environments    []string         `json:"environments" yaml:"environments"`   // which envs this applies to
-	CheckFunc       PolicyCheckFunc  `json:"-" yaml:"-"`                         // the actual check function
+	Name          string            `json:"name" yaml:"name"`
+	Description   string            `json:"description" yaml:"description"`
+	Enforcement   EnforcementLevel  `json:"enforcement" yaml:"enforcement"`
+	Severity      Severity          `json:"severity" yaml:"severity"`
+	AppliesTo     []string          `json:"applies_to" yaml:"applies_to"`         // skill name patterns
+	Environments  []string          `json:"environments" yaml:"environments"`     // which envs this applies to
+	CheckFunc     PolicyCheckFunc   `json:"-" yaml:"-"`                           // the actual check function
+	Rule          string            `json:"rule,omitempty" yaml:"rule,omitempty"` // expression for declarative policies
+	Source        string            `json:"source,omitempty" yaml:"-"`            // file:line for declarative policies
+	ResourceCheck ResourceCheckFunc `json:"-" yaml:"-"`                           // per-resource check for Terraform plans
+	ManifestCheck ManifestCheckFunc `json:"-" yaml:"-"`                           // per-object check for Kubernetes manifests
+
+	// ruleCheck evaluates a declarative rule, reporting evaluation errors
+	// so the engine can fail closed on them.
+	ruleCheck func(skill *core.Skill, params map[string]interface{}, env string) (bool, string, error)
 }
 
 // PolicyCheckFunc evaluates whether a policy is satisfied.
@@ -44,14 +57,18 @@ type PolicyCheckFunc func(skill *core.Skill, params map[string]interface{}, env
 
 // Violation represents a detected policy violation.
 type Violation struct {
-	PolicyName  string           `json:"policy_name"`
-	Description string           `json:"description"`
-	Severity    Severity         `json:"severity"`
-	Enforcement EnforcementLevel `json:"enforcement"`
-	Reason      string           `json:"reason"`
-	SkillName   string           `json:"skill_name"`
-	Environment string           `json:"environment"`
-	Timestamp   time.Time        `json:"timestamp"`
+	PolicyName   string           `json:"policy_name"`
+	Description  string           `json:"description"`
+	Severity     Severity         `json:"severity"`
+	Enforcement  EnforcementLevel `json:"enforcement"`
+	Reason       string           `json:"reason"`
+	SkillName    string           `json:"skill_name"`
+	Environment  string           `json:"environment"`
+	Timestamp    time.Time        `json:"timestamp"`
+	Exemption    string           `json:"exemption,omitempty"` // reason a blocking violation was downgraded
+	Resource     string           `json:"resource,omitempty"`  // identifier of the offending resource
+	SuppressedBy *Exception       `json:"suppressed_by,omitempty"`
+	Shadow       EnforcementLevel `json:"shadow,omitempty"` // enforcement an audited violation would have had
 }
 
 // EvaluationResult is the outcome of all policy checks for a single action.
@@ -59,6 +76,8 @@ type EvaluationResult struct {
 	Passed     bool        `json:"passed"`
 	Violations []Violation `json:"violations"`
 	Warnings   []Violation `json:"warnings"`
+	Suppressed []Violation `json:"suppressed,omitempty"` // covered by an active exception
+	Audited    []Violation `json:"audited,omitempty"`    // policies in audit mode; never enforced
 	Denied     bool        `json:"denied"`
 }
 
@@ -66,6 +85,35 @@ type EvaluationResult struct {
 type Engine struct {
 	policies        []*Policy
 	enforcementMode EnforcementLevel
+	classifier      *environment.Classifier
+	backends        []Backend
+	disabled        bool
+	envModes        map[string]EnforcementLevel // environment or tier name -> mode
+	overrides       map[string]Override
+	exemptions      []Exemption
+	exceptions      *ExceptionRegistry
+	now             func() time.Time
+}
+
+// Override adjusts the enforcement and/or severity of a named policy. Empty
+// fields keep the policy's own value. An overridden enforcement is final: it
+// is not raised by the global or environment mode. Lowering a DENY to warn
+// requires a reason, which is recorded on the violation as its exemption; a
+// downgrade without one is ignored. EnforcementAudit puts the policy in audit
+// mode: its violations are reported as Audited, with the enforcement they
+// would otherwise have had, and never block or warn.
+type Override struct {
+	Enforcement EnforcementLevel
+	Severity    Severity
+	Reason      string
+}
+
+// Exemption explicitly downgrades a policy to a warning in the listed
+// environments or tiers (all environments when empty).
+type Exemption struct {
+	Policy       string
+	Environments []string
+	Reason       string
 }
 
 // NewEngine creates a new PolicyEngine.
@@ -73,71 +121,351 @@ func NewEngine(enforcementMode EnforcementLevel) *Engine {
 	return &Engine{
 		policies:        []*Policy{},
 		enforcementMode: enforcementMode,
+		classifier:      environment.DefaultClassifier(),
+		envModes:        make(map[string]EnforcementLevel),
+		overrides:       make(map[string]Override),
+		now:             time.Now,
+	}
+}
+
+// SetClock overrides the time source used by time-based policies and
+// violation timestamps (for testing).
+func (e *Engine) SetClock(now func() time.Time) {
+	e.now = now
+}
+
+// Now returns the current time according to the engine's clock.
+func (e *Engine) Now() time.Time {
+	return e.now()
+}
+
+// SetEnabled turns policy evaluation on or off. A disabled engine passes
+// every action without evaluating anything.
+func (e *Engine) SetEnabled(enabled bool) {
+	e.disabled = !enabled
+}
+
+// Enabled reports whether policies are evaluated.
+func (e *Engine) Enabled() bool {
+	return !e.disabled
+}
+
+// SetEnvironmentMode sets the enforcement floor for an environment or tier.
+// It can raise the global mode there but never lower it.
+func (e *Engine) SetEnvironmentMode(envOrTier string, mode EnforcementLevel) {
+	e.envModes[strings.ToLower(envOrTier)] = mode
+}
+
+// SetOverride adjusts a policy's enforcement and severity. It applies to the
+// policy whether it is already registered or registered later.
+func (e *Engine) SetOverride(name string, o Override) {
+	e.overrides[name] = o
+	for _, p := range e.policies {
+		if p.Name == name {
+			o.apply(p)
+		}
 	}
 }
 
+// apply sets the overridden severity. Enforcement overrides are resolved
+// per violation so the policy keeps its declared level.
+func (o Override) apply(p *Policy) {
+	if o.Severity != "" {
+		p.Severity = o.Severity
+	}
+}
+
+// lowersDeny reports whether o downgrades the DENY policy p to a warning or
+// puts it in audit mode, where it no longer blocks.
+func (o Override) lowersDeny(p *Policy) bool {
+	return p.Enforcement == EnforcementDeny && (o.Enforcement == EnforcementWarn || o.Enforcement == EnforcementAudit)
+}
+
+// checkOverride rejects an override that lowers the DENY policy p without a
+// reason.
+func (e *Engine) checkOverride(p *Policy) error {
+	if o, ok := e.overrides[p.Name]; ok && o.lowersDeny(p) && strings.TrimSpace(o.Reason) == "" {
+		return fmt.Errorf("overrides.%s: reason is required to lower a DENY policy to %s", p.Name, o.Enforcement)
+	}
+	return nil
+}
+
+// AuditMode reports whether a policy runs in audit (shadow) mode.
+func (e *Engine) AuditMode(name string) bool {
+	o, ok := e.overrides[name]
+	return ok && o.Enforcement == EnforcementAudit
+}
+
+// AddExemption registers an explicit exemption. A reason is required so the
+// downgrade is visible wherever the violation is reported.
+func (e *Engine) AddExemption(x Exemption) error {
+	if x.Policy == "" {
+		return fmt.Errorf("exemption: policy is required")
+	}
+	if strings.TrimSpace(x.Reason) == "" {
+		return fmt.Errorf("exemption for '%s': reason is required", x.Policy)
+	}
+	e.exemptions = append(e.exemptions, x)
+	return nil
+}
+
+// SetExceptions attaches a registry of per-resource policy exceptions.
+func (e *Engine) SetExceptions(r *ExceptionRegistry) {
+	e.exceptions = r
+}
+
+// Exceptions returns the attached exception registry, or nil.
+func (e *Engine) Exceptions() *ExceptionRegistry {
+	return e.exceptions
+}
+
+// Exemptions returns all registered exemptions.
+func (e *Engine) Exemptions() []Exemption {
+	return e.exemptions
+}
+
+// modeFor returns the enforcement floor for env: the stricter of the global
+// mode, the environment's own mode and its tier's mode.
+func (e *Engine) modeFor(env string) EnforcementLevel {
+	modes := []EnforcementLevel{e.enforcementMode, e.envModes[strings.ToLower(env)]}
+	if tier := e.classifier.Classify(env); tier != nil {
+		modes = append(modes, e.envModes[strings.ToLower(tier.Name)])
+	}
+	for _, mode := range modes {
+		if mode == EnforcementDeny {
+			return EnforcementDeny
+		}
+	}
+	return EnforcementWarn
+}
+
+// SetClassifier replaces the environment tier classifier. Policy environments
+// may then name either a concrete environment or a tier.
+func (e *Engine) SetClassifier(classifier *environment.Classifier) {
+	e.classifier = classifier
+}
+
 // Register adds a policy to the engine.
 func (e *Engine) Register(policy *Policy) {
+	if o, ok := e.overrides[policy.Name]; ok {
+		o.apply(policy)
+	}
 	e.policies = append(e.policies, policy)
 }
 
 // LoadBuiltins registers all built-in policies.
 func (e *Engine) LoadBuiltins() {
-	for _, p := range BuiltinPolicies() {
+	for _, p := range BuiltinPoliciesWith(BuiltinOptions{Now: e.Now}) {
 		e.Register(p)
 	}
 }
 
 // Evaluate checks all applicable policies against a skill execution.
 func (e *Engine) Evaluate(skill *core.Skill, params map[string]interface{}, env string) *EvaluationResult {
+	return e.EvaluateInput(&Input{Skill: skill, Params: params, Environment: env})
+}
+
+// EvaluateInput checks all applicable policies and backends against an input
+// document, which may also carry the acting user and safety report.
+func (e *Engine) EvaluateInput(in *Input) *EvaluationResult {
+	skill, params, env := in.Skill, in.Params, in.Environment
 	result := &EvaluationResult{
 		Passed:     true,
 		Violations: []Violation{},
 		Warnings:   []Violation{},
 	}
+	if e.disabled {
+		return result
+	}
 
 	for _, policy := range e.policies {
 		if !e.policyApplies(policy, skill, env) {
 			continue
 		}
 
-		violated, reason := policy.CheckFunc(skill, params, env)
+		enforcement := policy.Enforcement
+		var violated bool
+		var reason string
+		switch {
+		case policy.ruleCheck != nil:
+			var err error
+			violated, reason, err = policy.ruleCheck(skill, params, env)
+			if err != nil {
+				// Fail closed: a rule that cannot be evaluated blocks,
+				// whatever the policy's own level.
+				violated, enforcement = true, EnforcementDeny
+				reason = fmt.Sprintf("rule evaluation failed: %v", err)
+			}
+		case policy.CheckFunc != nil:
+			violated, reason = policy.CheckFunc(skill, params, env)
+		default:
+			continue // manifest-only policy
+		}
 		if !violated {
 			continue
 		}
 
-		v := Violation{
+		e.record(result, Violation{
 			PolicyName:  policy.Name,
 			Description: policy.Description,
 			Severity:    policy.Severity,
-			Enforcement: policy.Enforcement,
+			Enforcement: enforcement,
 			Reason:      reason,
 			SkillName:   skill.Name,
 			Environment: env,
-			Timestamp:   time.Now(),
+			Timestamp:   e.now(),
+			Resource:    ResourceID(skill, params),
+		})
+	}
+
+	// Planned resources are checked whenever the input carries a plan, even
+	// when the skill itself (e.g. terraform.apply) is outside AppliesTo.
+	if in.Plan != nil {
+		for _, policy := range e.policies {
+			if policy.ResourceCheck == nil || !e.environmentApplies(policy, env) {
+				continue
+			}
+			for _, rc := range in.Plan.ResourceChanges {
+				for _, reason := range policy.ResourceCheck(rc) {
+					e.record(result, Violation{
+						PolicyName:  policy.Name,
+						Description: policy.Description,
+						Severity:    policy.Severity,
+						Enforcement: policy.Enforcement,
+						Reason:      reason,
+						SkillName:   skill.Name,
+						Environment: env,
+						Timestamp:   e.now(),
+						Resource:    rc.Address,
+					})
+				}
+			}
 		}
+	}
 
-		// Effective enforcement = stricter of (global, policy-level)
-		// Global warn ⇒ always warn. Global deny ⇒ always deny.
-		effectiveEnforcement := policy.Enforcement
-		if e.enforcementMode == EnforcementDeny {
-			effectiveEnforcement = EnforcementDeny
-		} else if e.enforcementMode == EnforcementWarn {
-			effectiveEnforcement = EnforcementWarn
+	// Manifest checks follow AppliesTo: rendered objects are inspected only
+	// for the deploy skills the policy covers.
+	for _, obj := range in.Manifests {
+		for _, policy := range e.policies {
+			if policy.ManifestCheck == nil || !e.policyApplies(policy, skill, env) {
+				continue
+			}
+			for _, reason := range policy.ManifestCheck(obj) {
+				e.record(result, Violation{
+					PolicyName:  policy.Name,
+					Description: policy.Description,
+					Severity:    policy.Severity,
+					Enforcement: policy.Enforcement,
+					Reason:      reason,
+					SkillName:   skill.Name,
+					Environment: env,
+					Timestamp:   e.now(),
+					Resource:    obj.Address(),
+				})
+			}
 		}
+	}
 
-		if effectiveEnforcement == EnforcementDeny {
-			result.Violations = append(result.Violations, v)
-			result.Passed = false
-			result.Denied = true
-		} else {
-			result.Warnings = append(result.Warnings, v)
+	for _, backend := range e.backends {
+		violations, err := backend.Evaluate(in)
+		if err != nil {
+			// Fail closed: a backend that cannot evaluate blocks execution.
+			violations = []Violation{{
+				PolicyName:  backend.Name(),
+				Description: "Policy backend evaluation failed",
+				Severity:    SeverityCritical,
+				Enforcement: EnforcementDeny,
+				Reason:      err.Error(),
+			}}
+		}
+		for _, v := range violations {
+			if o, ok := e.overrides[v.PolicyName]; ok && o.Severity != "" {
+				v.Severity = o.Severity
+			}
+			v.SkillName = skill.Name
+			v.Environment = env
+			if v.Resource == "" {
+				v.Resource = ResourceID(skill, params)
+			}
+			if v.Timestamp.IsZero() {
+				v.Timestamp = e.now()
+			}
+			e.record(result, v)
 		}
 	}
 
 	return result
 }
 
+// record files a violation as suppressed, blocking or a warning.
+func (e *Engine) record(result *EvaluationResult, v Violation) {
+	if e.exceptions != nil {
+		if x := e.exceptions.Match(v); x != nil {
+			v.SuppressedBy = x
+			result.Suppressed = append(result.Suppressed, v)
+			return
+		}
+	}
+	v.Enforcement, v.Exemption = e.effectiveEnforcement(v)
+	if e.AuditMode(v.PolicyName) {
+		v.Shadow, v.Enforcement = v.Enforcement, EnforcementAudit
+		result.Audited = append(result.Audited, v)
+		return
+	}
+	if v.Enforcement == EnforcementDeny {
+		result.Violations = append(result.Violations, v)
+		result.Passed = false
+		result.Denied = true
+	} else {
+		result.Warnings = append(result.Warnings, v)
+	}
+}
+
+// effectiveEnforcement resolves how a violation is enforced, in order:
+//
+//  1. an exemption matching the policy and environment downgrades to warn;
+//  2. an explicit per-policy enforcement override is final, but lowers a
+//     DENY only when it carries a reason;
+//  3. otherwise the stricter of the policy's own level and the floor, where
+//     the floor is the stricter of the global and environment modes.
+//
+// A global warn mode therefore never weakens a DENY policy.
+func (e *Engine) effectiveEnforcement(v Violation) (EnforcementLevel, string) {
+	for _, x := range e.exemptions {
+		if x.Policy != v.PolicyName {
+			continue
+		}
+		if len(x.Environments) == 0 || e.classifier.MatchesAny(v.Environment, x.Environments) {
+			return EnforcementWarn, x.Reason
+		}
+	}
+	level := EnforcementWarn
+	if v.Enforcement == EnforcementDeny || e.modeFor(v.Environment) == EnforcementDeny {
+		level = EnforcementDeny
+	}
+	if o, ok := e.overrides[v.PolicyName]; ok && o.Enforcement != "" && o.Enforcement != EnforcementAudit {
+		switch {
+		case o.Enforcement == EnforcementDeny:
+			return EnforcementDeny, ""
+		case level != EnforcementDeny:
+			return EnforcementWarn, ""
+		case strings.TrimSpace(o.Reason) != "":
+			return EnforcementWarn, o.Reason
+		}
+	}
+	return level, ""
+}
+
+// AddBackend registers an additional policy backend, such as a Rego evaluator.
+func (e *Engine) AddBackend(b Backend) {
+	e.backends = append(e.backends, b)
+}
+
+// Backends returns all registered policy backends.
+func (e *Engine) Backends() []Backend {
+	return e.backends
+}
+
 // ListPolicies returns all registered policies.
 func (e *Engine) ListPolicies() []*Policy {
 	return e.policies
@@ -159,21 +487,13 @@ func (e *Engine) policyApplies(policy *Policy, skill *core.Skill, env string) bo
 		}
 	}
 
-	// Check if environment matches
-	if len(policy.Environments) > 0 {
-		envMatched := false
-		for _, e := range policy.Environments {
-			if strings.EqualFold(e, env) {
-				envMatched = true
-				break
-			}
-		}
-		if !envMatched {
-			return false
-		}
-	}
+	// Check if environment (or its tier) matches
+	return e.environmentApplies(policy, env)
+}
 
-	return true
+// environmentApplies checks if a policy covers env or its tier.
+func (e *Engine) environmentApplies(policy *Policy, env string) bool {
+	return len(policy.Environments) == 0 || e.classifier.MatchesAny(env, policy.Environments)
 }
 
 // matchSkillPattern supports simple wildcard matching for skill names.
@@ -198,6 +518,8 @@ func (r *EvaluationResult) Render() string {
 
 	if r.Passed && len(r.Warnings) == 0 {
 		b.WriteString("✅ All policies passed\n")
+		r.renderAudited(&b)
+		r.renderSuppressed(&b)
 		return b.String()
 	}
 
@@ -205,6 +527,9 @@ func (r *EvaluationResult) Render() string {
 		b.WriteString(fmt.Sprintf("⚠️  POLICY WARNINGS (%d)\n", len(r.Warnings)))
 		for _, w := range r.Warnings {
 			b.WriteString(fmt.Sprintf("  ⚠️  [%s] %s: %s\n", w.Severity, w.PolicyName, w.Reason))
+			if w.Exemption != "" {
+				b.WriteString(fmt.Sprintf("      ↳ exempt: %s\n", w.Exemption))
+			}
 		}
 	}
 
@@ -215,5 +540,37 @@ func (r *EvaluationResult) Render() string {
 		}
 	}
 
+	r.renderAudited(&b)
+	r.renderSuppressed(&b)
 	return b.String()
 }
+
+func (r *EvaluationResult) renderAudited(b *strings.Builder) {
+	if len(r.Audited) == 0 {
+		return
+	}
+	b.WriteString(fmt.Sprintf("\n🔍 AUDIT MODE (%d) — recorded, not enforced\n", len(r.Audited)))
+	for _, v := range r.Audited {
+		outcome := "would warn"
+		if v.Shadow == EnforcementDeny {
+			outcome = "would block"
+		}
+		b.WriteString(fmt.Sprintf("  🔍 [%s] %s: %s (%s)\n", v.Severity, v.PolicyName, v.Reason, outcome))
+	}
+}
+
+func (r *EvaluationResult) renderSuppressed(b *strings.Builder) {
+	if len(r.Suppressed) == 0 {
+		return
+	}
+	b.WriteString(fmt.Sprintf("\n🔕 SUPPRESSED BY EXCEPTION (%d)\n", len(r.Suppressed)))
+	for _, v := range r.Suppressed {
+		x := v.SuppressedBy
+		b.WriteString(fmt.Sprintf("  🔕 %s on %s: %s\n", v.PolicyName, v.Resource, v.Reason))
+		b.WriteString(fmt.Sprintf("      ↳ %s owned by %s, expires %s", x.ID, x.Owner, FormatExpiry(x.Expires)))
+		if x.Ticket != "" {
+			b.WriteString(fmt.Sprintf(" (%s)", x.Ticket))
+		}
+		b.WriteString("\n")
+	}
+}
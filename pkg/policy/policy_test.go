@@ -1,10 +1,20 @@
 package policy_test
 
 import (
+	"crypto/ed25519"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"gopkg.in/yaml.v3"
+
+	"github.com/parth14193/ownbot/pkg/config"
 	"github.com/parth14193/ownbot/pkg/core"
+	"github.com/parth14193/ownbot/pkg/environment"
 	"github.com/parth14193/ownbot/pkg/policy"
+	"github.com/parth14193/ownbot/pkg/policy/rego"
 )
 
 func TestLoadBuiltins(t *testing.T) {
@@ -61,10 +71,650 @@ func TestWarnMode(t *testing.T) {
 	e := policy.NewEngine(policy.EnforcementWarn)
 	e.LoadBuiltins()
 
-	skill := &core.Skill{Name: "aws.s3.sync", RiskLevel: core.RiskMedium}
-	result := e.Evaluate(skill, map[string]interface{}{"acl": "public-read"}, "staging")
-	if result.Denied {
-		t.Error("warn mode should not deny — only warn")
+	// Warn mode is a floor: WARN policies only warn...
+	ec2 := &core.Skill{Name: "aws.ec2.launch", RiskLevel: core.RiskMedium}
+	result := e.Evaluate(ec2, nil, "staging")
+	if result.Denied || len(result.Warnings) == 0 {
+		t.Errorf("warn mode should only warn for require_tags: %+v", result)
+	}
+
+	// ...but never weakens a DENY policy.
+	s3 := &core.Skill{Name: "aws.s3.sync", RiskLevel: core.RiskMedium}
+	result = e.Evaluate(s3, map[string]interface{}{"acl": "public-read"}, "staging")
+	if !result.Denied {
+		t.Error("no_public_s3 is DENY and must block under a warn-mode engine")
+	}
+}
+
+func TestEnforcementPrecedence(t *testing.T) {
+	s3 := &core.Skill{Name: "aws.s3.sync"}
+	public := map[string]interface{}{"acl": "public-read"}
+	ec2 := &core.Skill{Name: "aws.ec2.launch"}
+
+	// Environment mode raises WARN policies in its tier only.
+	e := policy.NewEngine(policy.EnforcementWarn)
+	e.LoadBuiltins()
+	e.SetEnvironmentMode("production", policy.EnforcementDeny)
+	if r := e.Evaluate(ec2, nil, "prod"); !r.Denied {
+		t.Error("production tier mode should deny require_tags")
+	}
+	if r := e.Evaluate(ec2, nil, "dev"); r.Denied {
+		t.Error("dev should fall back to the global warn floor")
+	}
+
+	// An environment mode never lowers a deny global mode.
+	strict := policy.NewEngine(policy.EnforcementDeny)
+	strict.LoadBuiltins()
+	strict.SetEnvironmentMode("dev", policy.EnforcementWarn)
+	if r := strict.Evaluate(ec2, nil, "dev"); !r.Denied {
+		t.Error("a warn environment mode should not weaken the global deny floor")
+	}
+
+	// Downgrading a DENY policy without a reason is ignored...
+	e.SetOverride("no_public_s3", policy.Override{Enforcement: policy.EnforcementWarn})
+	if r := e.Evaluate(s3, public, "staging"); !r.Denied {
+		t.Error("an override without a reason must not downgrade a DENY policy")
+	}
+
+	// ...while an explicit override with a reason is final, even where the
+	// environment floor is deny, and records its reason.
+	e.SetOverride("no_public_s3", policy.Override{Enforcement: policy.EnforcementWarn, Reason: "public docs buckets"})
+	for _, env := range []string{"staging", "production"} {
+		r := e.Evaluate(s3, public, env)
+		if r.Denied || len(r.Warnings) != 1 || r.Warnings[0].Exemption != "public docs buckets" {
+			t.Errorf("%s: overridden no_public_s3 should only warn with its reason: %+v", env, r)
+		}
+	}
+
+	// An exemption downgrades only in its environments and carries its reason.
+	e = policy.NewEngine(policy.EnforcementDeny)
+	e.LoadBuiltins()
+	if err := e.AddExemption(policy.Exemption{Policy: "no_public_s3", Environments: []string{"dev"}, Reason: "static site bucket"}); err != nil {
+		t.Fatal(err)
+	}
+	r := e.Evaluate(s3, public, "development")
+	if r.Denied || len(r.Warnings) != 1 || r.Warnings[0].Exemption != "static site bucket" {
+		t.Errorf("exempted violation should warn with its reason: %+v", r)
+	}
+	if !strings.Contains(r.Render(), "exempt: static site bucket") {
+		t.Errorf("render should show the exemption:\n%s", r.Render())
+	}
+	if r := e.Evaluate(s3, public, "staging"); !r.Denied {
+		t.Error("exemption must not apply outside its environments")
+	}
+	if err := e.AddExemption(policy.Exemption{Policy: "no_public_s3"}); err == nil {
+		t.Error("exemption without a reason should be rejected")
+	}
+}
+
+func TestEngineFromConfig(t *testing.T) {
+	cfg := &config.PolicyConfig{
+		EnforcementMode:  "warn",
+		EnabledPolicies:  []string{"require_tags", "no_wide_open_sg", "max_blast_radius"},
+		EnvironmentModes: map[string]string{"production": "deny"},
+		Overrides: map[string]*config.PolicyOverride{
+			"require_tags":     {Severity: "critical", Params: map[string]interface{}{"tags": []interface{}{"owner"}}},
+			"no_wide_open_sg":  {Params: map[string]interface{}{"ports": []interface{}{6379}}},
+			"max_blast_radius": {Params: map[string]interface{}{"threshold": 5}},
+		},
+	}
+	e, err := policy.NewEngineFromConfig(cfg, environment.DefaultClassifier())
+	if err != nil {
+		t.Fatalf("NewEngineFromConfig: %v", err)
+	}
+	if n := len(e.ListPolicies()); n != 3 {
+		t.Fatalf("expected 3 enabled policies, got %d", n)
+	}
+
+	// Disabled built-in does not fire.
+	s3 := &core.Skill{Name: "aws.s3.sync"}
+	if r := e.Evaluate(s3, map[string]interface{}{"acl": "public-read"}, "staging"); len(r.Warnings)+len(r.Violations) != 0 {
+		t.Errorf("no_public_s3 should be disabled: %+v", r)
+	}
+
+	// Parameterised tags, severity override, warn outside production.
+	ec2 := &core.Skill{Name: "aws.ec2.launch"}
+	r := e.Evaluate(ec2, map[string]interface{}{"tags": map[string]interface{}{"team": "a", "env": "b", "service": "c"}}, "staging")
+	if len(r.Warnings) != 1 || r.Warnings[0].Severity != policy.SeverityCritical || !strings.Contains(r.Warnings[0].Reason, "owner") {
+		t.Errorf("expected one CRITICAL warning about 'owner', got %+v", r)
+	}
+
+	// Per-environment mode: the production tier denies ("prod" is classified into it).
+	if r := e.Evaluate(ec2, map[string]interface{}{"tags": map[string]interface{}{}}, "prod"); !r.Denied {
+		t.Errorf("production mode should deny: %+v", r)
+	}
+
+	// Parameterised ports and threshold.
+	sg := &core.Skill{Name: "aws.sg.modify"}
+	r = e.Evaluate(sg, map[string]interface{}{"cidr": "0.0.0.0/0", "port": "6379"}, "staging")
+	if len(r.Violations) != 1 || !strings.Contains(r.Violations[0].Reason, "Cannot open port 6379") {
+		t.Errorf("expected sensitive port 6379, got %+v", r)
+	}
+	r = e.Evaluate(sg, map[string]interface{}{"_resource_count": 6}, "staging")
+	if len(r.Violations) != 1 || !strings.Contains(r.Violations[0].Reason, "max: 5") {
+		t.Errorf("expected blast radius threshold 5, got %+v", r)
+	}
+
+	// Engine disabled entirely.
+	disabled := false
+	off, err := policy.NewEngineFromConfig(&config.PolicyConfig{Enabled: &disabled}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := off.Evaluate(s3, map[string]interface{}{"acl": "public-read"}, "production"); !r.Passed || len(r.Warnings) != 0 {
+		t.Errorf("disabled engine should pass everything: %+v", r)
+	}
+}
+
+func TestEngineFromPartialConfig(t *testing.T) {
+	// A policies section that leaves out enabled keeps policies on.
+	var cfg config.PolicyConfig
+	if err := yaml.Unmarshal([]byte("enforcement_mode: deny\n"), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	e, err := policy.NewEngineFromConfig(&cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !e.Enabled() {
+		t.Fatal("policies should be enabled unless explicitly disabled")
+	}
+	s3 := &core.Skill{Name: "aws.s3.sync"}
+	if r := e.Evaluate(s3, map[string]interface{}{"acl": "public-read"}, "production"); !r.Denied {
+		t.Errorf("expected public-read to be denied, got %+v", r)
+	}
+}
+
+func TestEngineFromConfigErrors(t *testing.T) {
+	cfg := &config.PolicyConfig{
+		EnforcementMode:  "block",
+		EnabledPolicies:  []string{"no_such_policy"},
+		EnvironmentModes: map[string]string{"dev": "maybe"},
+		Overrides: map[string]*config.PolicyOverride{
+			"require_tags":       {Params: map[string]interface{}{"labels": []interface{}{"x"}}},
+			"max_blast_radius":   {Severity: "huge", Params: map[string]interface{}{"threshold": "many"}},
+			"no_public_s3":       {Params: map[string]interface{}{"acl": "x"}},
+			"enforce_encryption": {Enforcement: "warn"},
+			"k8s_no_host_path":   {Enforcement: "audit"},
+		},
+		Exemptions: []*config.PolicyExemption{{Policy: "no_public_s3"}},
+	}
+	_, err := policy.NewEngineFromConfig(cfg, nil)
+	if err == nil {
+		t.Fatal("expected configuration errors")
+	}
+	for _, want := range []string{"enforcement_mode", "no_such_policy", "environment_modes.dev", "unknown param 'labels'", "threshold", "severity", "no_public_s3.params", "enforce_encryption: reason is required", "k8s_no_host_path: reason is required", "exemptions[0]"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error should mention %q: %v", want, err)
+		}
+	}
+
+	// Only lowering a DENY policy needs a reason: a WARN policy may be set to
+	// warn, and any policy may be raised to deny, without one.
+	_, err = policy.NewEngineFromConfig(&config.PolicyConfig{
+		Overrides: map[string]*config.PolicyOverride{
+			"require_tags":       {Enforcement: "warn"},
+			"k8s_no_latest_tag":  {Enforcement: "deny"},
+			"enforce_encryption": {Enforcement: "deny"},
+		},
+	}, nil)
+	if err != nil {
+		t.Errorf("overrides that do not lower a DENY policy should not need a reason: %v", err)
+	}
+
+	// The same applies to declarative policies registered after the override.
+	e := policy.NewEngine(policy.EnforcementWarn)
+	e.SetOverride("no_prod_scale_down", policy.Override{Enforcement: policy.EnforcementWarn})
+	dir := t.TempDir()
+	src := "policies:\n  - name: no_prod_scale_down\n    enforcement: deny\n    rule: params.replicas < 2\n"
+	if err := os.WriteFile(filepath.Join(dir, "p.yaml"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.LoadDir(dir); err == nil || !strings.Contains(err.Error(), "no_prod_scale_down: reason is required") {
+		t.Errorf("expected a reasonless downgrade of a declarative DENY policy to be rejected, got %v", err)
+	}
+}
+
+func TestPolicyExceptions(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	reg := policy.NewExceptionRegistry()
+	reg.SetClock(func() time.Time { return now })
+	err := reg.LoadConfig([]*config.PolicyException{
+		{ID: "EXC-BASTION", Policy: "no_wide_open_sg", Skill: "aws.sg.*", Resource: "sg-bastion-*",
+			Environments: []string{"production"}, Owner: "netops", Reason: "bastion SSH", Ticket: "SEC-1", Expires: "2026-10-10"},
+		{Policy: "require_tags", Resource: "i-legacy", Owner: "ops", Reason: "legacy host", Expires: "2026-09-30"},
+		{Policy: "require_tags", Resource: "i-batch", Owner: "data", Reason: "batch fleet", Expires: "2027-03-01"},
+	})
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+
+	e := policy.NewEngine(policy.EnforcementWarn)
+	e.LoadBuiltins()
+	e.SetExceptions(reg)
+
+	sg := &core.Skill{Name: "aws.sg.modify"}
+	open := func(id string) map[string]interface{} {
+		return map[string]interface{}{"group_id": id, "cidr": "0.0.0.0/0", "port": "22", "_iac_managed": true}
+	}
+	r := e.Evaluate(sg, open("sg-bastion-eu"), "prod")
+	if r.Denied || len(r.Suppressed) != 1 || r.Suppressed[0].SuppressedBy.ID != "EXC-BASTION" || r.Suppressed[0].Resource != "sg-bastion-eu" {
+		t.Fatalf("bastion violation should be suppressed: %+v", r)
+	}
+	if out := r.Render(); !strings.Contains(out, "SUPPRESSED BY EXCEPTION") || !strings.Contains(out, "owned by netops") {
+		t.Errorf("render should list suppressed violations:\n%s", out)
+	}
+	if r := e.Evaluate(sg, open("sg-web"), "prod"); !r.Denied {
+		t.Error("other security groups must still be denied")
+	}
+	if r := e.Evaluate(sg, open("sg-bastion-eu"), "staging"); !r.Denied {
+		t.Error("exception must not apply outside its environments")
+	}
+
+	// Expired exceptions no longer suppress.
+	ec2 := &core.Skill{Name: "aws.ec2.launch"}
+	if r := e.Evaluate(ec2, map[string]interface{}{"instance_id": "i-legacy"}, "dev"); len(r.Suppressed) != 0 || len(r.Warnings) != 1 {
+		t.Errorf("expired exception should not suppress: %+v", r)
+	}
+	if r := e.Evaluate(ec2, map[string]interface{}{"instance_id": "i-batch"}, "dev"); len(r.Suppressed) != 1 {
+		t.Errorf("active exception should suppress: %+v", r)
+	}
+
+	expiring := reg.Expiring(14 * 24 * time.Hour)
+	if len(expiring) != 1 || expiring[0].ID != "EXC-BASTION" {
+		t.Errorf("expected only EXC-BASTION expiring within 14d, got %+v", expiring)
+	}
+	if out := reg.Render(reg.List()); !strings.Contains(out, "EXPIRED") || !strings.Contains(out, "SEC-1") {
+		t.Errorf("unexpected listing:\n%s", out)
+	}
+	if out := reg.Render(reg.List()); !strings.Contains(out, "expires: 2026-10-10") {
+		t.Errorf("expected the configured expiry date, got:\n%s", out)
+	}
+
+	// Only params the skill takes identify the resource.
+	modify := &core.Skill{Name: "aws.sg.modify", Inputs: []core.SkillInput{{Name: "group_id"}, {Name: "cidr"}, {Name: "port"}}}
+	spoofed := open("sg-web")
+	spoofed["arn"] = "sg-bastion-eu"
+	if r := e.Evaluate(modify, spoofed, "prod"); !r.Denied || len(r.Suppressed) != 0 {
+		t.Errorf("an undeclared param must not redirect the exception: %+v", r)
+	}
+	if r := e.Evaluate(modify, open("sg-bastion-eu"), "prod"); len(r.Suppressed) != 1 {
+		t.Errorf("the declared target param should match the exception: %+v", r)
+	}
+
+	if err := reg.Add(&policy.Exception{Policy: "require_tags", Resource: "x"}); err == nil || !strings.Contains(err.Error(), "owner, reason, expires") {
+		t.Errorf("expected missing-field error, got %v", err)
+	}
+}
+
+const tfPlan = `{
+  "resource_changes": [
+    {"address": "aws_s3_bucket.logs", "mode": "managed", "type": "aws_s3_bucket", "name": "logs",
+     "change": {"actions": ["create"], "after": {"bucket": "logs", "acl": "public-read", "tags": {"owner": "ops"}}}},
+    {"address": "aws_security_group.web", "mode": "managed", "type": "aws_security_group", "name": "web",
+     "change": {"actions": ["update"], "after": {"ingress": [
+       {"protocol": "tcp", "from_port": 22, "to_port": 22, "cidr_blocks": ["0.0.0.0/0"]},
+       {"protocol": "tcp", "from_port": 443, "to_port": 443, "cidr_blocks": ["0.0.0.0/0"]}]}}},
+    {"address": "aws_ebs_volume.data", "mode": "managed", "type": "aws_ebs_volume", "name": "data",
+     "change": {"actions": ["create"], "after": {"encrypted": true}}},
+    {"address": "aws_s3_bucket.old", "mode": "managed", "type": "aws_s3_bucket", "name": "old",
+     "change": {"actions": ["delete"], "after": null}},
+    {"address": "data.aws_s3_bucket.shared", "mode": "data", "type": "aws_s3_bucket", "name": "shared",
+     "change": {"actions": ["read"], "after": {"acl": "public-read"}}}
+  ]
+}`
+
+func TestTerraformPlan(t *testing.T) {
+	plan, err := policy.ParsePlan([]byte(tfPlan))
+	if err != nil {
+		t.Fatalf("ParsePlan: %v", err)
+	}
+	if len(plan.ResourceChanges) != 3 {
+		t.Fatalf("expected deletions and data sources to be dropped, got %d changes", len(plan.ResourceChanges))
+	}
+
+	e := policy.NewEngine(policy.EnforcementWarn)
+	e.LoadBuiltins()
+	skill := &core.Skill{Name: "terraform.apply"}
+	r := e.EvaluateInput(&policy.Input{Skill: skill, Params: map[string]interface{}{}, Environment: "production", Plan: plan})
+	if !r.Denied {
+		t.Fatalf("plan with public bucket and open SSH should be denied: %+v", r)
+	}
+	byResource := map[string][]string{}
+	for _, v := range r.Violations {
+		byResource[v.Resource] = append(byResource[v.Resource], v.PolicyName)
+	}
+	if got := byResource["aws_s3_bucket.logs"]; len(got) != 1 || got[0] != "no_public_s3" {
+		t.Errorf("aws_s3_bucket.logs: got %v", got)
+	}
+	if got := byResource["aws_security_group.web"]; len(got) != 1 || got[0] != "no_wide_open_sg" {
+		t.Errorf("aws_security_group.web: got %v (port 443 must not be flagged)", got)
+	}
+	if _, ok := byResource["aws_ebs_volume.data"]; ok {
+		t.Error("encrypted volume should pass")
+	}
+	tagged := false
+	for _, v := range r.Warnings {
+		if v.PolicyName == "require_tags" && v.Resource == "aws_s3_bucket.logs" && strings.Contains(v.Reason, "team, env, service") {
+			tagged = true
+		}
+	}
+	if !tagged {
+		t.Errorf("expected missing-tag warning for aws_s3_bucket.logs, got %+v", r.Warnings)
+	}
+
+	// Exceptions match plan addresses like any other resource.
+	reg := policy.NewExceptionRegistry()
+	if err := reg.Add(&policy.Exception{Policy: "no_wide_open_sg", Resource: "aws_security_group.*", Owner: "netops",
+		Reason: "bastion", Expires: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	e.SetExceptions(reg)
+	r = e.EvaluateInput(&policy.Input{Skill: skill, Params: map[string]interface{}{}, Environment: "production", Plan: plan})
+	if len(r.Suppressed) != 1 || r.Suppressed[0].Resource != "aws_security_group.web" {
+		t.Errorf("expected SG violation to be suppressed: %+v", r.Suppressed)
+	}
+
+	if _, err := policy.ParsePlan([]byte("{")); err == nil {
+		t.Error("expected error for invalid plan JSON")
+	}
+}
+
+const k8sManifests = `---
+apiVersion: apps/v1
+kind: Deployment
+metadata: {name: api, namespace: payments}
+spec:
+  template:
+    spec:
+      initContainers:
+        - name: migrate
+          image: registry.acme.io/payments/migrate:1.4.0
+          resources: {limits: {cpu: 100m, memory: 64Mi}}
+      containers:
+        - name: api
+          image: registry.acme.io/payments/api:latest
+          securityContext: {privileged: true}
+          resources: {limits: {memory: 256Mi}}
+          readinessProbe: {httpGet: {path: /ready, port: 8080}}
+        - name: proxy
+          image: envoyproxy/envoy@sha256:abc
+          resources: {limits: {cpu: 100m, memory: 64Mi}}
+          livenessProbe: {tcpSocket: {port: 9901}}
+          readinessProbe: {tcpSocket: {port: 9901}}
+      volumes:
+        - name: docker
+          hostPath: {path: /var/run/docker.sock}
+---
+# empty document
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata: {name: report}
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+            - name: report
+              image: registry.acme.io/payments/report:2.0
+              resources: {limits: {cpu: "1", memory: 1Gi}}
+---
+apiVersion: v1
+kind: Service
+metadata: {name: api, namespace: payments}
+`
+
+func TestKubernetesManifests(t *testing.T) {
+	objects, err := policy.ParseManifests("release.yaml", []byte(k8sManifests))
+	if err != nil {
+		t.Fatalf("ParseManifests: %v", err)
+	}
+	if len(objects) != 3 || objects[0].Address() != "Deployment/payments/api" || objects[1].Address() != "CronJob/report" {
+		t.Fatalf("unexpected objects: %+v", objects)
+	}
+
+	e, err := policy.NewEngineFromConfig(&config.PolicyConfig{
+		EnforcementMode: "warn",
+		Overrides: map[string]*config.PolicyOverride{
+			"k8s_allowed_registries": {Params: map[string]interface{}{"registries": []interface{}{"registry.acme.io"}}},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewEngineFromConfig: %v", err)
+	}
+	skill := &core.Skill{Name: "helm.upgrade"}
+	r := e.EvaluateInput(&policy.Input{Skill: skill, Params: map[string]interface{}{}, Environment: "staging", Manifests: objects})
+	if !r.Denied {
+		t.Fatalf("privileged container and hostPath should be denied: %+v", r)
+	}
+	got := map[string]string{}
+	for _, v := range append(append([]policy.Violation{}, r.Violations...), r.Warnings...) {
+		if v.Resource != "Deployment/payments/api" {
+			t.Errorf("only the deployment should be flagged, got %s: %s", v.Resource, v.Reason)
+		}
+		got[v.PolicyName] += v.Reason + "\n"
+	}
+	want := map[string]string{
+		"k8s_no_privileged":      "container 'api' runs privileged",
+		"k8s_no_host_path":       "mounts host path '/var/run/docker.sock'",
+		"k8s_require_limits":     "container 'api' has no cpu limit",
+		"k8s_no_latest_tag":      "registry.acme.io/payments/api:latest",
+		"k8s_require_probes":     "container 'api' has no livenessProbe",
+		"k8s_allowed_registries": "container 'proxy' image 'envoyproxy/envoy@sha256:abc'",
+	}
+	for name, reason := range want {
+		if !strings.Contains(got[name], reason) {
+			t.Errorf("%s: want reason containing %q, got %q", name, reason, got[name])
+		}
+	}
+	if strings.Contains(got["k8s_require_probes"], "proxy") || strings.Contains(got["k8s_require_limits"], "migrate") {
+		t.Errorf("compliant containers flagged: %v", got)
+	}
+
+	// Manifest policies only apply to the deploy skills they cover.
+	r = e.EvaluateInput(&policy.Input{Skill: &core.Skill{Name: "aws.s3.create"}, Params: map[string]interface{}{}, Environment: "staging", Manifests: objects})
+	if len(r.Violations)+len(r.Warnings) != 0 {
+		t.Errorf("k8s policies should not apply to aws.s3.create: %+v", r)
+	}
+
+	// Image checks also run on k8s.deploy params without a manifest.
+	r = e.Evaluate(&core.Skill{Name: "k8s.deploy"}, map[string]interface{}{"image": "nginx"}, "staging")
+	if len(r.Violations) != 1 || r.Violations[0].PolicyName != "k8s_allowed_registries" || len(r.Warnings) != 1 || r.Warnings[0].PolicyName != "k8s_no_latest_tag" {
+		t.Errorf("expected registry violation and latest-tag warning for bare image: %+v", r)
+	}
+
+	if _, err := policy.ParseManifests("bad.yaml", []byte("metadata: {name: x}\n")); err == nil || !strings.Contains(err.Error(), "bad.yaml:1") {
+		t.Errorf("expected error for object without kind, got %v", err)
+	}
+}
+
+func TestAuditModeAndDecisionLog(t *testing.T) {
+	e, err := policy.NewEngineFromConfig(&config.PolicyConfig{
+		EnforcementMode: "deny",
+		Overrides: map[string]*config.PolicyOverride{
+			"no_wide_open_sg": {Enforcement: "audit", Reason: "rollout"},
+			"require_tags":    {Enforcement: "AUDIT"},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewEngineFromConfig: %v", err)
+	}
+	sg := &core.Skill{Name: "aws.sg.modify"}
+	open := &policy.Input{Skill: sg, Params: map[string]interface{}{"group_id": "sg-1", "cidr": "0.0.0.0/0", "port": "22", "_iac_managed": true}, Environment: "prod", User: "alice"}
+	r := e.EvaluateInput(open)
+	if !r.Passed || len(r.Violations) != 0 || len(r.Audited) != 1 {
+		t.Fatalf("audit-mode policy must not block: %+v", r)
+	}
+	if a := r.Audited[0]; a.Enforcement != policy.EnforcementAudit || a.Shadow != policy.EnforcementDeny {
+		t.Errorf("expected audited violation shadowing deny, got %+v", a)
+	}
+	if out := r.Render(); !strings.Contains(out, "AUDIT MODE") || !strings.Contains(out, "would block") {
+		t.Errorf("render should show audited violations:\n%s", out)
+	}
+
+	path := filepath.Join(t.TempDir(), "decisions.jsonl")
+	log, err := policy.OpenDecisionLog(path)
+	if err != nil {
+		t.Fatalf("OpenDecisionLog: %v", err)
+	}
+	start := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	now := start
+	log.SetClock(func() time.Time { return now })
+	record := func(in *policy.Input) {
+		t.Helper()
+		if err := log.Record(in, e.EvaluateInput(in)); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+		now = now.Add(time.Minute)
+	}
+	s3 := &policy.Input{Skill: &core.Skill{Name: "aws.s3.put"}, Params: map[string]interface{}{"bucket": "b", "acl": "public-read"}, Environment: "staging"}
+	safe := &policy.Input{Skill: &core.Skill{Name: "aws.s3.put"}, Params: map[string]interface{}{"bucket": "b"}, Environment: "staging"}
+	record(open)
+	record(open)
+	record(s3)
+	record(safe)
+	now = start.AddDate(0, 0, -30)
+	record(s3) // outside the report window
+
+	reopened, err := policy.OpenDecisionLog(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	report := reopened.Report(start.Add(-time.Hour))
+	if report.Executions != 4 || report.Blocked != 1 || report.WouldBlock != 2 {
+		t.Errorf("unexpected totals: %+v", report)
+	}
+	stats := map[string]*policy.PolicyStats{}
+	for _, s := range report.Policies {
+		stats[s.Policy] = s
+	}
+	if s := stats["no_wide_open_sg"]; s == nil || s.WouldBlock != 2 || s.Blocked != 0 {
+		t.Errorf("no_wide_open_sg stats: %+v", s)
+	}
+	if s := stats["no_public_s3"]; s == nil || s.Blocked != 1 {
+		t.Errorf("no_public_s3 stats: %+v", s)
+	}
+	if !strings.Contains(report.Render(), "no_wide_open_sg") {
+		t.Errorf("report should list policies:\n%s", report.Render())
+	}
+
+	decisions := reopened.Decisions(start)
+	if d := decisions[0]; d.User != "alice" || d.Decision != policy.DecisionAudit || !d.WouldBlock || d.InputsHash != policy.InputsHash(open) {
+		t.Errorf("unexpected first decision: %+v", d)
+	}
+	if decisions[0].InputsHash == decisions[len(decisions)-1].InputsHash {
+		t.Error("different inputs should hash differently")
+	}
+}
+
+func TestPolicyBundles(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+
+	const sgModule = "package platform.sg\n\ndeny contains \"ssh from anywhere\" if input.params.port == 22\n"
+	makeBundleWith := func(version, module string) string {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, "manifest.yaml"), "name: platform\nversion: "+version+"\n")
+		writeFile(t, filepath.Join(dir, "network.yaml"), `
+policies:
+  - name: no_internet_rdp
+    enforcement: deny
+    applies_to: ["aws.sg.*"]
+    rule: params.port == 3389
+`)
+		writeFile(t, filepath.Join(dir, "sg.rego"), module)
+		if _, err := policy.SignBundle(dir, priv); err != nil {
+			t.Fatalf("SignBundle: %v", err)
+		}
+		return dir
+	}
+	makeBundle := func(version string) string { return makeBundleWith(version, sgModule) }
+
+	lockPath := filepath.Join(t.TempDir(), "lock.json")
+	lock, err := policy.OpenBundleLock(lockPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trust := policy.BundleTrust{Keys: []ed25519.PublicKey{otherPub, pub}, Lock: lock}
+	v14 := makeBundle("1.4.0")
+
+	e := policy.NewEngine(policy.EnforcementWarn)
+	b, loaded, err := e.LoadBundle(v14, trust, rego.CompileBundle)
+	if err != nil {
+		t.Fatalf("LoadBundle: %v", err)
+	}
+	if len(loaded) != 1 || loaded[0].Source != "platform@1.4.0/network.yaml:3" || len(b.Files()) != 2 {
+		t.Errorf("unexpected bundle contents: %+v, files %v", loaded, b.Files())
+	}
+	if r := e.Evaluate(&core.Skill{Name: "aws.sg.modify"}, map[string]interface{}{"port": 3389}, "dev"); !r.Denied {
+		t.Error("bundled policy should be enforced")
+	}
+	if r := e.Evaluate(&core.Skill{Name: "aws.sg.modify"}, map[string]interface{}{"port": 22}, "dev"); !r.Denied {
+		t.Error("bundled Rego module should be enforced")
+	}
+	if lock.Version("platform") != "1.4.0" {
+		t.Errorf("lock should record 1.4.0, got %q", lock.Version("platform"))
+	}
+
+	// A bundle with an invalid Rego module is refused as a whole: its
+	// declarative policies are not registered and its version is not
+	// accepted.
+	fresh := policy.NewEngine(policy.EnforcementWarn)
+	bad := makeBundleWith("1.5.0", "package platform.sg\n\ndeny contains msg if {\n  msg := unknown_fn(1)\n}\n")
+	if _, _, err := fresh.LoadBundle(bad, trust, rego.CompileBundle); err == nil || !strings.Contains(err.Error(), "platform/sg.rego:4") {
+		t.Errorf("expected the bad module to be reported with file and line, got %v", err)
+	}
+	if len(fresh.ListPolicies()) != 0 || len(fresh.Backends()) != 0 {
+		t.Errorf("nothing from a refused bundle should be registered: %d policies, %d backends", len(fresh.ListPolicies()), len(fresh.Backends()))
+	}
+	if lock.Version("platform") != "1.4.0" {
+		t.Errorf("a refused bundle must not advance the lock, got %q", lock.Version("platform"))
+	}
+
+	cases := map[string]struct {
+		path  string
+		trust policy.BundleTrust
+		want  string
+	}{
+		"untrusted key": {v14, policy.BundleTrust{Keys: []ed25519.PublicKey{otherPub}}, "does not match any trusted key"},
+		"pin mismatch":  {v14, policy.BundleTrust{Keys: trust.Keys, Pin: "1.5"}, "pinned to 1.5"},
+		"downgrade":     {makeBundle("1.3.9"), trust, "refusing downgrade"},
+	}
+	for name, c := range cases {
+		if _, _, err := policy.NewEngine(policy.EnforcementWarn).LoadBundle(c.path, c.trust, rego.CompileBundle); err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("%s: want error containing %q, got %v", name, c.want, err)
+		}
+	}
+
+	// The accepted version survives restarts.
+	reopened, err := policy.OpenBundleLock(lockPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := policy.NewEngine(policy.EnforcementWarn).LoadBundle(makeBundle("1.2"), policy.BundleTrust{Keys: trust.Keys, Pin: "1.2", Lock: reopened}, rego.CompileBundle); err == nil || !strings.Contains(err.Error(), "refusing downgrade") {
+		t.Errorf("pinning an older version must not bypass the downgrade check, got %v", err)
+	}
+
+	tampered := makeBundle("2.0.0")
+	writeFile(t, filepath.Join(tampered, "network.yaml"), "policies: []\n")
+	if _, _, err := policy.NewEngine(policy.EnforcementWarn).LoadBundle(tampered, trust, rego.CompileBundle); err == nil || !strings.Contains(err.Error(), "does not match its manifest digest") {
+		t.Errorf("tampered file should be refused, got %v", err)
+	}
+	writeFile(t, filepath.Join(tampered, "extra.yaml"), "policies: []\n")
+	if err := os.Remove(filepath.Join(tampered, "manifest.sig")); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := policy.NewEngine(policy.EnforcementWarn).LoadBundle(tampered, trust, rego.CompileBundle); err == nil || !strings.Contains(err.Error(), "unsigned") {
+		t.Errorf("unsigned bundle should be refused, got %v", err)
+	}
+
+	if c, _ := policy.CompareVersions("v1.10", "1.9.9"); c != 1 {
+		t.Errorf("1.10 should be newer than 1.9.9")
 	}
 }
 
@@ -94,3 +744,228 @@ func TestPolicyAppliesPatternMatching(t *testing.T) {
 		t.Error("should not match aws.s3.* for ec2 skill")
 	}
 }
+
+func TestPolicyEnvironmentTiers(t *testing.T) {
+	e := policy.NewEngine(policy.EnforcementDeny)
+	e.SetClassifier(environment.NewClassifier(
+		&environment.Tier{Name: "production", Patterns: []string{"prod-*", "live"}, Production: true},
+	))
+	e.Register(&policy.Policy{
+		Name:         "prod_only",
+		Enforcement:  policy.EnforcementDeny,
+		Environments: []string{"production"},
+		CheckFunc: func(skill *core.Skill, params map[string]interface{}, env string) (bool, string) {
+			return true, "blocked in production"
+		},
+	})
+
+	skill := &core.Skill{Name: "k8s.deploy"}
+	if e.Evaluate(skill, nil, "live").Passed {
+		t.Error("policy scoped to the production tier should apply to 'live'")
+	}
+	if !e.Evaluate(skill, nil, "staging").Passed {
+		t.Error("policy should not apply outside the production tier")
+	}
+}
+
+func TestDeclarativePolicies(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "network.yaml"), `policies:
+  - name: no_internet_admin_ports
+    description: SSH and RDP must not be reachable from the internet
+    enforcement: deny
+    severity: critical
+    applies_to: ["aws.sg.*"]
+    environments: [production]
+    rule: params.cidr == "0.0.0.0/0" && params.port in [22, 3389]
+    message: "port {{ params.port }} open to {{ params.cidr }}"
+`)
+	writeFile(t, filepath.Join(dir, "nested", "risk.yml"), `name: high_risk_needs_ticket
+enforcement: warn
+rule: skill.risk_level == "HIGH" && !has(params._ticket)
+`)
+
+	e := policy.NewEngine(policy.EnforcementDeny)
+	loaded, err := e.LoadDir(dir)
+	if err != nil {
+		t.Fatalf("LoadDir: %v", err)
+	}
+	if len(loaded) != 2 || loaded[0].Source != filepath.Join(dir, "nested", "risk.yml")+":1" {
+		t.Fatalf("expected 2 policies with sources, got %+v", loaded)
+	}
+
+	sg := &core.Skill{Name: "aws.sg.modify", RiskLevel: core.RiskHigh}
+	result := e.Evaluate(sg, map[string]interface{}{"cidr": "0.0.0.0/0", "port": "22", "_ticket": "CHG-1"}, "production")
+	if result.Passed || len(result.Violations) != 1 {
+		t.Fatalf("expected SSH from internet to be denied, got %+v", result)
+	}
+	if result.Violations[0].Reason != "port 22 open to 0.0.0.0/0" || result.Violations[0].Severity != policy.SeverityCritical {
+		t.Errorf("unexpected violation %+v", result.Violations[0])
+	}
+	if !e.Evaluate(sg, map[string]interface{}{"cidr": "10.0.0.0/8", "port": "22", "_ticket": "CHG-1"}, "production").Passed {
+		t.Error("private CIDR should pass")
+	}
+	if !e.Evaluate(sg, map[string]interface{}{"cidr": "0.0.0.0/0", "port": "22", "_ticket": "CHG-1"}, "staging").Passed {
+		t.Error("policy scoped to production should not apply to staging")
+	}
+}
+
+func TestDeclarativeRuleErrorsFailClosed(t *testing.T) {
+	dir := t.TempDir()
+	// len() of a number cannot be evaluated.
+	writeFile(t, filepath.Join(dir, "broken.yaml"), `policies:
+  - name: warn_rule
+    enforcement: warn
+    rule: len(params.replicas) > 3
+  - name: deny_rule
+    enforcement: deny
+    rule: len(params.replicas) > 3
+`)
+	for _, mode := range []policy.EnforcementLevel{policy.EnforcementWarn, policy.EnforcementDeny} {
+		e := policy.NewEngine(mode)
+		if _, err := e.LoadDir(dir); err != nil {
+			t.Fatalf("LoadDir: %v", err)
+		}
+		result := e.Evaluate(&core.Skill{Name: "k8s.scale"}, map[string]interface{}{"replicas": 5}, "dev")
+		if !result.Denied || len(result.Violations) != 2 || len(result.Warnings) != 0 {
+			t.Fatalf("%s engine: rule errors should deny at both levels, got %+v", mode, result)
+		}
+		for _, v := range result.Violations {
+			if !strings.Contains(v.Reason, "rule evaluation failed") {
+				t.Errorf("%s: unexpected reason %q", v.PolicyName, v.Reason)
+			}
+		}
+	}
+}
+
+func TestDeclarativePolicyValidation(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "bad.yaml"), `policies:
+  - name: ok
+    rule: env == "production"
+  - name: broken_rule
+    enforcement: deny
+    rule: params.port in [22,
+  - name: bad_enforcement
+    enforcement: block
+    rule: "true"
+    colour: red
+`)
+
+	e := policy.NewEngine(policy.EnforcementWarn)
+	_, err := e.LoadDir(dir)
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	file := filepath.Join(dir, "bad.yaml")
+	for _, want := range []string{
+		file + ":6: policy 'broken_rule': rule:",
+		file + ":8: unknown enforcement 'block'",
+		file + ":10: unknown field 'colour'",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error containing %q, got:\n%v", want, err)
+		}
+	}
+	if len(e.ListPolicies()) != 0 {
+		t.Error("no policies should be registered when validation fails")
+	}
+}
+
+func TestPolicyFixtures(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "db.yaml"), `
+name: no_public_db
+enforcement: deny
+applies_to: ["aws.rds.*"]
+rule: params.public == true
+message: "database {{ params.name }} must not be public"
+`)
+	writeFile(t, filepath.Join(dir, "db_test.yaml"), `
+tests:
+  - name: public database is denied
+    skill: aws.rds.modify
+    params: {public: true, name: orders}
+    expect:
+      violations: [no_public_db]
+      reasons: {no_public_db: "orders must not"}
+  - name: wrong expectation
+    skill: aws.rds.modify
+    params: {public: true, name: orders}
+    expect:
+      warnings: [no_public_db]
+      reasons: {no_public_db: "billing"}
+`)
+
+	e := policy.NewEngine(policy.EnforcementWarn)
+	loaded, err := e.LoadDir(dir)
+	if err != nil || len(loaded) != 1 {
+		t.Fatalf("LoadDir should skip fixtures: %v (%d policies)", err, len(loaded))
+	}
+	fixtures, err := policy.LoadFixtures(dir)
+	if err != nil || len(fixtures) != 2 {
+		t.Fatalf("LoadFixtures: %v", err)
+	}
+	results := e.RunFixtures(fixtures, func(name string) *core.Skill { return &core.Skill{Name: name} })
+	if !results[0].Passed {
+		t.Errorf("first fixture should pass: %v", results[0].Diff)
+	}
+	want := []string{
+		"+ unexpected violation: no_public_db",
+		"- expected warning: no_public_db",
+		`- reason for no_public_db containing "billing"`,
+		`+ reason for no_public_db: "database orders must not be public"`,
+	}
+	if results[1].Passed || strings.Join(results[1].Diff, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected diff:\n%s", strings.Join(results[1].Diff, "\n"))
+	}
+	if out := policy.RenderFixtureResults(results); !strings.Contains(out, "1 passed, 1 failed") || !strings.Contains(out, "db_test.yaml:9") {
+		t.Errorf("unexpected report:\n%s", out)
+	}
+
+	_, err = policy.ParseFixtures("bad_test.yaml", []byte("tests:\n  - skill: x\n    expect:\n      violatons: [a]\n"))
+	if err == nil || !strings.Contains(err.Error(), "bad_test.yaml:4: unknown field 'violatons'") {
+		t.Errorf("expected unknown field error, got %v", err)
+	}
+	if _, err := policy.ParseFixtures("bad_test.yaml", []byte("tests:\n  - skill: x\n    now: tomorrow\n")); err == nil || !strings.Contains(err.Error(), "RFC 3339") {
+		t.Errorf("expected invalid now error, got %v", err)
+	}
+
+	// A fixture's now pins time-based policies regardless of the wall clock.
+	timed, err := policy.ParseFixtures("window_test.yaml", []byte(`
+tests:
+  - name: saturday deploy warns
+    skill: k8s.deploy
+    env: production
+    now: 2026-10-17T10:00:00Z
+    params: {_iac_managed: true}
+    expect:
+      warnings: [production_deploy_window]
+  - name: weekday deploy is quiet
+    skill: k8s.deploy
+    env: production
+    now: 2026-10-14T10:00:00Z
+    params: {_iac_managed: true}
+    expect: {}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	builtins := policy.NewEngine(policy.EnforcementWarn)
+	builtins.LoadBuiltins()
+	for _, r := range builtins.RunFixtures(timed, func(name string) *core.Skill { return &core.Skill{Name: name} }) {
+		if !r.Passed {
+			t.Errorf("%s: %v", r.Fixture.Name, r.Diff)
+		}
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
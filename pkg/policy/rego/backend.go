@@ -0,0 +1,391 @@
+// Package rego evaluates Rego policies in-process with the embedded Open
+// Policy Agent engine, so modules behave exactly as they do under OPA.
+// Modules may use Rego v1 syntax or the older v0 syntax; each file is parsed
+// with whichever it is written in.
+//
+// Modules are evaluated against policy.Input.Document() as input. Results of
+// the deny (or violation) rule block execution; results of warn are reported
+// as warnings:
+//
+//	package infracore.sg
+//
+//	deny contains msg if {
+//	    input.environment == "production"
+//	    input.params.cidr == "0.0.0.0/0"
+//	    msg := sprintf("%s opens %s to the internet", [input.skill.name, input.params.port])
+//	}
+//
+// A result may also be an object with msg, severity and policy fields.
+package rego
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	opa "github.com/open-policy-agent/opa/v1/rego"
+
+	"github.com/parth14193/ownbot/pkg/policy"
+)
+
+// Error is a parse or compile error located in a module.
+type Error struct {
+	File string
+	Line int
+	Col  int
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	if e.Col > 0 {
+		return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Col, e.Msg)
+	}
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Msg)
+}
+
+// Module is a parsed Rego module.
+type Module struct {
+	File    string
+	Package string
+	ast     *ast.Module
+}
+
+// ParseModule parses src as Rego v1, falling back to v0 syntax for older
+// modules. filename is used in error messages.
+func ParseModule(filename, src string) (*Module, error) {
+	m, err := ast.ParseModuleWithOpts(filename, src, ast.ParserOptions{RegoVersion: ast.RegoV1})
+	if err != nil {
+		m0, err0 := ast.ParseModuleWithOpts(filename, src, ast.ParserOptions{RegoVersion: ast.RegoV0})
+		if err0 != nil {
+			// Report the v0 errors only when v1 rejected the module for
+			// being written in v0 syntax; otherwise they are noise.
+			if writtenInV0(err) {
+				return nil, moduleErrors(filename, err0)
+			}
+			return nil, moduleErrors(filename, err)
+		}
+		m = m0
+	}
+	if m == nil {
+		return nil, &Error{File: filename, Line: 1, Msg: "empty module"}
+	}
+	return &Module{
+		File:    filename,
+		Package: strings.TrimPrefix(m.Package.Path.String(), "data."),
+		ast:     m,
+	}, nil
+}
+
+func writtenInV0(err error) bool {
+	var errs ast.Errors
+	if !errors.As(err, &errs) {
+		return false
+	}
+	for _, e := range errs {
+		if strings.Contains(e.Message, "keyword is required") {
+			return true
+		}
+	}
+	return false
+}
+
+// moduleErrors converts OPA's errors into located *Error values.
+func moduleErrors(filename string, err error) error {
+	var errs ast.Errors
+	if !errors.As(err, &errs) {
+		return &Error{File: filename, Line: 1, Msg: err.Error()}
+	}
+	out := make([]error, 0, len(errs))
+	for _, e := range errs {
+		re := &Error{File: filename, Line: 1, Msg: e.Message}
+		if e.Location != nil {
+			if e.Location.File != "" {
+				re.File = e.Location.File
+			}
+			re.Line, re.Col = e.Location.Row, e.Location.Col
+		}
+		out = append(out, re)
+	}
+	return errors.Join(out...)
+}
+
+// Backend is a policy.Backend that evaluates compiled Rego modules.
+type Backend struct {
+	modules []*Module
+	queries []resultQuery
+}
+
+var _ policy.Backend = (*Backend)(nil)
+
+type resultQuery struct {
+	pkg   string
+	rule  resultRule
+	query opa.PreparedEvalQuery
+}
+
+// NewBackend compiles modules together and prepares their result rules.
+// Nothing is returned if any module fails to compile; the error lists every
+// problem with file and line.
+func NewBackend(modules []*Module) (*Backend, error) {
+	parsed := make(map[string]*ast.Module, len(modules))
+	for _, m := range modules {
+		if _, dup := parsed[m.File]; dup {
+			return nil, &Error{File: m.File, Line: 1, Msg: "module loaded twice"}
+		}
+		parsed[m.File] = m.ast
+	}
+	compiler := ast.NewCompiler()
+	compiler.Compile(parsed)
+	if compiler.Failed() {
+		return nil, moduleErrors("", compiler.Errors)
+	}
+
+	b := &Backend{modules: modules}
+	seen := make(map[string]bool)
+	for _, m := range modules {
+		for _, rr := range resultRules {
+			key := m.Package + "." + rr.name
+			if seen[key] || !definesRule(m.ast, rr.name) {
+				continue
+			}
+			seen[key] = true
+			pq, err := opa.New(
+				opa.Query("data."+key),
+				opa.Compiler(compiler),
+			).PrepareForEval(context.Background())
+			if err != nil {
+				return nil, fmt.Errorf("failed to prepare %s: %w", key, err)
+			}
+			b.queries = append(b.queries, resultQuery{pkg: m.Package, rule: rr, query: pq})
+		}
+	}
+	sort.SliceStable(b.queries, func(i, j int) bool { return b.queries[i].pkg < b.queries[j].pkg })
+	return b, nil
+}
+
+func definesRule(m *ast.Module, name string) bool {
+	for _, r := range m.Rules {
+		if ref := r.Head.Ref(); len(ref) > 0 && ref[0].Value.Compare(ast.Var(name)) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadDir parses and compiles every .rego file under dir. Nothing is
+// returned if any module is invalid; the error lists every problem with file
+// and line.
+func LoadDir(dir string) (*Backend, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && filepath.Ext(path) == ".rego" {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy directory: %w", err)
+	}
+	sort.Strings(files)
+
+	var modules []*Module
+	var errs []error
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read rego module: %w", err))
+			continue
+		}
+		m, err := ParseModule(path, string(data))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		modules = append(modules, m)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return NewBackend(modules)
+}
+
+// CompileBundle compiles the Rego modules of a verified bundle, naming them
+// <bundle>/<file>. It returns a nil Backend when the bundle has none and
+// satisfies policy.BundleCompiler.
+func CompileBundle(b *policy.Bundle) (policy.Backend, error) {
+	var modules []*Module
+	var errs []error
+	for _, name := range b.Files() {
+		if path.Ext(name) != ".rego" {
+			continue
+		}
+		src, _ := b.File(name)
+		m, err := ParseModule(b.Manifest.Name+"/"+name, string(src))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		modules = append(modules, m)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	if len(modules) == 0 {
+		return nil, nil
+	}
+	backend, err := NewBackend(modules)
+	if err != nil {
+		return nil, err
+	}
+	return backend, nil
+}
+
+// Modules returns the loaded modules.
+func (b *Backend) Modules() []*Module {
+	return b.modules
+}
+
+// Name identifies the backend in violations and logs.
+func (b *Backend) Name() string {
+	return "rego"
+}
+
+type resultRule struct {
+	name        string
+	enforcement policy.EnforcementLevel
+	severity    policy.Severity
+}
+
+// resultRules maps result rule names to their default enforcement and severity.
+var resultRules = []resultRule{
+	{"deny", policy.EnforcementDeny, policy.SeverityCritical},
+	{"violation", policy.EnforcementDeny, policy.SeverityCritical},
+	{"warn", policy.EnforcementWarn, policy.SeverityWarning},
+}
+
+// Evaluate runs every package's deny, violation and warn rules against in.
+func (b *Backend) Evaluate(in *policy.Input) ([]policy.Violation, error) {
+	doc, err := normalize(in.Document())
+	if err != nil {
+		return nil, fmt.Errorf("invalid input document: %w", err)
+	}
+	ctx := context.Background()
+
+	var violations []policy.Violation
+	for _, q := range b.queries {
+		rs, err := q.query.Eval(ctx, opa.EvalInput(doc))
+		if err != nil {
+			return nil, fmt.Errorf("data.%s.%s: %w", q.pkg, q.rule.name, err)
+		}
+		for _, r := range rs {
+			for _, expr := range r.Expressions {
+				for _, result := range results(expr.Value) {
+					violations = append(violations, toViolation(q.pkg, q.rule.name, result, q.rule.enforcement, q.rule.severity))
+				}
+			}
+		}
+	}
+	return violations, nil
+}
+
+// normalize round-trips v through JSON so structs are seen by policies the
+// way their JSON tags describe them.
+func normalize(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// results flattens a rule value into individual results. A complete rule
+// that is simply true counts as one result; false counts as none.
+func results(v interface{}) []interface{} {
+	switch t := v.(type) {
+	case []interface{}:
+		return t
+	case bool:
+		if t {
+			return []interface{}{true}
+		}
+		return nil
+	case nil:
+		return nil
+	}
+	return []interface{}{v}
+}
+
+func toViolation(pkg, rule string, result interface{}, enforcement policy.EnforcementLevel, severity policy.Severity) policy.Violation {
+	v := policy.Violation{
+		PolicyName:  pkg,
+		Description: fmt.Sprintf("Rego rule data.%s.%s", pkg, rule),
+		Severity:    severity,
+		Enforcement: enforcement,
+	}
+	switch t := result.(type) {
+	case string:
+		v.Reason = t
+	case map[string]interface{}:
+		for _, key := range []string{"msg", "message"} {
+			if s, ok := t[key].(string); ok {
+				v.Reason = s
+				break
+			}
+		}
+		for _, key := range []string{"policy", "name"} {
+			if s, ok := t[key].(string); ok && s != "" {
+				v.PolicyName = s
+				break
+			}
+		}
+		if s, ok := t["severity"].(string); ok {
+			if sev, ok := parseSeverity(s); ok {
+				v.Severity = sev
+			}
+		}
+		if v.Reason == "" {
+			v.Reason = canonical(t)
+		}
+	case bool:
+		v.Reason = fmt.Sprintf("%s rule matched", rule)
+	default:
+		v.Reason = canonical(t)
+	}
+	return v
+}
+
+// canonical renders a result as compact JSON for use as a reason.
+func canonical(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func parseSeverity(s string) (policy.Severity, bool) {
+	switch strings.ToLower(s) {
+	case "info", "low":
+		return policy.SeverityInfo, true
+	case "warning", "medium":
+		return policy.SeverityWarning, true
+	case "critical", "high":
+		return policy.SeverityCritical, true
+	}
+	return "", false
+}
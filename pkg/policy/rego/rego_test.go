@@ -0,0 +1,291 @@
+package rego_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/parth14193/ownbot/pkg/core"
+	"github.com/parth14193/ownbot/pkg/policy"
+	"github.com/parth14193/ownbot/pkg/policy/rego"
+)
+
+const sgModule = `package infracore.sg
+
+internet := {"0.0.0.0/0", "::/0"}
+
+admin_ports := [22, 3389]
+
+deny contains msg if {
+	input.environment == "production"
+	internet[input.params.cidr]
+	to_number(input.params.port) in admin_ports
+	msg := sprintf("port %v open to %s", [input.params.port, input.params.cidr])
+}
+
+warn contains {"msg": "no ticket referenced", "severity": "info", "policy": "ticket_required"} if {
+	not input.params._ticket
+}
+`
+
+// safetyModule is written in Rego v0 syntax.
+const safetyModule = `package infracore.safety
+
+import future.keywords.in
+
+default max_radius := 10
+
+deny[{"msg": msg, "severity": "medium"}] {
+	input.safety.blast_radius > max_radius
+	msg := sprintf("blast radius %d exceeds %d", [input.safety.blast_radius, max_radius])
+}
+
+deny[msg] {
+	some i
+	r := input.safety.affected_resources[i]
+	startswith(r, "prod-db")
+	not admins[input.user]
+	msg := sprintf("%s may not touch %s", [input.user, r])
+}
+
+admins := {u | some u in ["alice", "bob"]}
+
+untagged := [k | input.params.tags[k] == ""]
+
+warn[msg] {
+	count(untagged) > 0
+	msg := concat(", ", untagged)
+}
+`
+
+func newBackend(t *testing.T, sources ...string) *rego.Backend {
+	t.Helper()
+	if len(sources) == 0 {
+		sources = []string{sgModule, safetyModule}
+	}
+	var modules []*rego.Module
+	for i, src := range sources {
+		m, err := rego.ParseModule(fmt.Sprintf("m%d.rego", i), src)
+		if err != nil {
+			t.Fatalf("module %d: %v", i, err)
+		}
+		modules = append(modules, m)
+	}
+	b, err := rego.NewBackend(modules)
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	return b
+}
+
+func TestDenyAndWarn(t *testing.T) {
+	b := newBackend(t)
+	skill := &core.Skill{Name: "aws.sg.modify"}
+
+	vs, err := b.Evaluate(&policy.Input{
+		Skill:       skill,
+		Params:      map[string]interface{}{"cidr": "0.0.0.0/0", "port": "22"},
+		Environment: "production",
+	})
+	if err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	var deny, warn *policy.Violation
+	for i := range vs {
+		switch vs[i].PolicyName {
+		case "infracore.sg":
+			deny = &vs[i]
+		case "ticket_required":
+			warn = &vs[i]
+		}
+	}
+	if deny == nil || deny.Enforcement != policy.EnforcementDeny || deny.Severity != policy.SeverityCritical {
+		t.Fatalf("expected critical deny from infracore.sg, got %+v", vs)
+	}
+	if deny.Reason != "port 22 open to 0.0.0.0/0" {
+		t.Errorf("unexpected reason %q", deny.Reason)
+	}
+	if warn == nil || warn.Enforcement != policy.EnforcementWarn || warn.Severity != policy.SeverityInfo {
+		t.Fatalf("expected info warning from ticket_required, got %+v", vs)
+	}
+
+	vs, err = b.Evaluate(&policy.Input{
+		Skill:       skill,
+		Params:      map[string]interface{}{"cidr": "10.0.0.0/8", "port": "22", "_ticket": "OPS-1"},
+		Environment: "production",
+	})
+	if err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	if len(vs) != 0 {
+		t.Errorf("expected no violations, got %+v", vs)
+	}
+}
+
+func TestUserAndSafetyInput(t *testing.T) {
+	b := newBackend(t)
+	in := &policy.Input{
+		Skill:       &core.Skill{Name: "aws.rds.modify"},
+		Params:      map[string]interface{}{"_ticket": "OPS-1", "tags": map[string]interface{}{"team": "", "owner": "x", "cost": ""}},
+		Environment: "staging",
+		User:        "mallory",
+		Safety: &core.SafetyReport{
+			BlastRadius:       25,
+			AffectedResources: []string{"prod-db-1", "cache-1"},
+		},
+	}
+	vs, err := b.Evaluate(in)
+	if err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	reasons := map[string]policy.Violation{}
+	for _, v := range vs {
+		reasons[v.Reason] = v
+	}
+	if v, ok := reasons["blast radius 25 exceeds 10"]; !ok || v.Severity != policy.SeverityWarning {
+		t.Errorf("expected blast radius deny with WARNING severity, got %+v", vs)
+	}
+	if _, ok := reasons["mallory may not touch prod-db-1"]; !ok {
+		t.Errorf("expected user deny, got %+v", vs)
+	}
+	if v, ok := reasons["cost, team"]; !ok || v.Enforcement != policy.EnforcementWarn {
+		t.Errorf("expected untagged warning, got %+v", vs)
+	}
+
+	in.User = "alice"
+	in.Safety.BlastRadius = 3
+	vs, _ = b.Evaluate(in)
+	for _, v := range vs {
+		if v.Enforcement == policy.EnforcementDeny {
+			t.Errorf("unexpected deny for admin: %+v", v)
+		}
+	}
+}
+
+func TestEngineIntegration(t *testing.T) {
+	e := policy.NewEngine(policy.EnforcementDeny)
+	e.AddBackend(newBackend(t))
+	skill := &core.Skill{Name: "aws.sg.modify"}
+	result := e.Evaluate(skill, map[string]interface{}{"cidr": "::/0", "port": 3389, "_ticket": "T"}, "production")
+	if result.Passed || len(result.Violations) != 1 {
+		t.Fatalf("expected one blocking violation, got %+v", result)
+	}
+	if result.Violations[0].SkillName != "aws.sg.modify" || result.Violations[0].Environment != "production" {
+		t.Errorf("violation not attributed to action: %+v", result.Violations[0])
+	}
+}
+
+func TestFullLanguage(t *testing.T) {
+	b := newBackend(t, `package t
+
+is_prod(env) if lower(env) == "production"
+
+tagged(r) := r.tags.owner != ""
+
+deny contains msg if {
+	is_prod(input.environment)
+	every r in input.params.resources { tagged(r) }
+	some r in input.params.resources
+	msg := sprintf("%s checked", [r.name])
+}
+
+level := "high" if input.params.count > 10
+else := "low"
+
+warn contains level if data.t.level == "high"
+`)
+	vs, err := b.Evaluate(&policy.Input{
+		Skill: &core.Skill{Name: "x"},
+		Params: map[string]interface{}{
+			"count":     11,
+			"resources": []interface{}{map[string]interface{}{"name": "a", "tags": map[string]interface{}{"owner": "ops"}}},
+		},
+		Environment: "PRODUCTION",
+	})
+	if err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	reasons := map[string]policy.EnforcementLevel{}
+	for _, v := range vs {
+		reasons[v.Reason] = v.Enforcement
+	}
+	if reasons["a checked"] != policy.EnforcementDeny || reasons["high"] != policy.EnforcementWarn || len(vs) != 2 {
+		t.Errorf("expected a deny and a warning, got %+v", vs)
+	}
+}
+
+func TestRuntimeErrorsAreReturned(t *testing.T) {
+	b := newBackend(t, `package t
+
+deny := msg if msg := input.params.a
+deny := msg if msg := input.params.b
+`)
+	_, err := b.Evaluate(&policy.Input{
+		Skill:  &core.Skill{Name: "x"},
+		Params: map[string]interface{}{"a": "one", "b": "two"},
+	})
+	if err == nil {
+		t.Fatal("expected conflicting complete rule values to be an error")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := map[string]string{
+		"package p\n\ndeny contains msg if {\n  msg := \n}\n": "bad.rego:5:1: unexpected }",
+		"package p\nallow if {\n  input.x == \"y\n}\n":        "bad.rego:3:",
+		"package p\n\ndeny[msg] {\n  msg := \n}\n":            "bad.rego:5:1: unexpected }",
+		"deny contains msg if { msg := 1 }\n":                 "bad.rego:1:",
+	}
+	for src, want := range cases {
+		_, err := rego.ParseModule("bad.rego", src)
+		var re *rego.Error
+		if !errors.As(err, &re) {
+			t.Errorf("%q: expected *rego.Error, got %v", src, err)
+			continue
+		}
+		if !strings.HasPrefix(err.Error(), want) {
+			t.Errorf("%q: error %q, want prefix %q", src, err, want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	cases := map[string]string{
+		"package p\nallow if {\n  unknown_fn(1)\n}\n": "bad.rego:3:",
+		"package p\ndefault a := 1\ndefault a := 2\n": "bad.rego:1:1: multiple default rules",
+		"package p\nallow if {\n  x == 1\n}\n":        "bad.rego:3:",
+	}
+	for src, want := range cases {
+		m, err := rego.ParseModule("bad.rego", src)
+		if err != nil {
+			t.Errorf("%q: parse: %v", src, err)
+			continue
+		}
+		_, err = rego.NewBackend([]*rego.Module{m})
+		var re *rego.Error
+		if !errors.As(err, &re) || !strings.HasPrefix(err.Error(), want) {
+			t.Errorf("%q: error %v, want prefix %q", src, err, want)
+		}
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "sg.rego"), []byte(sgModule), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	b, err := rego.LoadDir(dir)
+	if err != nil || len(b.Modules()) != 1 {
+		t.Fatalf("LoadDir: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "broken.rego"), []byte("package x\nallow {\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rego.LoadDir(dir); err == nil || !strings.Contains(err.Error(), "broken.rego:") {
+		t.Errorf("expected error naming broken.rego, got %v", err)
+	}
+}
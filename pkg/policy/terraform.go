@@ -0,0 +1,241 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Plan is the subset of a Terraform plan in JSON form (`terraform show -json
+// plan.out`) that policies inspect.
+type Plan struct {
+	ResourceChanges []*ResourceChange `json:"resource_changes"`
+}
+
+// ResourceChange is a managed resource that a plan creates or updates, with
+// its planned attribute values.
+type ResourceChange struct {
+	Address string                 `json:"address"` // e.g. aws_s3_bucket.logs
+	Type    string                 `json:"type"`
+	Name    string                 `json:"name"`
+	Actions []string               `json:"actions"`
+	After   map[string]interface{} `json:"after"`
+}
+
+// ResourceCheckFunc inspects one planned resource and returns a reason for
+// every violation found.
+type ResourceCheckFunc func(rc *ResourceChange) []string
+
+type planJSON struct {
+	ResourceChanges []struct {
+		Address string `json:"address"`
+		Mode    string `json:"mode"`
+		Type    string `json:"type"`
+		Name    string `json:"name"`
+		Change  struct {
+			Actions []string               `json:"actions"`
+			After   map[string]interface{} `json:"after"`
+		} `json:"change"`
+	} `json:"resource_changes"`
+}
+
+// ParsePlan parses Terraform plan JSON. Data sources, deletions, reads and
+// no-op changes are dropped: only resources being created or updated are kept.
+func ParsePlan(data []byte) (*Plan, error) {
+	var raw planJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid terraform plan JSON: %w", err)
+	}
+	plan := &Plan{}
+	for _, rc := range raw.ResourceChanges {
+		if rc.Mode == "data" || rc.Change.After == nil {
+			continue
+		}
+		if !hasAction(rc.Change.Actions, "create") && !hasAction(rc.Change.Actions, "update") {
+			continue
+		}
+		plan.ResourceChanges = append(plan.ResourceChanges, &ResourceChange{
+			Address: rc.Address,
+			Type:    rc.Type,
+			Name:    rc.Name,
+			Actions: rc.Change.Actions,
+			After:   rc.Change.After,
+		})
+	}
+	return plan, nil
+}
+
+// LoadPlan reads Terraform plan JSON from a file.
+func LoadPlan(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read terraform plan: %w", err)
+	}
+	return ParsePlan(data)
+}
+
+func hasAction(actions []string, action string) bool {
+	for _, a := range actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// document returns the plan in the shape policy rules and Rego modules see
+// under input.plan.
+func (p *Plan) document() map[string]interface{} {
+	changes := make([]interface{}, 0, len(p.ResourceChanges))
+	for _, rc := range p.ResourceChanges {
+		changes = append(changes, map[string]interface{}{
+			"address": rc.Address,
+			"type":    rc.Type,
+			"name":    rc.Name,
+			"actions": stringsToList(rc.Actions),
+			"after":   rc.After,
+		})
+	}
+	return map[string]interface{}{"resource_changes": changes}
+}
+
+// ── Resource checks for built-in policies ──────────────────────
+
+var publicACLs = map[string]bool{"public-read": true, "public-read-write": true}
+
+func publicS3Resource(rc *ResourceChange) []string {
+	switch rc.Type {
+	case "aws_s3_bucket", "aws_s3_bucket_acl":
+		if acl, _ := rc.After["acl"].(string); publicACLs[acl] {
+			return []string{fmt.Sprintf("%s uses public ACL '%s'", rc.Address, acl)}
+		}
+	case "aws_s3_bucket_public_access_block":
+		var open []string
+		for _, key := range []string{"block_public_acls", "block_public_policy", "ignore_public_acls", "restrict_public_buckets"} {
+			if v, ok := rc.After[key].(bool); ok && !v {
+				open = append(open, key)
+			}
+		}
+		if len(open) > 0 {
+			return []string{fmt.Sprintf("%s disables %s", rc.Address, strings.Join(open, ", "))}
+		}
+	}
+	return nil
+}
+
+func wideOpenSGResource(sensitivePorts []int) ResourceCheckFunc {
+	return func(rc *ResourceChange) []string {
+		var rules []map[string]interface{}
+		switch rc.Type {
+		case "aws_security_group":
+			for _, r := range asList(rc.After["ingress"]) {
+				if m, ok := r.(map[string]interface{}); ok {
+					rules = append(rules, m)
+				}
+			}
+		case "aws_security_group_rule":
+			if t, _ := rc.After["type"].(string); t == "ingress" {
+				rules = append(rules, rc.After)
+			}
+		case "aws_vpc_security_group_ingress_rule":
+			rules = append(rules, rc.After)
+		}
+
+		var reasons []string
+		for _, rule := range rules {
+			var cidrs []string
+			for _, key := range []string{"cidr_blocks", "ipv6_cidr_blocks"} {
+				for _, c := range asList(rule[key]) {
+					if s, ok := c.(string); ok {
+						cidrs = append(cidrs, s)
+					}
+				}
+			}
+			for _, key := range []string{"cidr_ipv4", "cidr_ipv6"} {
+				if s, ok := rule[key].(string); ok {
+					cidrs = append(cidrs, s)
+				}
+			}
+			from, to, all := portRange(rule)
+			for _, cidr := range cidrs {
+				if cidr != "0.0.0.0/0" && cidr != "::/0" {
+					continue
+				}
+				for _, port := range sensitivePorts {
+					if all || (port >= from && port <= to) {
+						reasons = append(reasons, fmt.Sprintf("%s allows ingress %s:%d — use VPN or bastion host", rc.Address, cidr, port))
+					}
+				}
+			}
+		}
+		return reasons
+	}
+}
+
+// portRange returns a rule's port range; all is true for all-protocol rules.
+func portRange(rule map[string]interface{}) (from, to int, all bool) {
+	proto := fmt.Sprintf("%v", rule["protocol"])
+	if proto == "" || proto == "<nil>" {
+		proto = fmt.Sprintf("%v", rule["ip_protocol"])
+	}
+	if proto == "-1" || proto == "all" {
+		return 0, 65535, true
+	}
+	f, _ := rule["from_port"].(float64)
+	t, _ := rule["to_port"].(float64)
+	return int(f), int(t), false
+}
+
+// encryptionFlags lists the attribute that must be true, per resource type.
+var encryptionFlags = map[string]string{
+	"aws_ebs_volume":                    "encrypted",
+	"aws_db_instance":                   "storage_encrypted",
+	"aws_rds_cluster":                   "storage_encrypted",
+	"aws_efs_file_system":               "encrypted",
+	"aws_redshift_cluster":              "encrypted",
+	"aws_docdb_cluster":                 "storage_encrypted",
+	"aws_neptune_cluster":               "storage_encrypted",
+	"aws_elasticache_replication_group": "at_rest_encryption_enabled",
+}
+
+func encryptionResource(rc *ResourceChange) []string {
+	flag, ok := encryptionFlags[rc.Type]
+	if !ok {
+		return nil
+	}
+	if v, _ := rc.After[flag].(bool); !v {
+		return []string{fmt.Sprintf("%s has %s disabled — storage must be encrypted at rest", rc.Address, flag)}
+	}
+	return nil
+}
+
+func requiredTagsResource(requiredTags []string) ResourceCheckFunc {
+	return func(rc *ResourceChange) []string {
+		tags, ok := rc.After["tags_all"].(map[string]interface{})
+		if !ok {
+			tags, ok = rc.After["tags"].(map[string]interface{})
+		}
+		if !ok {
+			if _, taggable := rc.After["tags"]; !taggable {
+				return nil // resource type has no tags
+			}
+			tags = map[string]interface{}{}
+		}
+		var missing []string
+		for _, tag := range requiredTags {
+			if _, exists := tags[tag]; !exists {
+				missing = append(missing, tag)
+			}
+		}
+		if len(missing) == 0 {
+			return nil
+		}
+		return []string{fmt.Sprintf("%s is missing required tags: %s", rc.Address, strings.Join(missing, ", "))}
+	}
+}
+
+func asList(v interface{}) []interface{} {
+	list, _ := v.([]interface{})
+	return list
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/parth14193/ownbot/pkg/core"
+	"github.com/parth14193/ownbot/pkg/environment"
 )
 
 // Role represents a user role with specific permissions.
@@ -42,6 +43,7 @@ type Engine struct {
 	users       map[string]*User
 	permissions map[Role]*Permission
 	enabled     bool
+	classifier  *environment.Classifier
 }
 
 // NewEngine creates a new RBAC engine with default role permissions.
@@ -50,11 +52,16 @@ func NewEngine() *Engine {
 		users:       make(map[string]*User),
 		permissions: make(map[Role]*Permission),
 		enabled:     true,
+		classifier:  environment.DefaultClassifier(),
 	}
 	e.loadDefaultPermissions()
 	return e
 }
 
+// SetClassifier replaces the environment tier classifier. Allowed environments
+// may then name either a concrete environment or a tier.
+func (e *Engine) SetClassifier(classifier *environment.Classifier) { e.classifier = classifier }
+
 // SetEnabled enables or disables RBAC enforcement.
 func (e *Engine) SetEnabled(enabled bool) { e.enabled = enabled }
 
@@ -91,13 +98,17 @@ func (e *Engine) CanExecute(username string, skill *core.Skill, env string) (boo
 		return false, fmt.Sprintf("No permissions defined for role: %s", user.Role)
 	}
 
-	// Check risk level
-	if !containsRisk(perm.AllowedRiskLevels, skill.RiskLevel) {
-		return false, fmt.Sprintf("Role '%s' cannot execute %s-risk operations", user.Role, skill.RiskLevel)
+	// Check risk level, escalated to the environment tier floor
+	risk := skill.RiskLevel
+	if floor := e.classifier.EscalationFloor(env); floor > risk {
+		risk = floor
+	}
+	if !containsRisk(perm.AllowedRiskLevels, risk) {
+		return false, fmt.Sprintf("Role '%s' cannot execute %s-risk operations", user.Role, risk)
 	}
 
 	// Check environment
-	if len(perm.AllowedEnvironments) > 0 && !containsStr(perm.AllowedEnvironments, env) {
+	if len(perm.AllowedEnvironments) > 0 && !e.classifier.MatchesAny(env, perm.AllowedEnvironments) {
 		return false, fmt.Sprintf("Role '%s' cannot access environment '%s'", user.Role, env)
 	}
 
@@ -176,15 +187,6 @@ func containsRisk(levels []core.RiskLevel, target core.RiskLevel) bool {
 	return false
 }
 
-func containsStr(items []string, target string) bool {
-	for _, i := range items {
-		if strings.EqualFold(i, target) {
-			return true
-		}
-	}
-	return false
-}
-
 func matchPattern(name, pattern string) bool {
 	if pattern == "*" {
 		return true
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/parth14193/ownbot/pkg/core"
+	"github.com/parth14193/ownbot/pkg/environment"
 	"github.com/parth14193/ownbot/pkg/rbac"
 )
 
@@ -99,3 +100,29 @@ func TestDisabledRBAC(t *testing.T) {
 		t.Error("disabled RBAC should allow everything")
 	}
 }
+
+func TestEnvironmentTiers(t *testing.T) {
+	e := rbac.NewEngine()
+	e.SetClassifier(environment.NewClassifier(
+		&environment.Tier{Name: "production", Patterns: []string{"prod-*"}, Production: true, EscalationFloor: core.RiskHigh},
+	))
+	e.AddUser("admin1", rbac.RoleAdmin, nil)
+	e.AddUser("ops1", rbac.RoleOperator, nil)
+
+	skill := &core.Skill{Name: "aws.ec2.scale", RiskLevel: core.RiskMedium}
+	if ok, reason := e.CanExecute("admin1", skill, "prod-eu"); !ok {
+		t.Errorf("admin should access prod-eu through the production tier: %s", reason)
+	}
+
+	critical := &core.Skill{Name: "terraform.apply", RiskLevel: core.RiskCritical}
+	if ok, _ := e.CanExecute("admin1", critical, "prod-eu"); ok {
+		t.Error("admin should NOT execute CRITICAL operations")
+	}
+
+	e.SetClassifier(environment.NewClassifier(
+		&environment.Tier{Name: "qa", EscalationFloor: core.RiskHigh},
+	))
+	if ok, _ := e.CanExecute("ops1", skill, "qa"); ok {
+		t.Error("tier escalation floor should lift MEDIUM to HIGH for operators")
+	}
+}
@@ -0,0 +1,31 @@
+package safety
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/parth14193/ownbot/pkg/config"
+)
+
+// LoadConfig records the dependency chains from configuration. Each chain
+// element is a "kind/name" resource, e.g. ingress/web → service/web.
+func (g *Graph) LoadConfig(chains []*config.ResourceChainConfig) error {
+	for i, cc := range chains {
+		if cc == nil {
+			continue
+		}
+		if len(cc.Chain) < 2 {
+			return fmt.Errorf("resource_graph[%d]: a chain needs at least two resources", i)
+		}
+		chain := make([]Resource, 0, len(cc.Chain))
+		for _, id := range cc.Chain {
+			kind, name, ok := strings.Cut(id, "/")
+			if !ok || kind == "" || name == "" {
+				return fmt.Errorf("resource_graph[%d]: invalid resource '%s' (want kind/name)", i, id)
+			}
+			chain = append(chain, Resource{Kind: kind, Name: name})
+		}
+		g.AddChain(chain...)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package safety
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/parth14193/ownbot/pkg/core"
+)
+
+// Resource identifies a single node in the resource dependency graph.
+type Resource struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// ID returns the canonical "kind/name" identifier of the resource.
+func (r Resource) ID() string {
+	return fmt.Sprintf("%s/%s", r.Kind, r.Name)
+}
+
+// Graph models dependencies between infrastructure resources.
+// An edge upstream → downstream means a change to upstream impacts downstream,
+// e.g. ingress → service → deployment → pods or vpc → subnet → instance.
+type Graph struct {
+	mu        sync.RWMutex
+	resources map[string]Resource
+	edges     map[string][]string
+}
+
+// NewGraph creates an empty dependency graph.
+func NewGraph() *Graph {
+	return &Graph{
+		resources: make(map[string]Resource),
+		edges:     make(map[string][]string),
+	}
+}
+
+// AddResource registers a resource and returns its reference.
+func (g *Graph) AddResource(kind, name string) Resource {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	r := Resource{Kind: kind, Name: name}
+	g.resources[r.ID()] = r
+	return r
+}
+
+// AddDependency records that a change to upstream impacts downstream.
+// Both resources are registered if they are not already known.
+func (g *Graph) AddDependency(upstream, downstream Resource) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.resources[upstream.ID()] = upstream
+	g.resources[downstream.ID()] = downstream
+	for _, id := range g.edges[upstream.ID()] {
+		if id == downstream.ID() {
+			return
+		}
+	}
+	g.edges[upstream.ID()] = append(g.edges[upstream.ID()], downstream.ID())
+}
+
+// AddChain records a linear dependency chain, e.g. ingress → service → deployment.
+func (g *Graph) AddChain(chain ...Resource) {
+	for i := 0; i+1 < len(chain); i++ {
+		g.AddDependency(chain[i], chain[i+1])
+	}
+}
+
+// Find looks up a registered resource by kind and name.
+func (g *Graph) Find(kind, name string) (Resource, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	r, ok := g.resources[Resource{Kind: kind, Name: name}.ID()]
+	return r, ok
+}
+
+// Len returns the number of registered resources.
+func (g *Graph) Len() int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return len(g.resources)
+}
+
+// Downstream returns every resource transitively impacted by a change to r,
+// in breadth-first order. The resource itself is not included.
+func (g *Graph) Downstream(r Resource) []Resource {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var result []Resource
+	visited := map[string]bool{r.ID(): true}
+	queue := []string{r.ID()}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, child := range g.sortedEdges(id) {
+			if visited[child] {
+				continue
+			}
+			visited[child] = true
+			result = append(result, g.resources[child])
+			queue = append(queue, child)
+		}
+	}
+	return result
+}
+
+// ImpactTree returns the downstream impact tree rooted at r.
+// Resources reachable through several paths appear once, under the first path found.
+func (g *Graph) ImpactTree(r Resource) *core.ImpactNode {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	visited := map[string]bool{r.ID(): true}
+	root := &core.ImpactNode{Kind: r.Kind, Name: r.Name}
+	g.buildTree(root, r.ID(), visited)
+	return root
+}
+
+func (g *Graph) buildTree(node *core.ImpactNode, id string, visited map[string]bool) {
+	for _, childID := range g.sortedEdges(id) {
+		if visited[childID] {
+			continue
+		}
+		visited[childID] = true
+		child := g.resources[childID]
+		childNode := &core.ImpactNode{Kind: child.Kind, Name: child.Name}
+		node.Children = append(node.Children, childNode)
+		g.buildTree(childNode, childID, visited)
+	}
+}
+
+// sortedEdges returns the outgoing edges of id in a stable order.
+func (g *Graph) sortedEdges(id string) []string {
+	edges := append([]string(nil), g.edges[id]...)
+	sort.Strings(edges)
+	return edges
+}
+
+// paramResourceKinds maps skill parameter keys to resource kinds in the graph.
+var paramResourceKinds = []struct {
+	param string
+	kind  string
+}{
+	{"ingress", "ingress"},
+	{"service", "service"},
+	{"deployment", "deployment"},
+	{"vpc_id", "vpc"},
+	{"subnet_id", "subnet"},
+	{"instance_id", "instance"},
+	{"asg_name", "asg"},
+	{"bucket_name", "bucket"},
+	{"function_name", "function"},
+	{"secret_id", "secret"},
+	{"release_name", "release"},
+	{"app_name", "app"},
+	{"vm_name", "vm"},
+}
@@ -6,15 +6,37 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/parth14193/ownbot/pkg/calendar"
 	"github.com/parth14193/ownbot/pkg/core"
+	"github.com/parth14193/ownbot/pkg/environment"
 )
 
 // Layer evaluates the safety characteristics of skill executions.
-type Layer struct{}
+type Layer struct {
+	graph      *Graph
+	classifier *environment.Classifier
+	calendars  *calendar.Registry
+}
 
-// NewLayer creates a new SafetyLayer.
+// NewLayer creates a new SafetyLayer using the default environment tiers.
 func NewLayer() *Layer {
-	return &Layer{}
+	return &Layer{classifier: environment.DefaultClassifier()}
+}
+
+// SetCalendars attaches change calendars; mutations inside a freeze or
+// outside a required maintenance window are escalated to CRITICAL.
+func (l *Layer) SetCalendars(calendars *calendar.Registry) {
+	l.calendars = calendars
+}
+
+// SetClassifier replaces the environment tier classifier.
+func (l *Layer) SetClassifier(classifier *environment.Classifier) {
+	l.classifier = classifier
+}
+
+// SetGraph attaches a resource dependency graph used to list downstream impact.
+func (l *Layer) SetGraph(graph *Graph) {
+	l.graph = graph
 }
 
 // Evaluate produces a SafetyReport for a given skill and its parameters.
@@ -31,13 +53,9 @@ func (l *Layer) Evaluate(skill *core.Skill, params map[string]interface{}, env s
 	report.BlastRadius = l.estimateBlastRadius(skill, params)
 	report.AffectedResources = l.identifyAffectedResources(skill, params)
 
-	// Environment-based risk escalation
-	if l.isProductionEnvironment(env) {
-		report.EnvironmentWarning = "⚠️  TARGET ENVIRONMENT IS PRODUCTION — exercise extreme caution"
-		if report.RiskLevel < core.RiskHigh {
-			report.RiskLevel = core.RiskHigh
-		}
-		report.RequiresConfirmation = true
+	// Downstream impact analysis (mutations only)
+	if report.BlastRadius > 0 {
+		l.analyzeImpact(report, params)
 	}
 
 	// Set dry run recommendation
@@ -46,9 +64,64 @@ func (l *Layer) Evaluate(skill *core.Skill, params map[string]interface{}, env s
 	// Generate appropriate confirmation prompt
 	report.ConfirmationPrompt = l.getConfirmationPrompt(report.RiskLevel)
 
+	// Change calendar escalation (mutations only)
+	if l.calendars != nil && report.BlastRadius > 0 {
+		l.applyCalendar(report, params, env)
+	}
+
+	// Environment tier escalation (read-only skills change nothing)
+	if tier := l.classifier.Classify(env); tier != nil && !skill.ReadOnly {
+		l.applyTier(report, tier)
+	}
+
 	return report
 }
 
+// applyCalendar escalates a report when the action falls inside a freeze
+// period or outside a required maintenance window.
+func (l *Layer) applyCalendar(report *core.SafetyReport, params map[string]interface{}, env string) {
+	d := l.calendars.Check(env, calendar.Team(params), l.calendars.Now())
+	if !d.Restricted() && !d.Overridden {
+		return
+	}
+
+	switch {
+	case d.Overridden:
+		report.FreezeWarning = fmt.Sprintf("❄️  CHANGE FREEZE OVERRIDDEN by %s: %s", d.Override.User, d.Reason)
+	case !d.Allowed:
+		report.FreezeWarning = fmt.Sprintf("❄️  CHANGE FREEZE: %s", d.Reason)
+	default:
+		report.FreezeWarning = fmt.Sprintf("❄️  CHANGE RESTRICTED: %s", d.Reason)
+	}
+	report.RiskLevel = core.RiskCritical
+	report.RequiresConfirmation = true
+	report.ConfirmationPrompt = l.getConfirmationPrompt(report.RiskLevel)
+}
+
+// applyTier escalates a report according to the environment tier rules.
+func (l *Layer) applyTier(report *core.SafetyReport, tier *environment.Tier) {
+	if tier.Production {
+		report.EnvironmentWarning = "⚠️  TARGET ENVIRONMENT IS PRODUCTION — exercise extreme caution"
+		report.RequiresConfirmation = true
+	}
+	if report.RiskLevel < tier.EscalationFloor {
+		report.RiskLevel = tier.EscalationFloor
+	}
+	if l.RequiresConfirmation(tier.EscalationFloor) {
+		report.RequiresConfirmation = true
+	}
+	report.ConfirmationPrompt = l.getConfirmationPrompt(report.RiskLevel)
+
+	if tier.ConfirmationPhrase != "" {
+		report.RequiresConfirmation = true
+		report.ConfirmationPhrase = tier.ConfirmationPhrase
+		report.ConfirmationPrompt = fmt.Sprintf(`Type "%s" to proceed or "cancel" to abort`, tier.ConfirmationPhrase)
+	}
+	if tier.MandatoryDryRun && report.DryRunRecommended {
+		report.DryRunRequired = true
+	}
+}
+
 // RequiresConfirmation returns whether a risk level requires user confirmation.
 func (l *Layer) RequiresConfirmation(riskLevel core.RiskLevel) bool {
 	return riskLevel >= core.RiskMedium
@@ -115,10 +188,40 @@ func (l *Layer) identifyAffectedResources(skill *core.Skill, params map[string]i
 	return resources
 }
 
-// isProductionEnvironment checks if the given environment string indicates production.
-func (l *Layer) isProductionEnvironment(env string) bool {
-	env = strings.ToLower(env)
-	return env == "production" || env == "prod" || env == "prd"
+// analyzeImpact walks the dependency graph from every resource named in params
+// and records the downstream resources and impact tree on the report.
+func (l *Layer) analyzeImpact(report *core.SafetyReport, params map[string]interface{}) {
+	if l.graph == nil || params == nil {
+		return
+	}
+
+	seen := make(map[string]bool)
+	roots := 0
+	for _, pk := range paramResourceKinds {
+		val, ok := params[pk.param]
+		if !ok {
+			continue
+		}
+		root, ok := l.graph.Find(pk.kind, fmt.Sprintf("%v", val))
+		if !ok || seen[root.ID()] {
+			continue
+		}
+		seen[root.ID()] = true
+		roots++
+
+		report.ImpactTree = append(report.ImpactTree, l.graph.ImpactTree(root))
+		for _, r := range l.graph.Downstream(root) {
+			if seen[r.ID()] {
+				continue
+			}
+			seen[r.ID()] = true
+			report.DownstreamResources = append(report.DownstreamResources, r.ID())
+		}
+	}
+
+	if impacted := roots + len(report.DownstreamResources); impacted > report.BlastRadius {
+		report.BlastRadius = impacted
+	}
 }
 
 // shouldDryRun returns whether this skill type should default to dry-run mode.
@@ -2,8 +2,12 @@ package safety_test
 
 import (
 	"testing"
+	"time"
 
+	"github.com/parth14193/ownbot/pkg/calendar"
+	"github.com/parth14193/ownbot/pkg/config"
 	"github.com/parth14193/ownbot/pkg/core"
+	"github.com/parth14193/ownbot/pkg/environment"
 	"github.com/parth14193/ownbot/pkg/safety"
 )
 
@@ -47,6 +51,60 @@ func TestEvaluateProductionEscalation(t *testing.T) {
 	}
 }
 
+func TestEvaluateEnvironmentTiers(t *testing.T) {
+	layer := safety.NewLayer()
+	layer.SetClassifier(environment.WithDefaults(
+		&environment.Tier{Name: "pci", Patterns: []string{"pci-*"}, Production: true,
+			EscalationFloor: core.RiskCritical, ConfirmationPhrase: "CONFIRM PCI CHANGE", MandatoryDryRun: true},
+		&environment.Tier{Name: "live", Patterns: []string{"prod-*", "live"}, Production: true, EscalationFloor: core.RiskHigh},
+	))
+	skill := &core.Skill{Name: "k8s.deploy", RiskLevel: core.RiskMedium}
+
+	report := layer.Evaluate(skill, nil, "prod-eu")
+	if report.RiskLevel != core.RiskHigh || report.EnvironmentWarning == "" {
+		t.Errorf("prod-eu should be treated as production, got risk %s", report.RiskLevel)
+	}
+
+	report = layer.Evaluate(skill, nil, "pci-cardholder")
+	if report.RiskLevel != core.RiskCritical {
+		t.Errorf("pci tier should escalate to CRITICAL, got %s", report.RiskLevel)
+	}
+	if !containsStr(report.ConfirmationPrompt, "CONFIRM PCI CHANGE") || report.ConfirmationPhrase != "CONFIRM PCI CHANGE" {
+		t.Errorf("expected tier confirmation phrase, got %q", report.ConfirmationPrompt)
+	}
+	if !report.DryRunRequired {
+		t.Error("pci tier should require a dry run for mutations")
+	}
+
+	report = layer.Evaluate(skill, nil, "prd")
+	if report.RiskLevel != core.RiskHigh || report.EnvironmentWarning == "" {
+		t.Error("built-in production tier should still apply alongside custom tiers")
+	}
+}
+
+func TestEvaluateChangeFreeze(t *testing.T) {
+	now := time.Date(2026, 12, 24, 12, 0, 0, 0, time.UTC)
+	cals := calendar.NewRegistry()
+	cals.SetClock(func() time.Time { return now })
+	cals.Add(&calendar.Calendar{
+		Name:    "holidays",
+		Freezes: []calendar.FreezePeriod{{Name: "christmas", Start: now.Add(-time.Hour), End: now.Add(time.Hour), Action: calendar.ActionBlock}},
+	})
+
+	layer := safety.NewLayer()
+	layer.SetCalendars(cals)
+
+	report := layer.Evaluate(&core.Skill{Name: "aws.ec2.scale", RiskLevel: core.RiskMedium}, nil, "staging")
+	if report.RiskLevel != core.RiskCritical || report.FreezeWarning == "" {
+		t.Errorf("mutation during a freeze should escalate to CRITICAL, got %s", report.RiskLevel)
+	}
+
+	report = layer.Evaluate(&core.Skill{Name: "aws.ec2.list", RiskLevel: core.RiskLow}, nil, "staging")
+	if report.FreezeWarning != "" {
+		t.Error("read-only operations should ignore freezes")
+	}
+}
+
 func TestEvaluateDryRunRecommendation(t *testing.T) {
 	layer := safety.NewLayer()
 
@@ -110,6 +168,75 @@ func TestRequiresConfirmation(t *testing.T) {
 	}
 }
 
+func TestImpactAnalysis(t *testing.T) {
+	g := safety.NewGraph()
+	g.AddChain(
+		g.AddResource("ingress", "web"),
+		g.AddResource("service", "web"),
+		g.AddResource("deployment", "web"),
+		g.AddResource("pod", "web-1"),
+	)
+	g.AddDependency(safety.Resource{Kind: "deployment", Name: "web"}, safety.Resource{Kind: "pod", Name: "web-2"})
+
+	layer := safety.NewLayer()
+	layer.SetGraph(g)
+
+	skill := &core.Skill{Name: "k8s.deploy", RiskLevel: core.RiskHigh}
+	report := layer.Evaluate(skill, map[string]interface{}{"deployment": "web"}, "staging")
+
+	if len(report.DownstreamResources) != 2 {
+		t.Fatalf("expected 2 downstream pods, got %v", report.DownstreamResources)
+	}
+	if report.BlastRadius != 3 {
+		t.Errorf("expected blast radius 3 (deployment + 2 pods), got %d", report.BlastRadius)
+	}
+	if len(report.ImpactTree) != 1 || len(report.ImpactTree[0].Children) != 2 {
+		t.Errorf("expected one root with two children, got %+v", report.ImpactTree)
+	}
+
+	// Read-only operations are not walked
+	report = layer.Evaluate(&core.Skill{Name: "k8s.rollout.status"}, map[string]interface{}{"deployment": "web"}, "staging")
+	if len(report.DownstreamResources) != 0 {
+		t.Error("read-only operations should not report downstream impact")
+	}
+}
+
+func TestGraphDownstreamCycle(t *testing.T) {
+	g := safety.NewGraph()
+	vpc := g.AddResource("vpc", "vpc-1")
+	subnet := g.AddResource("subnet", "subnet-a")
+	g.AddChain(vpc, subnet, g.AddResource("instance", "i-1"))
+	g.AddDependency(subnet, vpc)
+
+	if got := g.Downstream(vpc); len(got) != 2 {
+		t.Errorf("expected 2 downstream resources despite cycle, got %v", got)
+	}
+}
+
+func TestGraphFromConfig(t *testing.T) {
+	g := safety.NewGraph()
+	err := g.LoadConfig([]*config.ResourceChainConfig{
+		{Chain: []string{"vpc/vpc-1", "subnet/subnet-a", "instance/i-1"}},
+		{Chain: []string{"subnet/subnet-a", "instance/i-2"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	vpc, ok := g.Find("vpc", "vpc-1")
+	if !ok {
+		t.Fatal("expected vpc/vpc-1 in the graph")
+	}
+	if got := g.Downstream(vpc); len(got) != 3 {
+		t.Errorf("expected subnet and both instances downstream, got %v", got)
+	}
+
+	for _, chain := range [][]string{{"vpc/vpc-1"}, {"vpc/vpc-1", "subnet"}} {
+		if err := safety.NewGraph().LoadConfig([]*config.ResourceChainConfig{{Chain: chain}}); err == nil {
+			t.Errorf("%v: expected an invalid chain error", chain)
+		}
+	}
+}
+
 func containsStr(s, substr string) bool {
 	return len(s) >= len(substr) && contains(s, substr)
 }
@@ -84,22 +84,21 @@ func AWSSkills() []*core.Skill {
 		// ── Storage ──────────────────────────────────────────
 		{
 			Name:        "aws.s3.audit",
-			Description: "Audit S3 buckets for public access, encryption, and versioning",
+			Description: "Audit S3 bucket encryption, access logging and versioning as recorded by AWS Config",
 			Provider:    core.ProviderAWS,
 			Category:    core.CategoryStorage,
 			Inputs: []core.SkillInput{
-				{Name: "bucket_name", Type: "string", Required: false, Description: "Specific bucket to audit (all if omitted)"},
-				{Name: "region", Type: "string", Required: false, Description: "AWS region", Default: "us-east-1"},
+				{Name: "region", Type: "string", Required: false, Description: "AWS region whose Config recorder to query", Default: "us-east-1"},
 			},
 			Outputs: []core.SkillOutput{
-				{Name: "findings", Type: "list", Description: "Security findings per bucket"},
-				{Name: "buckets_scanned", Type: "int", Description: "Number of buckets audited"},
+				{Name: "Results", Type: "list", Description: "Encryption, logging and versioning configuration per bucket"},
 			},
 			RiskLevel:            core.RiskLow,
 			RequiresConfirmation: false,
+			ReadOnly:             true,
 			Execution: core.ExecutionConfig{
 				Type:    core.ExecCLI,
-				Command: "aws s3api get-bucket-acl && get-bucket-encryption && get-bucket-versioning",
+				Command: `aws configservice select-resource-config --region {region} --output json --expression "SELECT resourceName, supplementaryConfiguration.ServerSideEncryptionConfiguration, supplementaryConfiguration.BucketLoggingConfiguration, supplementaryConfiguration.BucketVersioningConfiguration WHERE resourceType = 'AWS::S3::Bucket'"`,
 				Timeout: 60 * time.Second,
 			},
 			Rollback: core.RollbackConfig{Supported: false, Procedure: "Read-only operation"},
@@ -128,6 +127,59 @@ func AWSSkills() []*core.Skill {
 			Rollback: core.RollbackConfig{Supported: false, Procedure: "Reverse sync from destination back to source"},
 		},
 
+		{
+			Name:        "aws.s3.encrypt",
+			Description: "Enable S3 default encryption on a bucket",
+			Provider:    core.ProviderAWS,
+			Category:    core.CategoryStorage,
+			Inputs: []core.SkillInput{
+				{Name: "bucket_name", Type: "string", Required: true, Description: "Bucket to encrypt"},
+				{Name: "algorithm", Type: "string", Required: false, Description: "AES256 or aws:kms", Default: "AES256"},
+				{Name: "kms_key_id", Type: "string", Required: false, Description: "KMS key ARN when algorithm is aws:kms"},
+			},
+			Outputs: []core.SkillOutput{
+				{Name: "encryption", Type: "object", Description: "Applied server-side encryption configuration"},
+			},
+			RiskLevel:            core.RiskMedium,
+			RequiresConfirmation: true,
+			Execution: core.ExecutionConfig{
+				Type:    core.ExecCLI,
+				Command: "aws s3api put-bucket-encryption --server-side-encryption-configuration",
+				Timeout: 30 * time.Second,
+			},
+			Rollback: core.RollbackConfig{
+				Supported: true,
+				Procedure: "Remove default encryption with aws s3api delete-bucket-encryption",
+				Command:   "aws s3api delete-bucket-encryption --bucket {bucket_name}",
+				Params:    []string{"bucket_name"},
+			},
+		},
+		{
+			Name:        "aws.s3.versioning",
+			Description: "Enable versioning on an S3 bucket",
+			Provider:    core.ProviderAWS,
+			Category:    core.CategoryStorage,
+			Inputs: []core.SkillInput{
+				{Name: "bucket_name", Type: "string", Required: true, Description: "Bucket to version"},
+			},
+			Outputs: []core.SkillOutput{
+				{Name: "status", Type: "string", Description: "Versioning status after the change"},
+			},
+			RiskLevel:            core.RiskMedium,
+			RequiresConfirmation: true,
+			Execution: core.ExecutionConfig{
+				Type:    core.ExecCLI,
+				Command: "aws s3api put-bucket-versioning --versioning-configuration Status=Enabled",
+				Timeout: 30 * time.Second,
+			},
+			Rollback: core.RollbackConfig{
+				Supported: true,
+				Procedure: "Suspend versioning: aws s3api put-bucket-versioning --versioning-configuration Status=Suspended",
+				Command:   "aws s3api put-bucket-versioning --bucket {bucket_name} --versioning-configuration Status=Suspended",
+				Params:    []string{"bucket_name"},
+			},
+		},
+
 		// ── Networking ───────────────────────────────────────
 		{
 			Name:        "aws.vpc.inspect",
@@ -165,32 +217,54 @@ func AWSSkills() []*core.Skill {
 			},
 			RiskLevel:            core.RiskLow,
 			RequiresConfirmation: false,
+			ReadOnly:             true,
 			Execution: core.ExecutionConfig{
 				Type:    core.ExecCLI,
-				Command: "aws ec2 describe-security-groups",
+				Command: "aws ec2 describe-security-groups --region {region} --output json",
 				Timeout: 30 * time.Second,
 			},
 			Rollback: core.RollbackConfig{Supported: false, Procedure: "Read-only operation"},
 		},
 
+		{
+			Name:        "aws.vpc.flowlogs",
+			Description: "Enable VPC flow logs to CloudWatch Logs",
+			Provider:    core.ProviderAWS,
+			Category:    core.CategoryNetworking,
+			Inputs: []core.SkillInput{
+				{Name: "vpc_id", Type: "string", Required: true, Description: "VPC ID"},
+				{Name: "traffic_type", Type: "string", Required: false, Description: "ACCEPT, REJECT, or ALL", Default: "ALL"},
+				{Name: "log_group", Type: "string", Required: false, Description: "Destination log group", Default: "vpc-flow-logs"},
+				{Name: "region", Type: "string", Required: false, Description: "AWS region", Default: "us-east-1"},
+			},
+			Outputs: []core.SkillOutput{
+				{Name: "flow_log_id", Type: "string", Description: "Created flow log ID"},
+			},
+			RiskLevel:            core.RiskMedium,
+			RequiresConfirmation: true,
+			Execution: core.ExecutionConfig{
+				Type:    core.ExecCLI,
+				Command: "aws ec2 create-flow-logs --resource-type VPC",
+				Timeout: 30 * time.Second,
+			},
+			Rollback: core.RollbackConfig{Supported: true, Procedure: "Delete the created flow log with aws ec2 delete-flow-logs --flow-log-ids"},
+		},
+
 		// ── Security ─────────────────────────────────────────
 		{
 			Name:        "aws.iam.audit",
-			Description: "Audit IAM roles, policies, and unused permissions",
+			Description: "Fetch the IAM credential report (generated beforehand with aws iam generate-credential-report)",
 			Provider:    core.ProviderAWS,
 			Category:    core.CategorySecurity,
-			Inputs: []core.SkillInput{
-				{Name: "max_age_days", Type: "int", Required: false, Description: "Flag credentials unused for N days", Default: "90"},
-			},
 			Outputs: []core.SkillOutput{
-				{Name: "unused_roles", Type: "list", Description: "IAM roles with no recent activity"},
-				{Name: "overprivileged_policies", Type: "list", Description: "Policies with excessive permissions"},
+				{Name: "Content", Type: "string", Description: "Base64-encoded credential report CSV"},
 			},
 			RiskLevel:            core.RiskLow,
 			RequiresConfirmation: false,
+			ReadOnly:             true,
 			Execution: core.ExecutionConfig{
 				Type:    core.ExecCLI,
-				Command: "aws iam get-credential-report && list-roles && list-policies",
+				Command: "aws iam get-credential-report --output json",
 				Timeout: 60 * time.Second,
 			},
 			Rollback: core.RollbackConfig{Supported: false, Procedure: "Read-only operation"},
@@ -239,6 +313,32 @@ func AWSSkills() []*core.Skill {
 			Rollback: core.RollbackConfig{Supported: false, Procedure: "Read-only operation"},
 		},
 
+		{
+			Name:        "aws.cloudtrail.validation",
+			Description: "Enable CloudTrail log file validation on a trail",
+			Provider:    core.ProviderAWS,
+			Category:    core.CategorySecurity,
+			Inputs: []core.SkillInput{
+				{Name: "trail_name", Type: "string", Required: true, Description: "Trail name or ARN"},
+			},
+			Outputs: []core.SkillOutput{
+				{Name: "trail", Type: "object", Description: "Updated trail configuration"},
+			},
+			RiskLevel:            core.RiskMedium,
+			RequiresConfirmation: true,
+			Execution: core.ExecutionConfig{
+				Type:    core.ExecCLI,
+				Command: "aws cloudtrail update-trail --enable-log-file-validation",
+				Timeout: 30 * time.Second,
+			},
+			Rollback: core.RollbackConfig{
+				Supported: true,
+				Procedure: "aws cloudtrail update-trail --no-enable-log-file-validation",
+				Command:   "aws cloudtrail update-trail --name {trail_name} --no-enable-log-file-validation",
+				Params:    []string{"trail_name"},
+			},
+		},
+
 		// ── Observability ────────────────────────────────────
 		{
 			Name:        "aws.cloudwatch.query",
@@ -265,6 +365,28 @@ func AWSSkills() []*core.Skill {
 			Rollback: core.RollbackConfig{Supported: false, Procedure: "Read-only operation"},
 		},
 
+		{
+			Name:        "aws.logs.retention",
+			Description: "Set the retention period of a CloudWatch log group",
+			Provider:    core.ProviderAWS,
+			Category:    core.CategoryObservability,
+			Inputs: []core.SkillInput{
+				{Name: "log_group", Type: "string", Required: true, Description: "CloudWatch Log Group name"},
+				{Name: "retention_days", Type: "int", Required: false, Description: "Days to retain log events", Default: "365"},
+			},
+			Outputs: []core.SkillOutput{
+				{Name: "retention_days", Type: "int", Description: "Retention applied"},
+			},
+			RiskLevel:            core.RiskMedium,
+			RequiresConfirmation: true,
+			Execution: core.ExecutionConfig{
+				Type:    core.ExecCLI,
+				Command: "aws logs put-retention-policy",
+				Timeout: 30 * time.Second,
+			},
+			Rollback: core.RollbackConfig{Supported: true, Procedure: "Restore the previous retention with aws logs put-retention-policy"},
+		},
+
 		// ── Cost Management ──────────────────────────────────
 		{
 			Name:        "aws.cost.report",
@@ -34,6 +34,8 @@ func GCPSkills() []*core.Skill {
 			Rollback: core.RollbackConfig{
 				Supported: true,
 				Procedure: "gcloud compute snapshots delete {snapshot_name}",
+				Command:   "gcloud compute snapshots delete {snapshot_name} --quiet",
+				Params:    []string{"snapshot_name"},
 			},
 		},
 		{
@@ -43,6 +43,7 @@ func IaCSkills() []*core.Skill {
 				{Name: "working_dir", Type: "string", Required: true, Description: "Terraform working directory"},
 				{Name: "var_file", Type: "string", Required: false, Description: "Path to .tfvars file"},
 				{Name: "auto_approve", Type: "bool", Required: false, Description: "Skip interactive approval"},
+				{Name: "plan_file", Type: "string", Required: false, Description: "Plan JSON (terraform show -json) checked per resource by policies"},
 			},
 			Outputs: []core.SkillOutput{
 				{Name: "apply_output", Type: "string", Description: "Terraform apply output"},
@@ -95,6 +96,7 @@ func IaCSkills() []*core.Skill {
 				{Name: "namespace", Type: "string", Required: true, Description: "Kubernetes namespace"},
 				{Name: "values_file", Type: "string", Required: false, Description: "Path to values.yaml"},
 				{Name: "version", Type: "string", Required: false, Description: "Chart version"},
+				{Name: "manifest_file", Type: "string", Required: false, Description: "Rendered manifests (helm template output) checked by policies"},
 			},
 			Outputs: []core.SkillOutput{
 				{Name: "status", Type: "string", Description: "Release status"},
@@ -110,6 +112,8 @@ func IaCSkills() []*core.Skill {
 			Rollback: core.RollbackConfig{
 				Supported: true,
 				Procedure: "helm rollback {release} {previous_revision} -n {namespace}",
+				Command:   "helm rollback {release_name} -n {namespace}",
+				Params:    []string{"release_name", "namespace"},
 			},
 		},
 		{
@@ -136,6 +140,8 @@ func IaCSkills() []*core.Skill {
 			Rollback: core.RollbackConfig{
 				Supported: true,
 				Procedure: "argocd app rollback {app_name} to previous revision",
+				Command:   "argocd app rollback {app_name}",
+				Params:    []string{"app_name"},
 			},
 		},
 	}
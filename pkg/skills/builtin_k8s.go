@@ -20,6 +20,7 @@ func KubernetesSkills() []*core.Skill {
 				{Name: "image", Type: "string", Required: true, Description: "Container image with tag"},
 				{Name: "replicas", Type: "int", Required: false, Description: "Number of replicas"},
 				{Name: "context", Type: "string", Required: false, Description: "kubectl context to use"},
+				{Name: "manifest_file", Type: "string", Required: false, Description: "Rendered manifest YAML (file or directory) checked by policies"},
 			},
 			Outputs: []core.SkillOutput{
 				{Name: "status", Type: "string", Description: "Rollout status"},
@@ -35,6 +36,8 @@ func KubernetesSkills() []*core.Skill {
 			Rollback: core.RollbackConfig{
 				Supported: true,
 				Procedure: "kubectl rollout undo deployment/{name} -n {namespace}",
+				Command:   "kubectl rollout undo deployment/{deployment} -n {namespace}",
+				Params:    []string{"deployment", "namespace"},
 			},
 		},
 		{
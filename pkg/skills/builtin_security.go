@@ -50,6 +50,7 @@ func SecuritySkills() []*core.Skill {
 			},
 			RiskLevel:            core.RiskLow,
 			RequiresConfirmation: false,
+			ReadOnly:             true,
 			Execution: core.ExecutionConfig{
 				Type:    core.ExecCLI,
 				Command: "trivy image --severity {severity} --format {format} {image}",
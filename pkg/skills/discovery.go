@@ -25,6 +25,7 @@ type SkillDefinition struct {
 	Inputs      []SkillInputDef       `yaml:"inputs"`
 	Outputs     []SkillOutputDef      `yaml:"outputs"`
 	RiskLevel   string                `yaml:"risk_level"`
+	ReadOnly    bool                  `yaml:"read_only,omitempty"`
 	Execution   SkillExecutionDef     `yaml:"execution"`
 	Rollback    SkillRollbackDef      `yaml:"rollback"`
 }
@@ -53,8 +54,10 @@ type SkillExecutionDef struct {
 
 // SkillRollbackDef defines the rollback config in YAML format.
 type SkillRollbackDef struct {
-	Supported bool   `yaml:"supported"`
-	Procedure string `yaml:"procedure"`
+	Supported bool     `yaml:"supported"`
+	Procedure string   `yaml:"procedure"`
+	Command   string   `yaml:"command,omitempty"`
+	Params    []string `yaml:"params,omitempty"`
 }
 
 // CreateSkill creates a core.Skill from a SkillDefinition and registers it.
@@ -95,6 +98,7 @@ func (d *Discovery) CreateSkill(def *SkillDefinition) (*core.Skill, error) {
 		Outputs:              outputs,
 		RiskLevel:            riskLevel,
 		RequiresConfirmation: riskLevel >= core.RiskHigh,
+		ReadOnly:             def.ReadOnly,
 		Execution: core.ExecutionConfig{
 			Type:    execType,
 			Command: def.Execution.Command,
@@ -102,6 +106,8 @@ func (d *Discovery) CreateSkill(def *SkillDefinition) (*core.Skill, error) {
 		Rollback: core.RollbackConfig{
 			Supported: def.Rollback.Supported,
 			Procedure: def.Rollback.Procedure,
+			Command:   def.Rollback.Command,
+			Params:    def.Rollback.Params,
 		},
 	}
 
@@ -132,6 +138,10 @@ func (d *Discovery) Validate(def *SkillDefinition) error {
 	if def.Execution.Command == "" {
 		return fmt.Errorf("skill execution command is required")
 	}
+	rollback := core.RollbackConfig{Command: def.Rollback.Command, Params: def.Rollback.Params}
+	if err := rollback.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -158,5 +168,7 @@ func (d *Discovery) GenerateTemplate(provider, action string) string {
   rollback:
     supported: false
     procedure: "How to undo this action"
+    # command: "command that undoes it, e.g. tool revert {param_name}"
+    # params: [param_name]
 `, provider, action, provider)
 }
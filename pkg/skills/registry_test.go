@@ -129,4 +129,20 @@ func TestLoadBuiltins(t *testing.T) {
 			t.Errorf("expected built-in skill %s to exist: %v", name, err)
 		}
 	}
+
+	// Rollback commands only use declared params, and those are inputs.
+	for _, skill := range r.List() {
+		if err := skill.Rollback.Validate(); err != nil {
+			t.Errorf("%s: %v", skill.Name, err)
+		}
+		for _, p := range skill.Rollback.Params {
+			found := false
+			for _, in := range skill.Inputs {
+				found = found || in.Name == p
+			}
+			if !found {
+				t.Errorf("%s: rollback param %s is not a skill input", skill.Name, p)
+			}
+		}
+	}
 }